@@ -11,12 +11,17 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/publishqueue"
+	"github.com/xpzouying/xiaohongshu-mcp/watchfolder"
 )
 
 // AppServer 应用服务器结构体，封装所有服务和处理器
 type AppServer struct {
 	xiaohongshuService *XiaohongshuService
 	cailiansheService  *CailiansheService
+	publishQueue       *publishqueue.Queue
+	folderWatcher      *watchfolder.Watcher // 未配置监听目录时为 nil
 	mcpServer          *mcp.Server
 	router             *gin.Engine
 	httpServer         *http.Server
@@ -29,6 +34,23 @@ func NewAppServer(xiaohongshuService *XiaohongshuService, cailiansheService *Cai
 		cailiansheService:  cailiansheService,
 	}
 
+	// 发布队列：持久化待发布任务，按计划时间执行
+	appServer.publishQueue = publishqueue.NewQueue(
+		publishqueue.GetStoreFilePath(),
+		xiaohongshuService.ExecutePublishJob,
+		publishqueue.Options{},
+	)
+
+	// 自动发布：监听目录中复制完成的笔记文件夹加入发布队列
+	if dir := configs.GetWatchDir(); dir != "" {
+		watcher, err := watchfolder.NewWatcher(dir, appServer.enqueueFolder, appServer.publishQueue, watchfolder.Options{})
+		if err != nil {
+			logrus.Warnf("自动发布未启用: %v", err)
+		} else {
+			appServer.folderWatcher = watcher
+		}
+	}
+
 	// 初始化 MCP Server（需要在创建 appServer 之后，因为工具注册需要访问 appServer）
 	appServer.mcpServer = InitMCPServer(appServer)
 
@@ -39,6 +61,11 @@ func NewAppServer(xiaohongshuService *XiaohongshuService, cailiansheService *Cai
 func (s *AppServer) Start(port string) error {
 	s.router = setupRoutes(s)
 
+	s.publishQueue.Start()
+	if s.folderWatcher != nil {
+		s.folderWatcher.Start()
+	}
+
 	s.httpServer = &http.Server{
 		Addr:    port,
 		Handler: s.router,
@@ -60,6 +87,11 @@ func (s *AppServer) Start(port string) error {
 
 	logrus.Infof("正在关闭服务器...")
 
+	if s.folderWatcher != nil {
+		s.folderWatcher.Stop()
+	}
+	s.publishQueue.Stop()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
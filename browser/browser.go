@@ -32,15 +32,23 @@ func NewBrowser(headless bool, options ...Option) *headless_browser.Browser {
 	}
 
 	// 加载 cookies
+	if data := loadCookies(); data != "" {
+		opts = append(opts, headless_browser.WithCookies(data))
+	}
+
+	return headless_browser.New(opts...)
+}
+
+// loadCookies 读取保存的 cookies，读取失败时返回空字符串
+func loadCookies() string {
 	cookiePath := cookies.GetCookiesFilePath()
 	cookieLoader := cookies.NewLoadCookie(cookiePath)
 
-	if data, err := cookieLoader.LoadCookies(); err == nil {
-		opts = append(opts, headless_browser.WithCookies(string(data)))
-		logrus.Debugf("loaded cookies from filesuccessfully")
-	} else {
+	data, err := cookieLoader.LoadCookies()
+	if err != nil {
 		logrus.Warnf("failed to load cookies: %v", err)
+		return ""
 	}
-
-	return headless_browser.New(opts...)
+	logrus.Debugf("loaded cookies from filesuccessfully")
+	return string(data)
 }
@@ -0,0 +1,79 @@
+package browser
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/stealth"
+	"github.com/sirupsen/logrus"
+)
+
+// profileUserAgent 与 headless_browser 默认的 User-Agent 保持一致
+const profileUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+// profileMu 同一个用户目录同时只能被一个浏览器进程使用
+var profileMu sync.Mutex
+
+// ProfileBrowser 使用固定用户目录的浏览器，关闭后保留用户目录中的本地存储（如网页版草稿箱）
+type ProfileBrowser struct {
+	browser  *rod.Browser
+	launcher *launcher.Launcher
+}
+
+// NewProfileBrowser 使用用户目录 dir 启动浏览器。同一时间只有一个实例，Close 之前其他调用会等待
+func NewProfileBrowser(headless bool, dir string, options ...Option) *ProfileBrowser {
+	cfg := &browserConfig{}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	profileMu.Lock()
+	launched := false
+	defer func() {
+		if !launched {
+			profileMu.Unlock()
+		}
+	}()
+
+	l := launcher.New().
+		UserDataDir(dir).
+		Headless(headless).
+		Set("--no-sandbox").
+		Set("user-agent", profileUserAgent)
+	if cfg.binPath != "" {
+		l = l.Bin(cfg.binPath)
+	}
+
+	b := rod.New().ControlURL(l.MustLaunch()).MustConnect()
+
+	if data := loadCookies(); data != "" {
+		var cks []*proto.NetworkCookie
+		if err := json.Unmarshal([]byte(data), &cks); err != nil {
+			logrus.Warnf("failed to unmarshal cookies: %v", err)
+		} else {
+			b.MustSetCookies(cks...)
+		}
+	}
+
+	launched = true
+	return &ProfileBrowser{browser: b, launcher: l}
+}
+
+// NewPage 创建启用 stealth 的页面
+func (b *ProfileBrowser) NewPage() *rod.Page {
+	return stealth.MustPage(b.browser)
+}
+
+// Close 关闭浏览器并等待进程退出，保留用户目录
+func (b *ProfileBrowser) Close() {
+	defer profileMu.Unlock()
+
+	b.browser.MustClose()
+
+	// Cleanup 会删除用户目录，先去掉用户目录参数，只等待进程退出
+	b.launcher.UserDataDir("")
+	b.launcher.Cleanup()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// runPublishMarkdown 命令行发布 Markdown 文件：
+//
+//	xiaohongshu-mcp publish-markdown [-draft] [-dry-run] note.md
+func runPublishMarkdown(args []string) {
+	fs := flag.NewFlagSet("publish-markdown", flag.ExitOnError)
+
+	var (
+		headless bool
+		binPath  string
+		req      PublishMarkdownRequest
+	)
+	fs.BoolVar(&headless, "headless", true, "是否无头模式")
+	fs.StringVar(&binPath, "bin", "", "浏览器二进制文件路径")
+	fs.BoolVar(&req.Draft, "draft", false, "仅保存到草稿箱不发布")
+	fs.BoolVar(&req.DryRun, "dry-run", false, "只解析和校验，输出转换结果，不发布")
+	fs.StringVar(&req.IdempotencyKey, "idempotency-key", "", "幂等键，重试时使用相同的值避免重复发布")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "用法: %s publish-markdown [选项] <file.md>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	req.FilePath = fs.Arg(0)
+
+	if len(binPath) == 0 {
+		binPath = os.Getenv("ROD_BROWSER_BIN")
+	}
+	configs.InitHeadless(headless)
+	configs.SetBinPath(binPath)
+
+	result, err := NewXiaohongshuService().PublishMarkdown(context.Background(), &req)
+	if err != nil {
+		logrus.Fatalf("发布 Markdown 失败: %v", err)
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		logrus.Fatalf("failed to marshal result: %v", err)
+	}
+	fmt.Println(string(data))
+
+	if result.Lint != nil && !result.Lint.Passed {
+		os.Exit(1)
+	}
+}
@@ -1,5 +1,7 @@
 package configs
 
+import "os"
+
 var (
 	useHeadless = true
 
@@ -22,3 +24,13 @@ func SetBinPath(b string) {
 func GetBinPath() string {
 	return binPath
 }
+
+// GetProfileDir 浏览器用户目录。网页版草稿箱保存在浏览器本地，草稿相关操作使用固定的用户目录，
+// 使保存的草稿在之后的调用中仍然可见
+func GetProfileDir() string {
+	dir := os.Getenv("BROWSER_PROFILE_DIR")
+	if dir == "" {
+		dir = "browser_profile" // 与 cookies.json 相同，默认使用当前目录
+	}
+	return dir
+}
@@ -3,12 +3,35 @@ package configs
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 )
 
 const (
 	ImagesDir = "xiaohongshu_images"
+
+	defaultImagesCacheTTL   = 24 * time.Hour
+	defaultImagesCacheMaxMB = 1024
 )
 
 func GetImagesPath() string {
 	return filepath.Join(os.TempDir(), ImagesDir)
 }
+
+// GetImagesCacheTTL 图片缓存保留时长，超过该时长未使用的图片会被清理。
+// 通过环境变量 IMAGES_CACHE_TTL 设置，如 72h，默认 24h。
+func GetImagesCacheTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("IMAGES_CACHE_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return defaultImagesCacheTTL
+}
+
+// GetImagesCacheMaxBytes 图片缓存大小上限，超过时优先清理最久未使用的图片。
+// 通过环境变量 IMAGES_CACHE_MAX_MB 设置，默认 1024MB。
+func GetImagesCacheMaxBytes() int64 {
+	if mb, err := strconv.ParseInt(os.Getenv("IMAGES_CACHE_MAX_MB"), 10, 64); err == nil && mb > 0 {
+		return mb << 20
+	}
+	return defaultImagesCacheMaxMB << 20
+}
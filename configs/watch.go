@@ -0,0 +1,13 @@
+package configs
+
+var watchDir = ""
+
+// SetWatchDir 设置自动发布的监听目录，为空时不启用
+func SetWatchDir(dir string) {
+	watchDir = dir
+}
+
+// GetWatchDir 自动发布的监听目录
+func GetWatchDir() string {
+	return watchDir
+}
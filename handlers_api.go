@@ -1,11 +1,18 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/sirupsen/logrus"
 )
 
@@ -66,7 +73,13 @@ func (s *AppServer) getLoginQrcodeHandler(c *gin.Context) {
 // publishHandler 发布内容
 func (s *AppServer) publishHandler(c *gin.Context) {
 	var req PublishRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var err error
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		err = bindPublishForm(c, &req)
+	} else {
+		err = c.ShouldBindJSON(&req)
+	}
+	if err != nil {
 		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
 			"请求参数错误", err.Error())
 		return
@@ -83,6 +96,75 @@ func (s *AppServer) publishHandler(c *gin.Context) {
 	respondSuccess(c, result, "发布成功")
 }
 
+// bindPublishForm 解析 multipart/form-data 发布请求。
+// 文本字段与 JSON 请求同名，images 既可以是文本（URL、本地路径、base64）也可以是上传的文件，
+// 文本图片在前，上传的文件按上传顺序在后；image_options、watermark 和 cover_collage 为 JSON 字符串。
+func bindPublishForm(c *gin.Context, req *PublishRequest) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return fmt.Errorf("解析表单失败: %w", err)
+	}
+
+	req.Title = c.PostForm("title")
+	req.Content = c.PostForm("content")
+	req.Images = c.PostFormArray("images")
+	req.Tags = splitFormList(c.PostFormArray("tags"))
+	req.PublishAt = c.PostForm("publish_at")
+	req.Draft, _ = strconv.ParseBool(c.PostForm("draft"))
+	req.Visibility = c.PostForm("visibility")
+	req.Original, _ = strconv.ParseBool(c.PostForm("original"))
+	req.Declaration = c.PostForm("declaration")
+	req.Mentions = splitFormList(c.PostFormArray("mentions"))
+	req.Location = c.PostForm("location")
+	req.IdempotencyKey = c.PostForm("idempotency_key")
+	for field, target := range map[string]any{
+		"image_options": &req.ImageOptions,
+		"watermark":     &req.Watermark,
+		"cover_collage": &req.CoverCollage,
+	} {
+		if value := c.PostForm(field); value != "" {
+			if err := json.Unmarshal([]byte(value), target); err != nil {
+				return fmt.Errorf("%s 格式错误: %w", field, err)
+			}
+		}
+	}
+
+	// 上传的图片校验文件类型后保存到图片目录
+	processor := downloader.NewImageProcessor()
+	for _, fh := range form.File["images"] {
+		path, err := saveUploadedImage(processor, fh)
+		if err != nil {
+			return fmt.Errorf("保存上传图片 %s 失败: %w", fh.Filename, err)
+		}
+		req.Images = append(req.Images, path)
+	}
+
+	return binding.Validator.ValidateStruct(req)
+}
+
+func saveUploadedImage(processor *downloader.ImageProcessor, fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return processor.SaveImage(f)
+}
+
+// splitFormList 表单中的列表字段可以重复传入，也可以用逗号分隔
+func splitFormList(values []string) []string {
+	var items []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
 // publishVideoHandler 发布视频内容
 func (s *AppServer) publishVideoHandler(c *gin.Context) {
 	var req PublishVideoRequest
@@ -0,0 +1,315 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 发布记录状态
+const (
+	StatusInProgress = "in_progress"
+	StatusSucceeded  = "succeeded"
+	StatusFailed     = "failed"
+	// StatusUnconfirmed 已点击发布但未确认结果，笔记可能已经发布
+	StatusUnconfirmed = "unconfirmed"
+)
+
+// DefaultWindow 去重窗口，窗口内重复的发布请求直接返回首次的结果
+const DefaultWindow = 24 * time.Hour
+
+// inProgressTimeout 发布中的记录超过该时长未完成，视为上次发布已中断（如进程崩溃）。
+// 中断时可能已经点击发布，按未确认处理，不允许直接重新发布
+const inProgressTimeout = 30 * time.Minute
+
+var (
+	ErrInProgress  = errors.New("相同内容正在发布中，请稍后再试或查看发布结果")
+	ErrKeyConflict = errors.New("idempotency_key 已用于不同的内容")
+	ErrUnconfirmed = errors.New("相同内容上次点击发布后未确认结果，笔记可能已发布。请先在创作中心确认，确认未发布后传入新的 idempotency_key 重新发布")
+)
+
+// Record 一次发布请求的记录
+type Record struct {
+	Key         string          `json:"key,omitempty"` // 客户端提供的幂等键
+	Fingerprint string          `json:"fingerprint"`
+	Status      string          `json:"status"`
+	Result      json.RawMessage `json:"result,omitempty"` // 成功时的发布结果
+	Error       string          `json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Store 持久化的发布记录。提供幂等键时按幂等键去重，否则按内容指纹去重。
+type Store struct {
+	path    string
+	window  time.Duration
+	mu      sync.Mutex
+	records map[string]*Record
+}
+
+// NewStore 创建发布记录，并从 path 加载已有记录
+func NewStore(path string, window time.Duration) *Store {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+
+	s := &Store{
+		path:    path,
+		window:  window,
+		records: make(map[string]*Record),
+	}
+
+	if err := s.load(); err != nil {
+		logrus.Warnf("failed to load publish history: %v", err)
+	}
+
+	return s
+}
+
+// Begin 开始一次发布。窗口内已有相同请求成功发布时返回该记录，调用方应直接返回其结果；
+// 相同请求正在发布时返回 ErrInProgress；上次发布结果未确认或发布中途中断时返回 ErrUnconfirmed；
+// 否则记录为发布中并返回 nil。
+//
+// 未提供幂等键时，窗口内任何相同内容的未确认记录都会阻止发布；
+// 调用方确认笔记未发布后，可以传入新的幂等键重新发布。
+func (s *Store) Begin(key, fingerprint string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := recordID(key, fingerprint)
+	now := time.Now()
+
+	if r, ok := s.records[id]; ok && now.Sub(r.CreatedAt) <= s.window {
+		if r.Fingerprint != fingerprint {
+			return nil, ErrKeyConflict
+		}
+
+		switch r.Status {
+		case StatusSucceeded:
+			existing := *r
+			return &existing, nil
+		case StatusInProgress:
+			if now.Sub(r.UpdatedAt) < inProgressTimeout {
+				return nil, ErrInProgress
+			}
+			return nil, ErrUnconfirmed
+		case StatusUnconfirmed:
+			return nil, ErrUnconfirmed
+		}
+	}
+
+	if key == "" {
+		for _, r := range s.records {
+			if r.Fingerprint == fingerprint && r.unconfirmed(now) && now.Sub(r.CreatedAt) <= s.window {
+				return nil, ErrUnconfirmed
+			}
+		}
+	}
+
+	s.records[id] = &Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      StatusInProgress,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.persist()
+
+	return nil, nil
+}
+
+// unconfirmed 是否未确认发布结果：点击发布后未确认，或发布中途中断
+func (r *Record) unconfirmed(now time.Time) bool {
+	return r.Status == StatusUnconfirmed || (r.Status == StatusInProgress && now.Sub(r.UpdatedAt) >= inProgressTimeout)
+}
+
+// Succeed 记录发布成功及其结果
+func (s *Store) Succeed(key, fingerprint string, result any) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		logrus.Warnf("failed to marshal publish result: %v", err)
+	}
+
+	s.finish(key, fingerprint, func(r *Record) {
+		r.Status = StatusSucceeded
+		r.Result = data
+		r.Error = ""
+	})
+}
+
+// Fail 记录发布失败，之后相同的请求可以重新发布。只用于点击发布之前的失败
+func (s *Store) Fail(key, fingerprint string, err error) {
+	s.finish(key, fingerprint, func(r *Record) {
+		r.Status = StatusFailed
+		if err != nil {
+			r.Error = err.Error()
+		}
+	})
+}
+
+// Unconfirmed 记录已点击发布但未确认结果，窗口内相同的请求不会再次发布
+func (s *Store) Unconfirmed(key, fingerprint string, err error) {
+	s.finish(key, fingerprint, func(r *Record) {
+		r.Status = StatusUnconfirmed
+		if err != nil {
+			r.Error = err.Error()
+		}
+	})
+}
+
+func (s *Store) finish(key, fingerprint string, update func(r *Record)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[recordID(key, fingerprint)]
+	if !ok || r.Fingerprint != fingerprint {
+		return
+	}
+
+	update(r)
+	r.UpdatedAt = time.Now()
+	s.persist()
+}
+
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to read publish history file")
+	}
+
+	var records []*Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return errors.Wrap(err, "failed to unmarshal publish history")
+	}
+
+	for _, r := range records {
+		s.records[recordID(r.Key, r.Fingerprint)] = r
+	}
+
+	return nil
+}
+
+// persist 写入文件，调用方需持有锁
+func (s *Store) persist() {
+	if err := s.save(); err != nil {
+		logrus.Warnf("failed to save publish history: %v", err)
+	}
+}
+
+// save 写入文件，调用方需持有锁。超出窗口的记录在写入时清理。
+func (s *Store) save() error {
+	records := make([]*Record, 0, len(s.records))
+	for id, r := range s.records {
+		if time.Since(r.CreatedAt) > s.window {
+			delete(s.records, id)
+			continue
+		}
+		records = append(records, r)
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// recordID 有幂等键时按幂等键索引，否则按内容指纹索引
+func recordID(key, fingerprint string) string {
+	if key != "" {
+		return "key:" + key
+	}
+	return "fp:" + fingerprint
+}
+
+// Content 计算指纹的发布内容
+type Content struct {
+	Kind    string // image/video
+	Title   string
+	Content string
+	Tags    []string
+	Files   []string // 图片、视频或封面文件，按文件内容计算哈希
+	Extra   []string // 其他影响发布结果的参数，如定时发布时间、是否草稿
+}
+
+// Fingerprint 计算发布内容的指纹。标签忽略顺序、大小写和 # 前缀，文件按内容计算哈希。
+func Fingerprint(c Content) (string, error) {
+	tags := make([]string, 0, len(c.Tags))
+	for _, tag := range c.Tags {
+		tags = append(tags, strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#")))
+	}
+	sort.Strings(tags)
+
+	files := make([]string, 0, len(c.Files))
+	for _, path := range c.Files {
+		sum, err := hashFile(path)
+		if err != nil {
+			return "", err
+		}
+		files = append(files, sum)
+	}
+
+	data, err := json.Marshal([]any{
+		c.Kind,
+		strings.TrimSpace(c.Title),
+		strings.TrimSpace(c.Content),
+		tags,
+		files,
+		c.Extra,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// hashFile 计算文件内容的 SHA256
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open %s", path)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errors.Wrapf(err, "failed to hash %s", path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// GetStoreFilePath 获取发布记录文件路径。
+// 优先使用环境变量 PUBLISH_HISTORY_PATH，否则使用当前目录下的 publish_history.json
+func GetStoreFilePath() string {
+	path := os.Getenv("PUBLISH_HISTORY_PATH")
+	if path == "" {
+		path = "publish_history.json"
+	}
+	return path
+}
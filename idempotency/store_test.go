@@ -0,0 +1,158 @@
+package idempotency
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreDuplicate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "publish_history.json")
+	store := NewStore(path, time.Hour)
+
+	existing, err := store.Begin("", "fp1")
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	// 首次发布未完成时，重复请求被拒绝
+	_, err = store.Begin("", "fp1")
+	require.ErrorIs(t, err, ErrInProgress)
+
+	store.Succeed("", "fp1", map[string]string{"post_id": "note1"})
+
+	// 发布成功后，重复请求返回首次的结果
+	existing, err = store.Begin("", "fp1")
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	require.Equal(t, StatusSucceeded, existing.Status)
+	require.JSONEq(t, `{"post_id":"note1"}`, string(existing.Result))
+
+	// 重新加载后记录仍在
+	reloaded := NewStore(path, time.Hour)
+	existing, err = reloaded.Begin("", "fp1")
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+
+	// 不同内容不受影响
+	existing, err = store.Begin("", "fp2")
+	require.NoError(t, err)
+	require.Nil(t, existing)
+}
+
+func TestStoreFailedCanRetry(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "publish_history.json"), time.Hour)
+
+	_, err := store.Begin("", "fp1")
+	require.NoError(t, err)
+	store.Fail("", "fp1", errors.New("网络错误"))
+
+	existing, err := store.Begin("", "fp1")
+	require.NoError(t, err)
+	require.Nil(t, existing)
+}
+
+func TestStoreUnconfirmed(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "publish_history.json"), time.Hour)
+
+	_, err := store.Begin("req-1", "fp1")
+	require.NoError(t, err)
+	store.Unconfirmed("req-1", "fp1", errors.New("点击发布后超时"))
+
+	// 结果未确认时，相同的幂等键和不带幂等键的相同内容都不能再次发布
+	_, err = store.Begin("req-1", "fp1")
+	require.ErrorIs(t, err, ErrUnconfirmed)
+	_, err = store.Begin("", "fp1")
+	require.ErrorIs(t, err, ErrUnconfirmed)
+
+	// 调用方确认未发布后，使用新的幂等键重新发布
+	existing, err := store.Begin("req-2", "fp1")
+	require.NoError(t, err)
+	require.Nil(t, existing)
+}
+
+func TestStoreInterrupted(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "publish_history.json"), time.Hour)
+
+	_, err := store.Begin("req-1", "fp1")
+	require.NoError(t, err)
+
+	// 发布中途进程退出，记录长时间停留在发布中，可能已经点击发布
+	store.records[recordID("req-1", "fp1")].UpdatedAt = time.Now().Add(-inProgressTimeout)
+	_, err = store.Begin("req-1", "fp1")
+	require.ErrorIs(t, err, ErrUnconfirmed)
+	_, err = store.Begin("", "fp1")
+	require.ErrorIs(t, err, ErrUnconfirmed)
+
+	existing, err := store.Begin("req-2", "fp1")
+	require.NoError(t, err)
+	require.Nil(t, existing)
+}
+
+func TestStoreIdempotencyKey(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "publish_history.json"), time.Hour)
+
+	_, err := store.Begin("req-1", "fp1")
+	require.NoError(t, err)
+	store.Succeed("req-1", "fp1", "ok")
+
+	existing, err := store.Begin("req-1", "fp1")
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+
+	// 同一个幂等键用于不同内容
+	_, err = store.Begin("req-1", "fp2")
+	require.ErrorIs(t, err, ErrKeyConflict)
+
+	// 新的幂等键可以重新发布相同内容
+	existing, err = store.Begin("req-2", "fp1")
+	require.NoError(t, err)
+	require.Nil(t, existing)
+}
+
+func TestStoreWindow(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "publish_history.json"), time.Hour)
+
+	_, err := store.Begin("", "fp1")
+	require.NoError(t, err)
+	store.Succeed("", "fp1", "ok")
+
+	// 超出窗口的记录不再去重
+	store.records[recordID("", "fp1")].CreatedAt = time.Now().Add(-2 * time.Hour)
+	existing, err := store.Begin("", "fp1")
+	require.NoError(t, err)
+	require.Nil(t, existing)
+}
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	img1 := filepath.Join(dir, "1.jpg")
+	img2 := filepath.Join(dir, "2.jpg")
+	require.NoError(t, os.WriteFile(img1, []byte("image-1"), 0644))
+	require.NoError(t, os.WriteFile(img2, []byte("image-2"), 0644))
+
+	base := Content{Kind: "image", Title: "标题", Content: "正文", Tags: []string{"美食", "#旅行"}, Files: []string{img1}}
+	fp, err := Fingerprint(base)
+	require.NoError(t, err)
+
+	// 标签顺序、# 前缀和首尾空白不影响指纹
+	same := base
+	same.Title = " 标题 "
+	same.Tags = []string{"旅行", "美食"}
+	fp2, err := Fingerprint(same)
+	require.NoError(t, err)
+	require.Equal(t, fp, fp2)
+
+	// 图片内容不同则指纹不同
+	other := base
+	other.Files = []string{img2}
+	fp3, err := Fingerprint(other)
+	require.NoError(t, err)
+	require.NotEqual(t, fp, fp3)
+
+	_, err = Fingerprint(Content{Files: []string{filepath.Join(dir, "missing.jpg")}})
+	require.Error(t, err)
+}
@@ -9,22 +9,35 @@ import (
 )
 
 func main() {
+	// 子命令：xiaohongshu-mcp publish-markdown note.md
+	if len(os.Args) > 1 && os.Args[1] == "publish-markdown" {
+		runPublishMarkdown(os.Args[2:])
+		return
+	}
+
 	var (
 		headless bool
 		binPath  string // 浏览器二进制文件路径
 		port     string
+		watchDir string // 自动发布的监听目录
 	)
 	flag.BoolVar(&headless, "headless", true, "是否无头模式")
 	flag.StringVar(&binPath, "bin", "", "浏览器二进制文件路径")
 	flag.StringVar(&port, "port", ":18060", "端口")
+	flag.StringVar(&watchDir, "watch-dir", "", "自动发布的监听目录，为空时不启用")
 	flag.Parse()
 
 	if len(binPath) == 0 {
 		binPath = os.Getenv("ROD_BROWSER_BIN")
 	}
 
+	if len(watchDir) == 0 {
+		watchDir = os.Getenv("WATCH_DIR")
+	}
+
 	configs.InitHeadless(headless)
 	configs.SetBinPath(binPath)
+	configs.SetWatchDir(watchDir)
 
 	// 初始化服务
 	xiaohongshuService := NewXiaohongshuService()
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/imagekit"
+	"github.com/xpzouying/xiaohongshu-mcp/publishqueue"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 	"strings"
 	"time"
@@ -85,6 +88,19 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 	content, _ := args["content"].(string)
 	imagePathsInterface, _ := args["images"].([]interface{})
 	tagsInterface, _ := args["tags"].([]interface{})
+	publishAt, _ := args["publish_at"].(string)
+	draft, _ := args["draft"].(bool)
+	visibility, _ := args["visibility"].(string)
+	original, _ := args["original"].(bool)
+	declaration, _ := args["declaration"].(string)
+	mentions := convertInterfacesToStrings(args["mentions"])
+	location, _ := args["location"].(string)
+	imageAspect, _ := args["image_aspect"].(string)
+	imageFit, _ := args["image_fit"].(string)
+	imageFormat, _ := args["image_format"].(string)
+	imageMaxKB, _ := args["image_max_kb"].(int)
+	emojiCodes, _ := args["emoji_codes"].(bool)
+	idempotencyKey, _ := args["idempotency_key"].(string)
 
 	var imagePaths []string
 	for _, path := range imagePathsInterface {
@@ -104,11 +120,27 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 
 	// 构建发布请求
 	req := &PublishRequest{
-		Title:   title,
-		Content: content,
-		Images:  imagePaths,
-		Tags:    tags,
+		Title:       title,
+		Content:     content,
+		Images:      imagePaths,
+		Tags:        tags,
+		PublishAt:   publishAt,
+		Draft:       draft,
+		Visibility:  visibility,
+		Original:    original,
+		Declaration: declaration,
+		Mentions:    mentions,
+		Location:    location,
+		EmojiCodes:  emojiCodes,
+		ImageOptions: downloader.PreprocessOptions{
+			Aspect:   imageAspect,
+			Fit:      imageFit,
+			Format:   imageFormat,
+			MaxBytes: int64(imageMaxKB) * 1024,
+		},
+		IdempotencyKey: idempotencyKey,
 	}
+	req.CoverCollage, req.Watermark = composeOptionsFromArgs(args)
 
 	// 执行发布
 	result, err := s.xiaohongshuService.PublishContent(ctx, req)
@@ -131,14 +163,25 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 	}
 }
 
-// handlePublishVideo 处理发布视频内容（仅本地单个视频文件）
+// handlePublishVideo 处理发布视频内容（单个视频文件，本地路径或链接）
 func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]interface{}) *MCPToolResult {
-	logrus.Info("MCP: 发布视频内容（本地）")
+	logrus.Info("MCP: 发布视频内容")
 
 	title, _ := args["title"].(string)
 	content, _ := args["content"].(string)
 	videoPath, _ := args["video"].(string)
 	tagsInterface, _ := args["tags"].([]interface{})
+	publishAt, _ := args["publish_at"].(string)
+	draft, _ := args["draft"].(bool)
+	visibility, _ := args["visibility"].(string)
+	original, _ := args["original"].(bool)
+	declaration, _ := args["declaration"].(string)
+	mentions := convertInterfacesToStrings(args["mentions"])
+	location, _ := args["location"].(string)
+	coverTime, _ := args["cover_time"].(string)
+	coverImage, _ := args["cover_image"].(string)
+	emojiCodes, _ := args["emoji_codes"].(bool)
+	idempotencyKey, _ := args["idempotency_key"].(string)
 
 	var tags []string
 	for _, tag := range tagsInterface {
@@ -151,7 +194,7 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "发布失败: 缺少本地视频文件路径",
+				Text: "发布失败: 缺少视频文件路径或链接",
 			}},
 			IsError: true,
 		}
@@ -161,10 +204,22 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 
 	// 构建发布请求
 	req := &PublishVideoRequest{
-		Title:   title,
-		Content: content,
-		Video:   videoPath,
-		Tags:    tags,
+		Title:       title,
+		Content:     content,
+		Video:       videoPath,
+		Tags:        tags,
+		PublishAt:   publishAt,
+		Draft:       draft,
+		Visibility:  visibility,
+		Original:    original,
+		Declaration: declaration,
+		Mentions:    mentions,
+		Location:    location,
+		CoverTime:   coverTime,
+		CoverImage:  coverImage,
+		EmojiCodes:  emojiCodes,
+
+		IdempotencyKey: idempotencyKey,
 	}
 
 	// 执行发布
@@ -284,23 +339,12 @@ func (s *AppServer) handleGetFeedDetail(ctx context.Context, args map[string]any
 	logrus.Info("MCP: 获取Feed详情")
 
 	// 解析参数
-	feedID, ok := args["feed_id"].(string)
-	if !ok || feedID == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feed详情失败: 缺少feed_id参数",
-			}},
-			IsError: true,
-		}
-	}
-
-	xsecToken, ok := args["xsec_token"].(string)
-	if !ok || xsecToken == "" {
+	feedID, xsecToken, err := s.resolveFeedArgs(ctx, args)
+	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "获取Feed详情失败: 缺少xsec_token参数",
+				Text: "获取Feed详情失败: " + err.Error(),
 			}},
 			IsError: true,
 		}
@@ -355,16 +399,8 @@ func (s *AppServer) handleUserProfile(ctx context.Context, args map[string]any)
 		}
 	}
 
-	xsecToken, ok := args["xsec_token"].(string)
-	if !ok || xsecToken == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取用户主页失败: 缺少xsec_token参数",
-			}},
-			IsError: true,
-		}
-	}
+	// xsec_token 可选，未提供时使用令牌缓存
+	xsecToken, _ := args["xsec_token"].(string)
 
 	logrus.Infof("MCP: 获取用户主页 - User ID: %s", userID)
 
@@ -401,18 +437,13 @@ func (s *AppServer) handleUserProfile(ctx context.Context, args map[string]any)
 
 // handleLikeFeed 处理点赞/取消点赞
 func (s *AppServer) handleLikeFeed(ctx context.Context, args map[string]interface{}) *MCPToolResult {
-	feedID, ok := args["feed_id"].(string)
-	if !ok || feedID == "" {
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: 缺少feed_id参数"}}, IsError: true}
-	}
-	xsecToken, ok := args["xsec_token"].(string)
-	if !ok || xsecToken == "" {
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: 缺少xsec_token参数"}}, IsError: true}
+	feedID, xsecToken, err := s.resolveFeedArgs(ctx, args)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: " + err.Error()}}, IsError: true}
 	}
 	unlike, _ := args["unlike"].(bool)
 
 	var res *ActionResult
-	var err error
 
 	if unlike {
 		res, err = s.xiaohongshuService.UnlikeFeed(ctx, feedID, xsecToken)
@@ -437,18 +468,13 @@ func (s *AppServer) handleLikeFeed(ctx context.Context, args map[string]interfac
 
 // handleFavoriteFeed 处理收藏/取消收藏
 func (s *AppServer) handleFavoriteFeed(ctx context.Context, args map[string]interface{}) *MCPToolResult {
-	feedID, ok := args["feed_id"].(string)
-	if !ok || feedID == "" {
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: 缺少feed_id参数"}}, IsError: true}
-	}
-	xsecToken, ok := args["xsec_token"].(string)
-	if !ok || xsecToken == "" {
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: 缺少xsec_token参数"}}, IsError: true}
+	feedID, xsecToken, err := s.resolveFeedArgs(ctx, args)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: " + err.Error()}}, IsError: true}
 	}
 	unfavorite, _ := args["unfavorite"].(bool)
 
 	var res *ActionResult
-	var err error
 
 	if unfavorite {
 		res, err = s.xiaohongshuService.UnfavoriteFeed(ctx, feedID, xsecToken)
@@ -476,23 +502,12 @@ func (s *AppServer) handlePostComment(ctx context.Context, args map[string]inter
 	logrus.Info("MCP: 发表评论到Feed")
 
 	// 解析参数
-	feedID, ok := args["feed_id"].(string)
-	if !ok || feedID == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发表评论失败: 缺少feed_id参数",
-			}},
-			IsError: true,
-		}
-	}
-
-	xsecToken, ok := args["xsec_token"].(string)
-	if !ok || xsecToken == "" {
+	feedID, xsecToken, err := s.resolveFeedArgs(ctx, args)
+	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "发表评论失败: 缺少xsec_token参数",
+				Text: "发表评论失败: " + err.Error(),
 			}},
 			IsError: true,
 		}
@@ -532,3 +547,702 @@ func (s *AppServer) handlePostComment(ctx context.Context, args map[string]inter
 		}},
 	}
 }
+
+// handleReplyComment 处理回复评论
+func (s *AppServer) handleReplyComment(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	logrus.Info("MCP: 回复评论")
+
+	feedID, xsecToken, err := s.resolveFeedArgs(ctx, args)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "回复评论失败: " + err.Error()}}, IsError: true}
+	}
+
+	commentID, _ := args["comment_id"].(string)
+	if commentID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "回复评论失败: 缺少comment_id参数"}}, IsError: true}
+	}
+
+	content, _ := args["content"].(string)
+	if content == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "回复评论失败: 缺少content参数"}}, IsError: true}
+	}
+
+	logrus.Infof("MCP: 回复评论 - Feed ID: %s, Comment ID: %s, 内容长度: %d", feedID, commentID, len(content))
+
+	result, err := s.xiaohongshuService.ReplyToComment(ctx, feedID, xsecToken, commentID, content)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "回复评论失败: " + err.Error()}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("回复评论成功 - Feed ID: %s, Comment ID: %s", result.FeedID, result.CommentID)}}}
+}
+
+// handleLikeComment 处理点赞/取消点赞评论
+func (s *AppServer) handleLikeComment(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	feedID, xsecToken, err := s.resolveFeedArgs(ctx, args)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: " + err.Error()}}, IsError: true}
+	}
+
+	commentID, _ := args["comment_id"].(string)
+	if commentID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "操作失败: 缺少comment_id参数"}}, IsError: true}
+	}
+	unlike, _ := args["unlike"].(bool)
+
+	action := "点赞评论"
+	if unlike {
+		action = "取消点赞评论"
+	}
+
+	var res *CommentActionResult
+	if unlike {
+		res, err = s.xiaohongshuService.UnlikeComment(ctx, feedID, xsecToken, commentID)
+	} else {
+		res, err = s.xiaohongshuService.LikeComment(ctx, feedID, xsecToken, commentID)
+	}
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + err.Error()}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s成功 - Feed ID: %s, Comment ID: %s", action, res.FeedID, res.CommentID)}}}
+}
+
+// handleDeleteComment 处理删除评论
+func (s *AppServer) handleDeleteComment(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	logrus.Info("MCP: 删除评论")
+
+	feedID, xsecToken, err := s.resolveFeedArgs(ctx, args)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除评论失败: " + err.Error()}}, IsError: true}
+	}
+
+	commentID, _ := args["comment_id"].(string)
+	content, _ := args["content"].(string)
+	if commentID == "" && content == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除评论失败: 缺少comment_id或content参数"}}, IsError: true}
+	}
+
+	logrus.Infof("MCP: 删除评论 - Feed ID: %s, Comment ID: %s", feedID, commentID)
+
+	result, err := s.xiaohongshuService.DeleteComment(ctx, feedID, xsecToken, xiaohongshu.DeleteCommentOption{
+		CommentID: commentID,
+		Content:   content,
+	})
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除评论失败: " + err.Error()}}, IsError: true}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("删除评论成功，但序列化失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除评论成功:\n" + string(jsonData)}}}
+}
+
+// handleFollowUser 处理关注/取消关注用户
+func (s *AppServer) handleFollowUser(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	unfollow, _ := args["unfollow"].(bool)
+
+	action := "关注"
+	if unfollow {
+		action = "取消关注"
+	}
+
+	userID, xsecToken, err := s.resolveUserArgs(ctx, args)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + err.Error()}}, IsError: true}
+	}
+
+	logrus.Infof("MCP: %s用户 - User ID: %s", action, userID)
+
+	var res *UserActionResult
+	if unfollow {
+		res, err = s.xiaohongshuService.UnfollowUser(ctx, userID, xsecToken)
+	} else {
+		res, err = s.xiaohongshuService.FollowUser(ctx, userID, xsecToken)
+	}
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + err.Error()}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s成功 - User ID: %s", action, res.UserID)}}}
+}
+
+// handleListMyNotes 处理获取自己发布的笔记列表
+func (s *AppServer) handleListMyNotes(ctx context.Context, args ListMyNotesArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取自己发布的笔记 - 状态: %s", args.Status)
+
+	result, err := s.xiaohongshuService.ListMyNotes(ctx, args.Status)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "获取笔记列表失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("获取笔记列表成功，但序列化失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleEditNote 处理编辑笔记
+func (s *AppServer) handleEditNote(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	noteID, _ := args["note_id"].(string)
+	if noteID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "编辑笔记失败: 缺少note_id参数"}}, IsError: true}
+	}
+
+	title, _ := args["title"].(string)
+	content, _ := args["content"].(string)
+	tagsInterface, _ := args["tags"].([]interface{})
+
+	var tags []string
+	for _, tag := range tagsInterface {
+		if tagStr, ok := tag.(string); ok {
+			tags = append(tags, tagStr)
+		}
+	}
+
+	logrus.Infof("MCP: 编辑笔记 - Note ID: %s, 标题: %s, 标签数量: %d", noteID, title, len(tags))
+
+	result, err := s.xiaohongshuService.EditMyNote(ctx, &EditNoteRequest{
+		NoteID:  noteID,
+		Title:   title,
+		Content: content,
+		Tags:    tags,
+	})
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "编辑笔记失败: " + err.Error()}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s - Note ID: %s", result.Message, result.NoteID)}}}
+}
+
+// handleDeleteNote 处理删除笔记
+func (s *AppServer) handleDeleteNote(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	noteID, _ := args["note_id"].(string)
+	if noteID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除笔记失败: 缺少note_id参数"}}, IsError: true}
+	}
+	confirm, _ := args["confirm"].(bool)
+
+	logrus.Infof("MCP: 删除笔记 - Note ID: %s, confirm: %v", noteID, confirm)
+
+	result, err := s.xiaohongshuService.DeleteMyNote(ctx, noteID, confirm)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除笔记失败: " + err.Error()}}, IsError: true}
+	}
+
+	resultText := fmt.Sprintf("笔记删除成功: %+v", result.Data)
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: resultText}}}
+}
+
+// handleEnqueuePublish 处理加入发布队列
+func (s *AppServer) handleEnqueuePublish(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	title, _ := args["title"].(string)
+	content, _ := args["content"].(string)
+	imagesInterface, _ := args["images"].([]interface{})
+	videoPath, _ := args["video"].(string)
+	tagsInterface, _ := args["tags"].([]interface{})
+	runAtStr, _ := args["run_at"].(string)
+	publishAt, _ := args["publish_at"].(string)
+	account, _ := args["account"].(string)
+	maxAttempts, _ := args["max_attempts"].(int)
+	visibility, _ := args["visibility"].(string)
+	original, _ := args["original"].(bool)
+	declaration, _ := args["declaration"].(string)
+	mentions := convertInterfacesToStrings(args["mentions"])
+	location, _ := args["location"].(string)
+	coverTime, _ := args["cover_time"].(string)
+	coverImage, _ := args["cover_image"].(string)
+	draft, _ := args["draft"].(bool)
+	imageAspect, _ := args["image_aspect"].(string)
+	imageFit, _ := args["image_fit"].(string)
+	imageFormat, _ := args["image_format"].(string)
+	imageMaxKB, _ := args["image_max_kb"].(int)
+	emojiCodes, _ := args["emoji_codes"].(bool)
+	idempotencyKey, _ := args["idempotency_key"].(string)
+
+	var images []string
+	for _, path := range imagesInterface {
+		if pathStr, ok := path.(string); ok {
+			images = append(images, pathStr)
+		}
+	}
+
+	var tags []string
+	for _, tag := range tagsInterface {
+		if tagStr, ok := tag.(string); ok {
+			tags = append(tags, tagStr)
+		}
+	}
+
+	if title == "" || content == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "加入发布队列失败: 缺少title或content参数"}}, IsError: true}
+	}
+	if (len(images) == 0) == (videoPath == "") {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "加入发布队列失败: images和video必须且只能提供一个"}}, IsError: true}
+	}
+
+	var runAt time.Time
+	if runAtStr != "" {
+		t, err := xiaohongshu.ParseScheduleTime(runAtStr)
+		if err != nil {
+			return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "加入发布队列失败: " + err.Error()}}, IsError: true}
+		}
+		runAt = t
+	}
+	checkAt := runAt
+	if checkAt.Before(time.Now()) {
+		checkAt = time.Now()
+	}
+
+	// 提前进行与直接发布相同的校验，避免任务执行时才发现参数错误
+	kind := publishqueue.KindImage
+	var payload any
+	if videoPath == "" {
+		req := &PublishRequest{
+			Title:       title,
+			Content:     content,
+			Images:      images,
+			Tags:        tags,
+			PublishAt:   publishAt,
+			Draft:       draft,
+			Visibility:  visibility,
+			Original:    original,
+			Declaration: declaration,
+			Mentions:    mentions,
+			Location:    location,
+			EmojiCodes:  emojiCodes,
+			ImageOptions: downloader.PreprocessOptions{
+				Aspect:   imageAspect,
+				Fit:      imageFit,
+				Format:   imageFormat,
+				MaxBytes: int64(imageMaxKB) * 1024,
+			},
+			IdempotencyKey: idempotencyKey,
+		}
+		req.CoverCollage, req.Watermark = composeOptionsFromArgs(args)
+		if _, err := s.xiaohongshuService.checkPublishRequest(req, checkAt); err != nil {
+			return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "加入发布队列失败: " + err.Error()}}, IsError: true}
+		}
+		payload = req
+	} else {
+		kind = publishqueue.KindVideo
+		req := &PublishVideoRequest{
+			Title:          title,
+			Content:        content,
+			Video:          videoPath,
+			Tags:           tags,
+			PublishAt:      publishAt,
+			Draft:          draft,
+			Visibility:     visibility,
+			Original:       original,
+			Declaration:    declaration,
+			Mentions:       mentions,
+			Location:       location,
+			CoverTime:      coverTime,
+			CoverImage:     coverImage,
+			EmojiCodes:     emojiCodes,
+			IdempotencyKey: idempotencyKey,
+		}
+		if _, err := s.xiaohongshuService.checkPublishVideoRequest(req, checkAt); err != nil {
+			return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "加入发布队列失败: " + err.Error()}}, IsError: true}
+		}
+		// 视频链接在任务执行时才下载，入队时只能校验本地文件
+		if !downloader.IsVideoURL(videoPath) {
+			if err := s.xiaohongshuService.checkVideo(videoPath); err != nil {
+				return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "加入发布队列失败: " + err.Error()}}, IsError: true}
+			}
+		}
+		payload = req
+	}
+
+	logrus.Infof("MCP: 加入发布队列 - 标题: %s, 类型: %s, 账号: %s, 执行时间: %s", title, kind, account, runAtStr)
+
+	job, err := s.publishQueue.Enqueue(account, kind, payload, runAt, maxAttempts)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "加入发布队列失败: " + err.Error()}}, IsError: true}
+	}
+
+	resultText := fmt.Sprintf("已加入发布队列 - Job ID: %s, 账号: %s, 执行时间: %s",
+		job.ID, job.Account, job.RunAt.Format("2006-01-02 15:04:05"))
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: resultText}}}
+}
+
+// handleListPublishJobs 处理查看发布队列
+func (s *AppServer) handleListPublishJobs(ctx context.Context, args ListPublishJobsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 查看发布队列 - 状态: %s", args.Status)
+
+	jobs := s.publishQueue.List(args.Status)
+
+	jsonData, err := json.MarshalIndent(map[string]any{
+		"jobs":  jobs,
+		"count": len(jobs),
+	}, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("获取发布队列成功，但序列化失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleReschedulePublishJob 处理修改发布任务执行时间
+func (s *AppServer) handleReschedulePublishJob(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	jobID, _ := args["job_id"].(string)
+	if jobID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "修改发布任务失败: 缺少job_id参数"}}, IsError: true}
+	}
+
+	runAtStr, _ := args["run_at"].(string)
+	var runAt time.Time
+	if runAtStr != "" {
+		t, err := xiaohongshu.ParseScheduleTime(runAtStr)
+		if err != nil {
+			return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "修改发布任务失败: " + err.Error()}}, IsError: true}
+		}
+		runAt = t
+	}
+
+	logrus.Infof("MCP: 修改发布任务 - Job ID: %s, 执行时间: %s", jobID, runAtStr)
+
+	job, err := s.publishQueue.Reschedule(jobID, runAt)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "修改发布任务失败: " + err.Error()}}, IsError: true}
+	}
+
+	resultText := fmt.Sprintf("发布任务已重新调度 - Job ID: %s, 执行时间: %s", job.ID, job.RunAt.Format("2006-01-02 15:04:05"))
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: resultText}}}
+}
+
+// handleCancelPublishJob 处理取消发布任务
+func (s *AppServer) handleCancelPublishJob(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	jobID, _ := args["job_id"].(string)
+	if jobID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "取消发布任务失败: 缺少job_id参数"}}, IsError: true}
+	}
+
+	logrus.Infof("MCP: 取消发布任务 - Job ID: %s", jobID)
+
+	job, err := s.publishQueue.Cancel(jobID)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "取消发布任务失败: " + err.Error()}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("发布任务已取消 - Job ID: %s", job.ID)}}}
+}
+
+// handleListDrafts 处理获取草稿箱
+func (s *AppServer) handleListDrafts(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 获取草稿箱")
+
+	result, err := s.xiaohongshuService.ListDrafts(ctx)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "获取草稿箱失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("获取草稿箱成功，但序列化失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handlePublishDraft 处理发布草稿
+func (s *AppServer) handlePublishDraft(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	draftID, _ := args["draft_id"].(string)
+	if draftID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "发布草稿失败: 缺少draft_id参数"}}, IsError: true}
+	}
+
+	logrus.Infof("MCP: 发布草稿 - Draft ID: %s", draftID)
+
+	result, err := s.xiaohongshuService.PublishDraft(ctx, draftID)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "发布草稿失败: " + err.Error()}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s: %+v", result.Message, result.Data)}}}
+}
+
+// handlePublishTextCards 处理发布文字卡片
+func (s *AppServer) handlePublishTextCards(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	req := &PublishTextCardsRequest{}
+	req.Title, _ = args["title"].(string)
+	req.Content, _ = args["content"].(string)
+	req.CardTitle, _ = args["card_title"].(string)
+	req.CardText, _ = args["card_text"].(string)
+	req.Theme, _ = args["theme"].(string)
+	req.Watermark, _ = args["watermark"].(string)
+	req.FontPath, _ = args["font_path"].(string)
+	req.Tags = convertInterfacesToStrings(args["tags"])
+	req.PublishAt, _ = args["publish_at"].(string)
+	req.Draft, _ = args["draft"].(bool)
+	req.Visibility, _ = args["visibility"].(string)
+	req.Original, _ = args["original"].(bool)
+	req.Declaration, _ = args["declaration"].(string)
+	req.IdempotencyKey, _ = args["idempotency_key"].(string)
+
+	if strings.TrimSpace(req.CardText) == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "发布文字卡片失败: 缺少card_text参数"}}, IsError: true}
+	}
+
+	logrus.Infof("MCP: 发布文字卡片 - 标题: %s, 主题: %s", req.Title, req.Theme)
+
+	result, err := s.xiaohongshuService.PublishTextCards(ctx, req)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "发布文字卡片失败: " + err.Error()}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("文字卡片发布成功（%d 张卡片）: %+v", len(result.Cards), result.PublishResponse)}}}
+}
+
+// handleLintContent 处理内容校验
+func (s *AppServer) handleLintContent(_ context.Context, args map[string]interface{}) *MCPToolResult {
+	title, _ := args["title"].(string)
+	content, _ := args["content"].(string)
+	tags := convertInterfacesToStrings(args["tags"])
+
+	logrus.Infof("MCP: 内容校验 - 标题: %s", title)
+
+	result := s.xiaohongshuService.LintContent(title, content, tags)
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("内容校验完成，但序列化失败: %v", err)}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: string(jsonData)}}}
+}
+
+// handlePublishMarkdown 处理从 Markdown 文件发布
+func (s *AppServer) handlePublishMarkdown(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	req := &PublishMarkdownRequest{}
+	req.FilePath, _ = args["file_path"].(string)
+	req.Draft, _ = args["draft"].(bool)
+	req.DryRun, _ = args["dry_run"].(bool)
+	req.IdempotencyKey, _ = args["idempotency_key"].(string)
+
+	if req.FilePath == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "发布 Markdown 失败: 缺少file_path参数"}}, IsError: true}
+	}
+
+	logrus.Infof("MCP: 发布 Markdown - 文件: %s, dry_run: %v", req.FilePath, req.DryRun)
+
+	result, err := s.xiaohongshuService.PublishMarkdown(ctx, req)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "发布 Markdown 失败: " + err.Error()}}, IsError: true}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("发布 Markdown 完成，但序列化失败: %v", err)}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: string(jsonData)}}}
+}
+
+// handleComposeImages 处理图片合成
+func (s *AppServer) handleComposeImages(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	req := &ComposeImagesRequest{Images: convertInterfacesToStrings(args["images"])}
+	req.Collage, req.Watermark = composeOptionsFromArgs(args)
+
+	if len(req.Images) == 0 {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "图片合成失败: 缺少images参数"}}, IsError: true}
+	}
+
+	logrus.Infof("MCP: 图片合成 - 图片数量: %d, 拼图: %v, 水印: %v", len(req.Images), req.Collage != nil, req.Watermark != nil)
+
+	result, err := s.xiaohongshuService.ComposeImages(ctx, req)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "图片合成失败: " + err.Error()}}, IsError: true}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("图片合成完成，但序列化失败: %v", err)}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: string(jsonData)}}}
+}
+
+// composeOptionsFromArgs 从工具参数构建拼图和水印选项，未启用的返回 nil
+func composeOptionsFromArgs(args map[string]interface{}) (*imagekit.CollageOptions, *imagekit.WatermarkOptions) {
+	var (
+		collage   *imagekit.CollageOptions
+		watermark *imagekit.WatermarkOptions
+	)
+
+	if enabled, _ := args["collage"].(bool); enabled {
+		collage = &imagekit.CollageOptions{}
+		collage.Columns, _ = args["collage_columns"].(int)
+		collage.Aspect, _ = args["collage_aspect"].(string)
+	}
+
+	text, _ := args["watermark_text"].(string)
+	logo, _ := args["watermark_logo"].(string)
+	if text != "" || logo != "" {
+		watermark = &imagekit.WatermarkOptions{Text: text, Logo: logo}
+		watermark.Position, _ = args["watermark_position"].(string)
+		watermark.Opacity, _ = args["watermark_opacity"].(float64)
+	}
+
+	return collage, watermark
+}
+
+// handleResolveNoteURL 处理解析分享链接
+func (s *AppServer) handleResolveNoteURL(ctx context.Context, args ResolveNoteURLArgs) *MCPToolResult {
+	logrus.Info("MCP: 解析分享链接")
+
+	if args.URL == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "解析链接失败: 缺少url参数",
+			}},
+			IsError: true,
+		}
+	}
+
+	result, err := s.xiaohongshuService.ResolveNoteURL(ctx, args.URL)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "解析链接失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("解析链接成功，但序列化失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// resolveFeedArgs 从参数中获取 feed_id 和 xsec_token。
+// 未提供 feed_id 时，尝试通过 url 参数（笔记链接或分享短链接）解析。
+// 返回的 xsec_token 可能为空，此时由服务层从令牌缓存中查找。
+func (s *AppServer) resolveFeedArgs(ctx context.Context, args map[string]any) (string, string, error) {
+	feedID, _ := args["feed_id"].(string)
+	xsecToken, _ := args["xsec_token"].(string)
+	rawURL, _ := args["url"].(string)
+
+	if feedID == "" && rawURL != "" {
+		resolved, err := s.xiaohongshuService.ResolveNoteURL(ctx, rawURL)
+		if err != nil {
+			return "", "", fmt.Errorf("解析url参数失败: %w", err)
+		}
+		if resolved.Type != xiaohongshu.ResolvedTypeNote {
+			return "", "", fmt.Errorf("url参数不是笔记链接: %s", resolved.URL)
+		}
+
+		feedID = resolved.FeedID
+		if xsecToken == "" {
+			xsecToken = resolved.XsecToken
+		}
+	}
+
+	if feedID == "" {
+		return "", "", fmt.Errorf("缺少feed_id参数")
+	}
+
+	return feedID, xsecToken, nil
+}
+
+// resolveUserArgs 从参数中获取 user_id 和 xsec_token。
+// 未提供 user_id 时，尝试通过 url 参数（用户主页链接）解析。
+func (s *AppServer) resolveUserArgs(ctx context.Context, args map[string]any) (string, string, error) {
+	userID, _ := args["user_id"].(string)
+	xsecToken, _ := args["xsec_token"].(string)
+	rawURL, _ := args["url"].(string)
+
+	if userID == "" && rawURL != "" {
+		resolved, err := s.xiaohongshuService.ResolveNoteURL(ctx, rawURL)
+		if err != nil {
+			return "", "", fmt.Errorf("解析url参数失败: %w", err)
+		}
+		if resolved.Type != xiaohongshu.ResolvedTypeUser {
+			return "", "", fmt.Errorf("url参数不是用户主页链接: %s", resolved.URL)
+		}
+
+		userID = resolved.UserID
+		if xsecToken == "" {
+			xsecToken = resolved.XsecToken
+		}
+	}
+
+	if userID == "" {
+		return "", "", fmt.Errorf("缺少user_id参数")
+	}
+
+	return userID, xsecToken, nil
+}
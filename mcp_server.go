@@ -14,18 +14,49 @@ import (
 
 // PublishContentArgs 发布内容的参数
 type PublishContentArgs struct {
-	Title   string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
-	Content string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
-	Images  []string `json:"images" jsonschema:"图片路径列表（至少需要1张图片）。支持两种方式：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）"`
-	Tags    []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	Title          string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
+	Content        string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
+	Images         []string `json:"images" jsonschema:"图片路径列表（1-18张图片）。支持三种方式：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）；3. data URI（如 data:image/png;base64,...）或 base64 编码的图片"`
+	Tags           []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	PublishAt      string   `json:"publish_at,omitempty" jsonschema:"定时发布时间（可选），如 2025-01-02 15:04（北京时间）或 RFC3339 格式；需在1小时后至14天内，不填则立即发布"`
+	Draft          bool     `json:"draft,omitempty" jsonschema:"仅保存到草稿箱不发布（可选），用于人工审核后再通过publish_draft发布"`
+	Visibility     string   `json:"visibility,omitempty" jsonschema:"可见范围（可选）: 公开|仅自己可见|仅互关好友可见，默认公开"`
+	Original       bool     `json:"original,omitempty" jsonschema:"是否声明原创（可选）"`
+	Declaration    string   `json:"declaration,omitempty" jsonschema:"内容类型声明（可选）: 笔记含AI合成内容|虚构演绎，仅供娱乐。AI生成的内容按平台规定必须声明'笔记含AI合成内容'"`
+	Mentions       []string `json:"mentions,omitempty" jsonschema:"需要@的用户列表（可选），填写用户昵称或小红书号，通过编辑器的@下拉框匹配，未匹配的会在结果中列出"`
+	Location       string   `json:"location,omitempty" jsonschema:"地点名称（可选），通过'添加地点'搜索选择，未找到时不影响发布并在结果中列出"`
+	ImageAspect    string   `json:"image_aspect,omitempty" jsonschema:"图片宽高比（可选）: 3:4|1:1|4:3，不填保持原比例"`
+	ImageFit       string   `json:"image_fit,omitempty" jsonschema:"宽高比适配方式（可选）: crop 居中裁剪|pad 补白边，默认 crop"`
+	ImageFormat    string   `json:"image_format,omitempty" jsonschema:"图片输出格式（可选）: jpeg|png，默认转为 JPEG，带透明通道的 PNG 保持 PNG"`
+	ImageMaxKB     int      `json:"image_max_kb,omitempty" jsonschema:"单张图片大小上限KB（可选），超过时自动压缩，默认 20MB"`
+	EmojiCodes     bool     `json:"emoji_codes,omitempty" jsonschema:"是否将正文中的 emoji 转为小红书表情代码（可选），如 😂 转为 [笑哭R]，发布后显示为小红书自带表情"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"幂等键（可选），超时重试时传入与首次相同的值可直接返回首次的发布结果，不会重复发布；不填时按内容去重，换一个新值可再次发布相同内容"`
+
+	WatermarkText     string  `json:"watermark_text,omitempty" jsonschema:"图片水印文字（可选），如 @品牌名，为每张图片添加"`
+	WatermarkLogo     string  `json:"watermark_logo,omitempty" jsonschema:"水印 Logo 图片（可选），本地路径或HTTP链接，建议使用透明背景的 PNG；与文字同时提供时 Logo 在左"`
+	WatermarkPosition string  `json:"watermark_position,omitempty" jsonschema:"水印位置（可选）: top-left|top-right|bottom-left|bottom-right|center，默认 bottom-right"`
+	WatermarkOpacity  float64 `json:"watermark_opacity,omitempty" jsonschema:"水印不透明度（可选），0-1，默认 0.6"`
+	CoverCollage      bool    `json:"cover_collage,omitempty" jsonschema:"是否用前几张图片（最多9张）拼成网格封面（可选），拼图插入为第一张图片并计入18张上限，需要至少2张图片"`
+	CollageColumns    int     `json:"collage_columns,omitempty" jsonschema:"拼图每行图片数（可选），默认按图片数量自动选择"`
 }
 
-// PublishVideoArgs 发布视频的参数（仅支持本地单个视频文件）
+// PublishVideoArgs 发布视频的参数（单个视频文件，本地路径或链接）
 type PublishVideoArgs struct {
-	Title   string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
-	Content string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
-	Video   string   `json:"video" jsonschema:"本地视频绝对路径（仅支持单个视频文件，如:/Users/user/video.mp4）"`
-	Tags    []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	Title          string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
+	Content        string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
+	Video          string   `json:"video" jsonschema:"单个视频文件，支持本地视频绝对路径（如:/Users/user/video.mp4）或HTTP/HTTPS视频链接（MP4/MOV），推荐使用本地路径"`
+	Tags           []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	PublishAt      string   `json:"publish_at,omitempty" jsonschema:"定时发布时间（可选），如 2025-01-02 15:04（北京时间）或 RFC3339 格式；需在1小时后至14天内，不填则立即发布"`
+	Draft          bool     `json:"draft,omitempty" jsonschema:"仅保存到草稿箱不发布（可选），用于人工审核后再通过publish_draft发布"`
+	Visibility     string   `json:"visibility,omitempty" jsonschema:"可见范围（可选）: 公开|仅自己可见|仅互关好友可见，默认公开"`
+	Original       bool     `json:"original,omitempty" jsonschema:"是否声明原创（可选）"`
+	Declaration    string   `json:"declaration,omitempty" jsonschema:"内容类型声明（可选）: 笔记含AI合成内容|虚构演绎，仅供娱乐。AI生成的内容按平台规定必须声明'笔记含AI合成内容'"`
+	Mentions       []string `json:"mentions,omitempty" jsonschema:"需要@的用户列表（可选），填写用户昵称或小红书号，通过编辑器的@下拉框匹配，未匹配的会在结果中列出"`
+	Location       string   `json:"location,omitempty" jsonschema:"地点名称（可选），通过'添加地点'搜索选择，未找到时不影响发布并在结果中列出"`
+	CoverTime      string   `json:"cover_time,omitempty" jsonschema:"封面截取时间（可选），如 3.5（秒）、01:20，从视频中选择该时间点的画面作为封面"`
+	CoverImage     string   `json:"cover_image,omitempty" jsonschema:"封面图片（可选），本地图片绝对路径或HTTP/HTTPS图片链接，与cover_time二选一"`
+	EmojiCodes     bool     `json:"emoji_codes,omitempty" jsonschema:"是否将正文中的 emoji 转为小红书表情代码（可选），如 😂 转为 [笑哭R]，发布后显示为小红书自带表情"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"幂等键（可选），超时重试时传入与首次相同的值可直接返回首次的发布结果，不会重复发布；不填时按内容去重，换一个新值可再次发布相同内容"`
 }
 
 // SearchFeedsArgs 搜索内容的参数
@@ -45,37 +76,200 @@ type FilterOption struct {
 
 // FeedDetailArgs 获取Feed详情的参数
 type FeedDetailArgs struct {
-	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
-	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	FeedID    string `json:"feed_id,omitempty" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token,omitempty" jsonschema:"访问令牌（可选），从Feed列表的xsecToken字段获取；未提供时使用最近从搜索/列表结果中记录的令牌"`
+	URL       string `json:"url,omitempty" jsonschema:"笔记链接或分享短链接（可选），提供时可省略feed_id和xsec_token，如 http://xhslink.com/a/xxx"`
 }
 
 // UserProfileArgs 获取用户主页的参数
 type UserProfileArgs struct {
 	UserID    string `json:"user_id" jsonschema:"小红书用户ID，从Feed列表获取"`
-	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	XsecToken string `json:"xsec_token,omitempty" jsonschema:"访问令牌（可选），从Feed列表的xsecToken字段获取；未提供时使用最近从搜索/列表结果中记录的令牌"`
 }
 
 // PostCommentArgs 发表评论的参数
 type PostCommentArgs struct {
-	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
-	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	FeedID    string `json:"feed_id,omitempty" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token,omitempty" jsonschema:"访问令牌（可选），从Feed列表的xsecToken字段获取；未提供时使用最近从搜索/列表结果中记录的令牌"`
+	URL       string `json:"url,omitempty" jsonschema:"笔记链接或分享短链接（可选），提供时可省略feed_id和xsec_token，如 http://xhslink.com/a/xxx"`
 	Content   string `json:"content" jsonschema:"评论内容"`
 }
 
 // LikeFeedArgs 点赞参数
 type LikeFeedArgs struct {
-	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
-	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	FeedID    string `json:"feed_id,omitempty" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token,omitempty" jsonschema:"访问令牌（可选），从Feed列表的xsecToken字段获取；未提供时使用最近从搜索/列表结果中记录的令牌"`
+	URL       string `json:"url,omitempty" jsonschema:"笔记链接或分享短链接（可选），提供时可省略feed_id和xsec_token，如 http://xhslink.com/a/xxx"`
 	Unlike    bool   `json:"unlike,omitempty" jsonschema:"是否取消点赞，true为取消点赞，false或未设置则为点赞"`
 }
 
 // FavoriteFeedArgs 收藏参数
 type FavoriteFeedArgs struct {
-	FeedID     string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
-	XsecToken  string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	FeedID     string `json:"feed_id,omitempty" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken  string `json:"xsec_token,omitempty" jsonschema:"访问令牌（可选），从Feed列表的xsecToken字段获取；未提供时使用最近从搜索/列表结果中记录的令牌"`
+	URL        string `json:"url,omitempty" jsonschema:"笔记链接或分享短链接（可选），提供时可省略feed_id和xsec_token，如 http://xhslink.com/a/xxx"`
 	Unfavorite bool   `json:"unfavorite,omitempty" jsonschema:"是否取消收藏，true为取消收藏，false或未设置则为收藏"`
 }
 
+// ReplyCommentArgs 回复评论的参数
+type ReplyCommentArgs struct {
+	FeedID    string `json:"feed_id,omitempty" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token,omitempty" jsonschema:"访问令牌（可选），从Feed列表的xsecToken字段获取；未提供时使用最近从搜索/列表结果中记录的令牌"`
+	URL       string `json:"url,omitempty" jsonschema:"笔记链接或分享短链接（可选），提供时可省略feed_id和xsec_token，如 http://xhslink.com/a/xxx"`
+	CommentID string `json:"comment_id" jsonschema:"要回复的评论ID，从笔记详情的comments.list[].id或subComments[].id获取"`
+	Content   string `json:"content" jsonschema:"回复内容"`
+}
+
+// LikeCommentArgs 点赞评论的参数
+type LikeCommentArgs struct {
+	FeedID    string `json:"feed_id,omitempty" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token,omitempty" jsonschema:"访问令牌（可选），从Feed列表的xsecToken字段获取；未提供时使用最近从搜索/列表结果中记录的令牌"`
+	URL       string `json:"url,omitempty" jsonschema:"笔记链接或分享短链接（可选），提供时可省略feed_id和xsec_token，如 http://xhslink.com/a/xxx"`
+	CommentID string `json:"comment_id" jsonschema:"要点赞的评论ID，从笔记详情的comments.list[].id或subComments[].id获取"`
+	Unlike    bool   `json:"unlike,omitempty" jsonschema:"是否取消点赞，true为取消点赞，false或未设置则为点赞"`
+}
+
+// DeleteCommentArgs 删除评论的参数
+type DeleteCommentArgs struct {
+	FeedID    string `json:"feed_id,omitempty" jsonschema:"小红书笔记ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token,omitempty" jsonschema:"访问令牌（可选），从Feed列表的xsecToken字段获取；未提供时使用最近从搜索/列表结果中记录的令牌"`
+	URL       string `json:"url,omitempty" jsonschema:"笔记链接或分享短链接（可选），提供时可省略feed_id和xsec_token，如 http://xhslink.com/a/xxx"`
+	CommentID string `json:"comment_id,omitempty" jsonschema:"要删除的评论ID（与content二选一）"`
+	Content   string `json:"content,omitempty" jsonschema:"要删除的评论内容，精确匹配当前账号发表的评论（与comment_id二选一）"`
+}
+
+// FollowUserArgs 关注/取消关注用户的参数
+type FollowUserArgs struct {
+	UserID    string `json:"user_id,omitempty" jsonschema:"小红书用户ID，从Feed列表获取"`
+	XsecToken string `json:"xsec_token,omitempty" jsonschema:"访问令牌（可选），从Feed列表的xsecToken字段获取；未提供时使用最近从搜索/列表结果中记录的令牌"`
+	URL       string `json:"url,omitempty" jsonschema:"用户主页链接（可选），提供时可省略user_id和xsec_token"`
+}
+
+// ListMyNotesArgs 获取自己笔记列表的参数
+type ListMyNotesArgs struct {
+	Status string `json:"status,omitempty" jsonschema:"笔记状态: 全部|已发布|审核中|未通过,默认为'全部'"`
+}
+
+// EditNoteArgs 编辑笔记的参数
+type EditNoteArgs struct {
+	NoteID  string   `json:"note_id" jsonschema:"要编辑的笔记ID，从list_my_notes获取"`
+	Title   string   `json:"title,omitempty" jsonschema:"新标题（可选），不填则保持不变"`
+	Content string   `json:"content,omitempty" jsonschema:"新正文（可选），不填则保持不变；填写时替换原正文，正文末尾原有的话题标签保留"`
+	Tags    []string `json:"tags,omitempty" jsonschema:"新的话题标签（可选），如 [美食, 旅行]，替换正文末尾原有的话题标签；不填则保持不变"`
+}
+
+// DeleteNoteArgs 删除笔记的参数
+type DeleteNoteArgs struct {
+	NoteID  string `json:"note_id" jsonschema:"要删除的笔记ID，从list_my_notes获取"`
+	Confirm bool   `json:"confirm" jsonschema:"确认删除，删除不可恢复，必须为true才会执行"`
+}
+
+// EnqueuePublishArgs 加入发布队列的参数，images 和 video 二选一
+type EnqueuePublishArgs struct {
+	Title       string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
+	Content     string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容"`
+	Images      []string `json:"images,omitempty" jsonschema:"图片路径列表，发布图文时必填（HTTP/HTTPS图片链接、本地绝对路径、data URI 或 base64）"`
+	Video       string   `json:"video,omitempty" jsonschema:"本地视频绝对路径或HTTP/HTTPS视频链接，发布视频时必填"`
+	Tags        []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	RunAt       string   `json:"run_at,omitempty" jsonschema:"任务执行时间（可选），如 2025-01-02 15:04（北京时间）或 RFC3339 格式，不填则尽快执行"`
+	PublishAt   string   `json:"publish_at,omitempty" jsonschema:"平台定时发布时间（可选），执行任务时使用平台的定时发布，需在执行时间的1小时后至14天内"`
+	Account     string   `json:"account,omitempty" jsonschema:"账号名（可选），目前只支持default（当前登录的账号），所有任务依次执行"`
+	MaxAttempts int      `json:"max_attempts,omitempty" jsonschema:"最大执行次数（可选，含首次），失败后按指数退避重试，默认为3"`
+	Visibility  string   `json:"visibility,omitempty" jsonschema:"可见范围（可选）: 公开|仅自己可见|仅互关好友可见，默认公开"`
+	Original    bool     `json:"original,omitempty" jsonschema:"是否声明原创（可选）"`
+	Declaration string   `json:"declaration,omitempty" jsonschema:"内容类型声明（可选）: 笔记含AI合成内容|虚构演绎，仅供娱乐。AI生成的内容按平台规定必须声明'笔记含AI合成内容'"`
+	Mentions    []string `json:"mentions,omitempty" jsonschema:"需要@的用户列表（可选），填写用户昵称或小红书号，通过编辑器的@下拉框匹配，未匹配的会在结果中列出"`
+	Location    string   `json:"location,omitempty" jsonschema:"地点名称（可选），通过'添加地点'搜索选择，未找到时不影响发布并在结果中列出"`
+	CoverTime   string   `json:"cover_time,omitempty" jsonschema:"视频封面截取时间（可选，仅视频），如 3.5（秒）、01:20"`
+	CoverImage  string   `json:"cover_image,omitempty" jsonschema:"视频封面图片（可选，仅视频），本地图片绝对路径或HTTP/HTTPS图片链接，与cover_time二选一"`
+
+	Draft          bool   `json:"draft,omitempty" jsonschema:"仅保存到草稿箱不发布（可选），不能与publish_at同时使用"`
+	ImageAspect    string `json:"image_aspect,omitempty" jsonschema:"图片宽高比（可选，仅图文）: 3:4|1:1|4:3，不填保持原比例"`
+	ImageFit       string `json:"image_fit,omitempty" jsonschema:"宽高比适配方式（可选，仅图文）: crop 居中裁剪|pad 补白边，默认 crop"`
+	ImageFormat    string `json:"image_format,omitempty" jsonschema:"图片输出格式（可选，仅图文）: jpeg|png，默认转为 JPEG，带透明通道的 PNG 保持 PNG"`
+	ImageMaxKB     int    `json:"image_max_kb,omitempty" jsonschema:"单张图片大小上限KB（可选，仅图文），超过时自动压缩，默认 20MB"`
+	EmojiCodes     bool   `json:"emoji_codes,omitempty" jsonschema:"是否将正文中的 emoji 转为小红书表情代码（可选），如 😂 转为 [笑哭R]"`
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"幂等键（可选），规则与 publish_content 相同，任务重试时不会重复发布"`
+
+	WatermarkText     string  `json:"watermark_text,omitempty" jsonschema:"图片水印文字（可选，仅图文），如 @品牌名，为每张图片添加"`
+	WatermarkLogo     string  `json:"watermark_logo,omitempty" jsonschema:"水印 Logo 图片（可选，仅图文），本地路径或HTTP链接，建议使用透明背景的 PNG"`
+	WatermarkPosition string  `json:"watermark_position,omitempty" jsonschema:"水印位置（可选）: top-left|top-right|bottom-left|bottom-right|center，默认 bottom-right"`
+	WatermarkOpacity  float64 `json:"watermark_opacity,omitempty" jsonschema:"水印不透明度（可选），0-1，默认 0.6"`
+	CoverCollage      bool    `json:"cover_collage,omitempty" jsonschema:"是否用前几张图片（最多9张）拼成网格封面（可选，仅图文），拼图插入为第一张图片并计入18张上限"`
+	CollageColumns    int     `json:"collage_columns,omitempty" jsonschema:"拼图每行图片数（可选），默认按图片数量自动选择"`
+}
+
+// ListPublishJobsArgs 查看发布队列的参数
+type ListPublishJobsArgs struct {
+	Status string `json:"status,omitempty" jsonschema:"任务状态: pending|running|succeeded|failed|canceled，不填则返回全部"`
+}
+
+// ReschedulePublishJobArgs 修改发布任务执行时间的参数
+type ReschedulePublishJobArgs struct {
+	JobID string `json:"job_id" jsonschema:"发布任务ID，从list_publish_jobs获取"`
+	RunAt string `json:"run_at,omitempty" jsonschema:"新的执行时间，如 2025-01-02 15:04（北京时间）或 RFC3339 格式，不填则尽快执行；已失败的任务会重新执行"`
+}
+
+// PublishDraftArgs 发布草稿的参数
+type PublishDraftArgs struct {
+	DraftID string `json:"draft_id" jsonschema:"草稿ID，从list_drafts获取"`
+}
+
+// PublishTextCardsArgs 发布文字卡片的参数
+type PublishTextCardsArgs struct {
+	Title          string   `json:"title" jsonschema:"笔记标题（小红书限制：最多20个中文字或英文单词）"`
+	Content        string   `json:"content" jsonschema:"笔记正文，不包含以#开头的标签内容"`
+	CardTitle      string   `json:"card_title,omitempty" jsonschema:"卡片上的标题（可选），不填使用笔记标题"`
+	CardText       string   `json:"card_text" jsonschema:"渲染到卡片上的文字，按换行分段，过长时自动分成多张卡片，最多18张"`
+	Theme          string   `json:"theme,omitempty" jsonschema:"卡片主题（可选）: light|dark|warm|mint，默认 light"`
+	Watermark      string   `json:"watermark,omitempty" jsonschema:"卡片左下角的水印文字（可选），如 @账号名"`
+	FontPath       string   `json:"font_path,omitempty" jsonschema:"中文字体文件路径（可选），支持 ttf/otf/ttc，不填时使用 XHS_CARD_FONT 环境变量或系统中文字体"`
+	Tags           []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	PublishAt      string   `json:"publish_at,omitempty" jsonschema:"定时发布时间（可选），如 2025-01-02 15:04（北京时间）或 RFC3339 格式；需在1小时后至14天内，不填则立即发布"`
+	Draft          bool     `json:"draft,omitempty" jsonschema:"仅保存到草稿箱不发布（可选），用于人工审核后再通过publish_draft发布"`
+	Visibility     string   `json:"visibility,omitempty" jsonschema:"可见范围（可选）: 公开|仅自己可见|仅互关好友可见，默认公开"`
+	Original       bool     `json:"original,omitempty" jsonschema:"是否声明原创（可选）"`
+	Declaration    string   `json:"declaration,omitempty" jsonschema:"内容类型声明（可选）: 笔记含AI合成内容|虚构演绎，仅供娱乐"`
+	IdempotencyKey string   `json:"idempotency_key,omitempty" jsonschema:"幂等键（可选），超时重试时传入与首次相同的值可直接返回首次的发布结果，不会重复发布；不填时按内容去重，换一个新值可再次发布相同内容"`
+}
+
+// LintContentArgs 内容校验的参数
+type LintContentArgs struct {
+	Title   string   `json:"title" jsonschema:"笔记标题"`
+	Content string   `json:"content" jsonschema:"笔记正文"`
+	Tags    []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选）"`
+}
+
+// PublishMarkdownArgs 从 Markdown 文件发布的参数
+type PublishMarkdownArgs struct {
+	FilePath       string `json:"file_path" jsonschema:"Markdown 文件的本地绝对路径。front matter 支持 title、tags、visibility、schedule（或 publish_at）、draft、original、declaration、location、mentions、images；正文中引用的本地图片按顺序作为图片列表"`
+	Draft          bool   `json:"draft,omitempty" jsonschema:"仅保存到草稿箱不发布（可选），也可在 front matter 中设置 draft: true"`
+	DryRun         bool   `json:"dry_run,omitempty" jsonschema:"只解析和校验不发布（可选），返回转换后的标题、正文、图片列表和内容校验结果，用于发布前预览"`
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"幂等键（可选），超时重试时传入与首次相同的值可直接返回首次的发布结果，不会重复发布"`
+}
+
+// ComposeImagesArgs 图片合成的参数，拼图和水印至少设置一项
+type ComposeImagesArgs struct {
+	Images            []string `json:"images" jsonschema:"图片列表，支持HTTP/HTTPS链接、本地绝对路径、data URI 或 base64 编码的图片"`
+	Collage           bool     `json:"collage,omitempty" jsonschema:"是否用前几张图片（最多9张）拼成网格图（可选），拼图作为返回列表的第一张，需要至少2张图片"`
+	CollageColumns    int      `json:"collage_columns,omitempty" jsonschema:"拼图每行图片数（可选），默认按图片数量自动选择"`
+	CollageAspect     string   `json:"collage_aspect,omitempty" jsonschema:"拼图宽高比（可选），如 3:4|1:1|4:3，默认 3:4"`
+	WatermarkText     string   `json:"watermark_text,omitempty" jsonschema:"水印文字（可选），如 @品牌名，为每张图片（含拼图）添加"`
+	WatermarkLogo     string   `json:"watermark_logo,omitempty" jsonschema:"水印 Logo 图片（可选），本地路径或HTTP链接，建议使用透明背景的 PNG；与文字同时提供时 Logo 在左"`
+	WatermarkPosition string   `json:"watermark_position,omitempty" jsonschema:"水印位置（可选）: top-left|top-right|bottom-left|bottom-right|center，默认 bottom-right"`
+	WatermarkOpacity  float64  `json:"watermark_opacity,omitempty" jsonschema:"水印不透明度（可选），0-1，默认 0.6"`
+}
+
+// CancelPublishJobArgs 取消发布任务的参数
+type CancelPublishJobArgs struct {
+	JobID string `json:"job_id" jsonschema:"发布任务ID，从list_publish_jobs获取"`
+}
+
+// ResolveNoteURLArgs 解析链接的参数
+type ResolveNoteURLArgs struct {
+	URL string `json:"url" jsonschema:"小红书分享短链接（xhslink.com）、笔记链接（/explore/、/discovery/item/）或用户主页链接，也可直接粘贴整段分享文案"`
+}
+
 // 财联社相关参数定义
 
 // FetchCailiansheNewsArgs 获取财联社新闻参数
@@ -177,15 +371,35 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "publish_content",
-			Description: "发布小红书图文内容",
+			Description: "发布小红书图文内容（支持通过 publish_at 使用平台定时发布），确认发布成功后返回新笔记的 post_id、post_url 和 xsec_token",
 		},
 		withPanicRecovery("publish_content", func(ctx context.Context, req *mcp.CallToolRequest, args PublishContentArgs) (*mcp.CallToolResult, any, error) {
 			// 转换参数格式到现有的 handler
 			argsMap := map[string]interface{}{
-				"title":   args.Title,
-				"content": args.Content,
-				"images":  convertStringsToInterfaces(args.Images),
-				"tags":    convertStringsToInterfaces(args.Tags),
+				"title":           args.Title,
+				"content":         args.Content,
+				"images":          convertStringsToInterfaces(args.Images),
+				"tags":            convertStringsToInterfaces(args.Tags),
+				"publish_at":      args.PublishAt,
+				"draft":           args.Draft,
+				"visibility":      args.Visibility,
+				"original":        args.Original,
+				"declaration":     args.Declaration,
+				"mentions":        convertStringsToInterfaces(args.Mentions),
+				"location":        args.Location,
+				"image_aspect":    args.ImageAspect,
+				"image_fit":       args.ImageFit,
+				"image_format":    args.ImageFormat,
+				"image_max_kb":    args.ImageMaxKB,
+				"emoji_codes":     args.EmojiCodes,
+				"idempotency_key": args.IdempotencyKey,
+
+				"watermark_text":     args.WatermarkText,
+				"watermark_logo":     args.WatermarkLogo,
+				"watermark_position": args.WatermarkPosition,
+				"watermark_opacity":  args.WatermarkOpacity,
+				"collage":            args.CoverCollage,
+				"collage_columns":    args.CollageColumns,
 			}
 			result := appServer.handlePublishContent(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -226,6 +440,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 			argsMap := map[string]interface{}{
 				"feed_id":    args.FeedID,
 				"xsec_token": args.XsecToken,
+				"url":        args.URL,
 			}
 			result := appServer.handleGetFeedDetail(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -259,6 +474,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"feed_id":    args.FeedID,
 				"xsec_token": args.XsecToken,
 				"content":    args.Content,
+				"url":        args.URL,
 			}
 			result := appServer.handlePostComment(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -269,14 +485,25 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "publish_with_video",
-			Description: "发布小红书视频内容（仅支持本地单个视频文件）",
+			Description: "发布小红书视频内容（单个视频文件，支持本地路径或HTTP/HTTPS链接，支持通过 publish_at 使用平台定时发布），确认发布成功后返回新笔记的 post_id、post_url 和 xsec_token",
 		},
 		withPanicRecovery("publish_with_video", func(ctx context.Context, req *mcp.CallToolRequest, args PublishVideoArgs) (*mcp.CallToolResult, any, error) {
 			argsMap := map[string]interface{}{
-				"title":   args.Title,
-				"content": args.Content,
-				"video":   args.Video,
-				"tags":    convertStringsToInterfaces(args.Tags),
+				"title":           args.Title,
+				"content":         args.Content,
+				"video":           args.Video,
+				"tags":            convertStringsToInterfaces(args.Tags),
+				"publish_at":      args.PublishAt,
+				"draft":           args.Draft,
+				"visibility":      args.Visibility,
+				"original":        args.Original,
+				"declaration":     args.Declaration,
+				"mentions":        convertStringsToInterfaces(args.Mentions),
+				"location":        args.Location,
+				"cover_time":      args.CoverTime,
+				"cover_image":     args.CoverImage,
+				"emoji_codes":     args.EmojiCodes,
+				"idempotency_key": args.IdempotencyKey,
 			}
 			result := appServer.handlePublishVideo(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -294,6 +521,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"feed_id":    args.FeedID,
 				"xsec_token": args.XsecToken,
 				"unlike":     args.Unlike,
+				"url":        args.URL,
 			}
 			result := appServer.handleLikeFeed(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -311,13 +539,365 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"feed_id":    args.FeedID,
 				"xsec_token": args.XsecToken,
 				"unfavorite": args.Unfavorite,
+				"url":        args.URL,
 			}
 			result := appServer.handleFavoriteFeed(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
 
-	logrus.Infof("Registered %d MCP tools", 11)
+	// 工具 12: 解析分享链接
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "resolve_note_url",
+			Description: "解析小红书分享短链接或笔记/用户主页链接，返回笔记ID（feed_id）或用户ID（user_id）以及 xsec_token",
+		},
+		withPanicRecovery("resolve_note_url", func(ctx context.Context, req *mcp.CallToolRequest, args ResolveNoteURLArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleResolveNoteURL(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 13: 回复评论
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "reply_to_comment",
+			Description: "回复小红书笔记下的指定评论（按评论ID定位，必要时自动滚动加载和展开回复），回复后确认已出现在回复列表中",
+		},
+		withPanicRecovery("reply_to_comment", func(ctx context.Context, req *mcp.CallToolRequest, args ReplyCommentArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"feed_id":    args.FeedID,
+				"xsec_token": args.XsecToken,
+				"url":        args.URL,
+				"comment_id": args.CommentID,
+				"content":    args.Content,
+			}
+			result := appServer.handleReplyComment(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 14: 点赞评论
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "like_comment",
+			Description: "为笔记下的指定评论点赞或取消点赞（如已是目标状态则跳过），点击后确认状态已变化",
+		},
+		withPanicRecovery("like_comment", func(ctx context.Context, req *mcp.CallToolRequest, args LikeCommentArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"feed_id":    args.FeedID,
+				"xsec_token": args.XsecToken,
+				"url":        args.URL,
+				"comment_id": args.CommentID,
+				"unlike":     args.Unlike,
+			}
+			result := appServer.handleLikeComment(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 15: 删除评论
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "delete_comment",
+			Description: "删除当前账号在笔记下发表的评论（按评论ID或精确内容定位），删除后确认评论已消失，并返回被删除评论的元数据",
+		},
+		withPanicRecovery("delete_comment", func(ctx context.Context, req *mcp.CallToolRequest, args DeleteCommentArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"feed_id":    args.FeedID,
+				"xsec_token": args.XsecToken,
+				"url":        args.URL,
+				"comment_id": args.CommentID,
+				"content":    args.Content,
+			}
+			result := appServer.handleDeleteComment(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 16: 关注用户
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "follow_user",
+			Description: "关注指定小红书用户（如已关注将跳过），点击后确认关注状态已变化",
+		},
+		withPanicRecovery("follow_user", func(ctx context.Context, req *mcp.CallToolRequest, args FollowUserArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"user_id":    args.UserID,
+				"xsec_token": args.XsecToken,
+				"url":        args.URL,
+				"unfollow":   false,
+			}
+			result := appServer.handleFollowUser(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 17: 取消关注用户
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "unfollow_user",
+			Description: "取消关注指定小红书用户（如未关注将跳过），点击后确认关注状态已变化",
+		},
+		withPanicRecovery("unfollow_user", func(ctx context.Context, req *mcp.CallToolRequest, args FollowUserArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"user_id":    args.UserID,
+				"xsec_token": args.XsecToken,
+				"url":        args.URL,
+				"unfollow":   true,
+			}
+			result := appServer.handleFollowUser(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 18: 获取自己发布的笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_my_notes",
+			Description: "获取创作中心中自己发布的笔记列表及审核状态（审核中/已发布/未通过）",
+		},
+		withPanicRecovery("list_my_notes", func(ctx context.Context, req *mcp.CallToolRequest, args ListMyNotesArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListMyNotes(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 19: 编辑笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "edit_note",
+			Description: "编辑自己发布的笔记的标题、正文和标签，提交后重新读取笔记确认修改已保存（修改后需重新审核）",
+		},
+		withPanicRecovery("edit_note", func(ctx context.Context, req *mcp.CallToolRequest, args EditNoteArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"note_id": args.NoteID,
+				"title":   args.Title,
+				"content": args.Content,
+				"tags":    convertStringsToInterfaces(args.Tags),
+			}
+			result := appServer.handleEditNote(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 20: 删除笔记
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "delete_note",
+			Description: "删除自己发布的笔记（不可恢复，必须设置 confirm=true）",
+		},
+		withPanicRecovery("delete_note", func(ctx context.Context, req *mcp.CallToolRequest, args DeleteNoteArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"note_id": args.NoteID,
+				"confirm": args.Confirm,
+			}
+			result := appServer.handleDeleteNote(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 21: 加入发布队列
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "enqueue_publish",
+			Description: "将图文或视频发布任务加入本地发布队列，按执行时间依次自动发布，服务重启后任务不会丢失；点击发布前失败时自动重试，点击发布后未确认结果的不会重试",
+		},
+		withPanicRecovery("enqueue_publish", func(ctx context.Context, req *mcp.CallToolRequest, args EnqueuePublishArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"title":        args.Title,
+				"content":      args.Content,
+				"images":       convertStringsToInterfaces(args.Images),
+				"video":        args.Video,
+				"tags":         convertStringsToInterfaces(args.Tags),
+				"run_at":       args.RunAt,
+				"publish_at":   args.PublishAt,
+				"account":      args.Account,
+				"max_attempts": args.MaxAttempts,
+				"visibility":   args.Visibility,
+				"original":     args.Original,
+				"declaration":  args.Declaration,
+				"mentions":     convertStringsToInterfaces(args.Mentions),
+				"location":     args.Location,
+				"cover_time":   args.CoverTime,
+				"cover_image":  args.CoverImage,
+
+				"draft":           args.Draft,
+				"image_aspect":    args.ImageAspect,
+				"image_fit":       args.ImageFit,
+				"image_format":    args.ImageFormat,
+				"image_max_kb":    args.ImageMaxKB,
+				"emoji_codes":     args.EmojiCodes,
+				"idempotency_key": args.IdempotencyKey,
+
+				"watermark_text":     args.WatermarkText,
+				"watermark_logo":     args.WatermarkLogo,
+				"watermark_position": args.WatermarkPosition,
+				"watermark_opacity":  args.WatermarkOpacity,
+				"collage":            args.CoverCollage,
+				"collage_columns":    args.CollageColumns,
+			}
+			result := appServer.handleEnqueuePublish(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 22: 查看发布队列
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_publish_jobs",
+			Description: "查看本地发布队列中的任务及其状态、执行次数和错误信息",
+		},
+		withPanicRecovery("list_publish_jobs", func(ctx context.Context, req *mcp.CallToolRequest, args ListPublishJobsArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListPublishJobs(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 23: 修改发布任务执行时间
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "reschedule_publish_job",
+			Description: "修改等待中或已失败的发布任务的执行时间",
+		},
+		withPanicRecovery("reschedule_publish_job", func(ctx context.Context, req *mcp.CallToolRequest, args ReschedulePublishJobArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"job_id": args.JobID,
+				"run_at": args.RunAt,
+			}
+			result := appServer.handleReschedulePublishJob(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 24: 取消发布任务
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "cancel_publish_job",
+			Description: "取消等待中的发布任务",
+		},
+		withPanicRecovery("cancel_publish_job", func(ctx context.Context, req *mcp.CallToolRequest, args CancelPublishJobArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"job_id": args.JobID,
+			}
+			result := appServer.handleCancelPublishJob(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 25: 获取草稿箱
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_drafts",
+			Description: "获取创作中心草稿箱中的图文和视频草稿（草稿通过发布工具的 draft 参数保存）",
+		},
+		withPanicRecovery("list_drafts", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListDrafts(ctx)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 26: 发布草稿
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "publish_draft",
+			Description: "发布草稿箱中的指定草稿，用于人工审核通过后发布",
+		},
+		withPanicRecovery("publish_draft", func(ctx context.Context, req *mcp.CallToolRequest, args PublishDraftArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"draft_id": args.DraftID,
+			}
+			result := appServer.handlePublishDraft(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 27: 发布文字卡片
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "publish_text_cards",
+			Description: "将标题和文字渲染为 3:4 的文字卡片图片（过长自动分页），再作为图文笔记发布",
+		},
+		withPanicRecovery("publish_text_cards", func(ctx context.Context, req *mcp.CallToolRequest, args PublishTextCardsArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"title":           args.Title,
+				"content":         args.Content,
+				"card_title":      args.CardTitle,
+				"card_text":       args.CardText,
+				"theme":           args.Theme,
+				"watermark":       args.Watermark,
+				"font_path":       args.FontPath,
+				"tags":            convertStringsToInterfaces(args.Tags),
+				"publish_at":      args.PublishAt,
+				"draft":           args.Draft,
+				"visibility":      args.Visibility,
+				"original":        args.Original,
+				"declaration":     args.Declaration,
+				"idempotency_key": args.IdempotencyKey,
+			}
+			result := appServer.handlePublishTextCards(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 28: 内容校验
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "lint_content",
+			Description: "发布前校验标题、正文和标签：字数和标签数量限制、违禁词、外部链接、手机号、微信号等，返回带位置的错误和警告。有错误时 publish_content 会拒绝发布",
+		},
+		withPanicRecovery("lint_content", func(ctx context.Context, req *mcp.CallToolRequest, args LintContentArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"title":   args.Title,
+				"content": args.Content,
+				"tags":    convertStringsToInterfaces(args.Tags),
+			}
+			result := appServer.handleLintContent(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 29: 从 Markdown 文件发布
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "publish_markdown",
+			Description: "解析 Markdown 文件并发布为图文笔记：front matter 提供标题、标签、可见范围和定时发布时间，正文转为适合小红书的纯文本和表情，引用的本地图片按文档顺序上传。可先用 dry_run 预览",
+		},
+		withPanicRecovery("publish_markdown", func(ctx context.Context, req *mcp.CallToolRequest, args PublishMarkdownArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"file_path":       args.FilePath,
+				"draft":           args.Draft,
+				"dry_run":         args.DryRun,
+				"idempotency_key": args.IdempotencyKey,
+			}
+			result := appServer.handlePublishMarkdown(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	// 工具 30: 图片合成
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "compose_images",
+			Description: "为图片添加文字或 Logo 水印，或将多张图片拼成网格封面，返回处理后的本地图片路径，可直接用于 publish_content。publish_content 也可通过 watermark_*、cover_collage 参数在发布时完成相同处理",
+		},
+		withPanicRecovery("compose_images", func(ctx context.Context, req *mcp.CallToolRequest, args ComposeImagesArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"images":             convertStringsToInterfaces(args.Images),
+				"collage":            args.Collage,
+				"collage_columns":    args.CollageColumns,
+				"collage_aspect":     args.CollageAspect,
+				"watermark_text":     args.WatermarkText,
+				"watermark_logo":     args.WatermarkLogo,
+				"watermark_position": args.WatermarkPosition,
+				"watermark_opacity":  args.WatermarkOpacity,
+			}
+			result := appServer.handleComposeImages(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	logrus.Infof("Registered %d MCP tools", 30)
 }
 
 // convertToMCPResult 将自定义的 MCPToolResult 转换为官方 SDK 的格式
@@ -351,6 +931,19 @@ func convertToMCPResult(result *MCPToolResult) *mcp.CallToolResult {
 	}
 }
 
+// convertInterfacesToStrings 辅助函数：将 []interface{} 参数转换为 []string，忽略非字符串元素
+func convertInterfacesToStrings(v interface{}) []string {
+	items, _ := v.([]interface{})
+
+	var result []string
+	for _, item := range items {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
 // convertStringsToInterfaces 辅助函数：将 []string 转换为 []interface{}
 func convertStringsToInterfaces(strs []string) []interface{} {
 	result := make([]interface{}, len(strs))
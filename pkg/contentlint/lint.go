@@ -0,0 +1,288 @@
+package contentlint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+)
+
+// Severity 问题级别
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // 发布会失败或被限流，必须修改
+	SeverityWarning Severity = "warning" // 可能影响推荐，建议修改
+)
+
+// 规则名称
+const (
+	RuleTitleTooLong    = "title_too_long"
+	RuleContentTooLong  = "content_too_long"
+	RuleTooManyTags     = "too_many_tags"
+	RuleDuplicateTag    = "duplicate_tag"
+	RuleTagFormat       = "tag_format"
+	RuleBannedWord      = "banned_word"
+	RuleSensitiveWord   = "sensitive_word"
+	RuleExternalLink    = "external_link"
+	RulePhoneNumber     = "phone_number"
+	RuleWechatHandle    = "wechat_handle"
+	RuleContentHashtags = "content_hashtag"
+)
+
+// Input 待校验的内容
+type Input struct {
+	Title   string
+	Content string
+	Tags    []string
+}
+
+// Issue 校验发现的问题，Start/End 为所在字段中的字符位置（按 rune 计，左闭右开）
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Field    string   `json:"field"` // title/content/tags[i]
+	Message  string   `json:"message"`
+	Start    int      `json:"start"`
+	End      int      `json:"end"`
+	Text     string   `json:"text,omitempty"`
+}
+
+// Result 校验结果
+type Result struct {
+	Passed   bool    `json:"passed"` // 没有 error 级别的问题
+	Errors   []Issue `json:"errors"`
+	Warnings []Issue `json:"warnings"`
+}
+
+// Err 存在 error 级别的问题时返回汇总错误
+func (r *Result) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, 0, len(r.Errors))
+	for _, issue := range r.Errors {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", issue.Field, issue.Message))
+	}
+	return errors.Errorf("内容校验未通过（可先调用 lint_content 查看详情）: %s", strings.Join(msgs, "; "))
+}
+
+var (
+	linkPattern    = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s，。！？、）)]+|\b[a-z0-9][-a-z0-9]*\.(?:com|cn|net|org|io|cc|top|xyz|me|vip)\b(?:/[^\s，。！？、）)]*)?`)
+	phonePattern   = regexp.MustCompile(`(?:^|\D)(1[3-9]\d[- ]?\d{4}[- ]?\d{4})(?:\D|$)`)
+	wechatPattern  = regexp.MustCompile(`(?i)(?:微信号?|威信|薇信|v信|\bvx|\bwx|\bweixin|\bwechat)\s*[:：]?\s*([a-z][-_a-z0-9]{5,19})`)
+	hashtagPattern = regexp.MustCompile(`#[^\s#]+`)
+)
+
+// Linter 发布前内容校验器
+type Linter struct {
+	rules     Rules
+	banned    *regexp.Regexp
+	sensitive *regexp.Regexp
+}
+
+// New 根据规则创建校验器
+func New(rules Rules) *Linter {
+	allowed := make(map[string]bool, len(rules.AllowedWords))
+	for _, w := range rules.AllowedWords {
+		allowed[strings.ToLower(w)] = true
+	}
+
+	return &Linter{
+		rules:     rules,
+		banned:    compileWords(rules.BannedWords, allowed),
+		sensitive: compileWords(rules.SensitiveWords, allowed),
+	}
+}
+
+// compileWords 将词表编译为忽略大小写的正则，长词优先匹配
+func compileWords(words []string, allowed map[string]bool) *regexp.Regexp {
+	seen := make(map[string]bool, len(words))
+	var quoted []string
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		key := strings.ToLower(w)
+		if w == "" || seen[key] || allowed[key] {
+			continue
+		}
+		seen[key] = true
+		quoted = append(quoted, regexp.QuoteMeta(w))
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	return regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+}
+
+// Lint 校验标题、正文和标签
+func (l *Linter) Lint(in Input) *Result {
+	var issues []Issue
+
+	issues = append(issues, l.checkTitle(in.Title)...)
+	issues = append(issues, l.checkContent(in.Content)...)
+	issues = append(issues, l.checkTags(in.Tags)...)
+
+	issues = append(issues, l.checkText("title", in.Title)...)
+	issues = append(issues, l.checkText("content", in.Content)...)
+	for i, tag := range in.Tags {
+		issues = append(issues, l.checkWords(fmt.Sprintf("tags[%d]", i), tag)...)
+	}
+
+	result := &Result{Errors: []Issue{}, Warnings: []Issue{}}
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			result.Errors = append(result.Errors, issue)
+		} else {
+			result.Warnings = append(result.Warnings, issue)
+		}
+	}
+	result.Passed = len(result.Errors) == 0
+
+	return result
+}
+
+// checkTitle 标题宽度，中文占 2、英文占 1
+func (l *Linter) checkTitle(title string) []Issue {
+	width, total := 0, runewidth.StringWidth(title)
+	if total <= l.rules.MaxTitleWidth {
+		return nil
+	}
+
+	start := 0
+	for _, r := range title {
+		width += runewidth.RuneWidth(r)
+		if width > l.rules.MaxTitleWidth {
+			break
+		}
+		start++
+	}
+
+	return []Issue{{
+		Rule:     RuleTitleTooLong,
+		Severity: SeverityError,
+		Field:    "title",
+		Message:  fmt.Sprintf("标题长度超过限制：宽度 %d，最多 %d（中文占 2，英文占 1）", total, l.rules.MaxTitleWidth),
+		Start:    start,
+		End:      utf8.RuneCountInString(title),
+	}}
+}
+
+// checkContent 正文字数和正文中的话题标签
+func (l *Linter) checkContent(content string) []Issue {
+	var issues []Issue
+
+	if n := utf8.RuneCountInString(content); n > l.rules.MaxContentLength {
+		issues = append(issues, Issue{
+			Rule:     RuleContentTooLong,
+			Severity: SeverityError,
+			Field:    "content",
+			Message:  fmt.Sprintf("正文 %d 字，超过 %d 字限制", n, l.rules.MaxContentLength),
+			Start:    l.rules.MaxContentLength,
+			End:      n,
+		})
+	}
+
+	for _, loc := range hashtagPattern.FindAllStringIndex(content, -1) {
+		issues = append(issues, newIssue(RuleContentHashtags, SeverityWarning, "content", content, loc[0], loc[1],
+			"正文中的话题标签不会被识别为话题，请放到 tags 参数中"))
+	}
+
+	return issues
+}
+
+// checkTags 标签数量、重复和格式
+func (l *Linter) checkTags(tags []string) []Issue {
+	var issues []Issue
+
+	if len(tags) > l.rules.MaxTags {
+		issues = append(issues, Issue{
+			Rule:     RuleTooManyTags,
+			Severity: SeverityError,
+			Field:    "tags",
+			Message:  fmt.Sprintf("话题标签 %d 个，最多 %d 个", len(tags), l.rules.MaxTags),
+			Start:    l.rules.MaxTags,
+			End:      len(tags),
+		})
+	}
+
+	seen := make(map[string]bool, len(tags))
+	for i, tag := range tags {
+		field := fmt.Sprintf("tags[%d]", i)
+		name := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		n := utf8.RuneCountInString(tag)
+
+		if name == "" || strings.ContainsAny(strings.TrimSpace(tag), "# \t") {
+			issues = append(issues, Issue{Rule: RuleTagFormat, Severity: SeverityWarning, Field: field,
+				Message: "标签不能为空，不需要 # 前缀，也不能包含空格", Start: 0, End: n, Text: tag})
+		}
+
+		key := strings.ToLower(name)
+		if name != "" && seen[key] {
+			issues = append(issues, Issue{Rule: RuleDuplicateTag, Severity: SeverityWarning, Field: field,
+				Message: fmt.Sprintf("标签 %s 重复", name), Start: 0, End: n, Text: tag})
+		}
+		seen[key] = true
+	}
+
+	return issues
+}
+
+// checkText 检查外部链接、手机号、微信号和违禁词
+func (l *Linter) checkText(field, text string) []Issue {
+	var issues []Issue
+
+	for _, loc := range linkPattern.FindAllStringIndex(text, -1) {
+		issues = append(issues, newIssue(RuleExternalLink, SeverityError, field, text, loc[0], loc[1],
+			"包含外部链接，笔记会被限流"))
+	}
+	for _, loc := range phonePattern.FindAllStringSubmatchIndex(text, -1) {
+		issues = append(issues, newIssue(RulePhoneNumber, SeverityError, field, text, loc[2], loc[3],
+			"包含手机号，笔记会被限流"))
+	}
+	for _, loc := range wechatPattern.FindAllStringIndex(text, -1) {
+		issues = append(issues, newIssue(RuleWechatHandle, SeverityError, field, text, loc[0], loc[1],
+			"包含微信号，笔记会被限流"))
+	}
+
+	return append(issues, l.checkWords(field, text)...)
+}
+
+// checkWords 检查违禁词和敏感词
+func (l *Linter) checkWords(field, text string) []Issue {
+	var issues []Issue
+
+	if l.banned != nil {
+		for _, loc := range l.banned.FindAllStringIndex(text, -1) {
+			issues = append(issues, newIssue(RuleBannedWord, SeverityError, field, text, loc[0], loc[1],
+				fmt.Sprintf("包含违禁词“%s”", text[loc[0]:loc[1]])))
+		}
+	}
+	if l.sensitive != nil {
+		for _, loc := range l.sensitive.FindAllStringIndex(text, -1) {
+			issues = append(issues, newIssue(RuleSensitiveWord, SeverityWarning, field, text, loc[0], loc[1],
+				fmt.Sprintf("包含敏感词“%s”，可能影响推荐", text[loc[0]:loc[1]])))
+		}
+	}
+
+	return issues
+}
+
+// newIssue 将字节位置转换为字符位置并创建问题
+func newIssue(rule string, severity Severity, field, text string, start, end int, message string) Issue {
+	return Issue{
+		Rule:     rule,
+		Severity: severity,
+		Field:    field,
+		Message:  message,
+		Start:    utf8.RuneCountInString(text[:start]),
+		End:      utf8.RuneCountInString(text[:end]),
+		Text:     text[start:end],
+	}
+}
@@ -0,0 +1,116 @@
+package contentlint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// findIssue 按规则查找问题
+func findIssue(issues []Issue, rule string) *Issue {
+	for i := range issues {
+		if issues[i].Rule == rule {
+			return &issues[i]
+		}
+	}
+	return nil
+}
+
+func TestLintPassed(t *testing.T) {
+	result := New(DefaultRules()).Lint(Input{
+		Title:   "周末去哪玩",
+		Content: "第一次去这家咖啡店，环境很安静，适合看书。",
+		Tags:    []string{"咖啡", "周末"},
+	})
+	require.True(t, result.Passed)
+	require.Empty(t, result.Errors)
+	require.Empty(t, result.Warnings)
+	require.NoError(t, result.Err())
+}
+
+func TestLintLimits(t *testing.T) {
+	result := New(DefaultRules()).Lint(Input{
+		Title:   strings.Repeat("标题", 11),
+		Content: strings.Repeat("字", 1005),
+		Tags:    []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"},
+	})
+	require.False(t, result.Passed)
+	require.Error(t, result.Err())
+
+	title := findIssue(result.Errors, RuleTitleTooLong)
+	require.NotNil(t, title)
+	require.Equal(t, 20, title.Start)
+	require.Equal(t, 22, title.End)
+
+	content := findIssue(result.Errors, RuleContentTooLong)
+	require.NotNil(t, content)
+	require.Equal(t, 1000, content.Start)
+	require.Equal(t, 1005, content.End)
+
+	require.NotNil(t, findIssue(result.Errors, RuleTooManyTags))
+}
+
+func TestLintContactsAndWords(t *testing.T) {
+	content := "全网第一的好物！加微信 abc_123456 或打 138-1234-5678，详见 https://example.com/item 私信我"
+	result := New(DefaultRules()).Lint(Input{Title: "好物分享", Content: content})
+
+	banned := findIssue(result.Errors, RuleBannedWord)
+	require.NotNil(t, banned)
+	require.Equal(t, "全网第一", banned.Text)
+	require.Equal(t, 0, banned.Start)
+	require.Equal(t, 4, banned.End)
+
+	phone := findIssue(result.Errors, RulePhoneNumber)
+	require.NotNil(t, phone)
+	require.Equal(t, "138-1234-5678", phone.Text)
+	require.Equal(t, []rune(content)[phone.Start:phone.End], []rune(phone.Text))
+
+	wechat := findIssue(result.Errors, RuleWechatHandle)
+	require.NotNil(t, wechat)
+	require.Contains(t, wechat.Text, "abc_123456")
+
+	link := findIssue(result.Errors, RuleExternalLink)
+	require.NotNil(t, link)
+	require.Equal(t, "https://example.com/item", link.Text)
+
+	require.NotNil(t, findIssue(result.Warnings, RuleSensitiveWord))
+}
+
+func TestLintTags(t *testing.T) {
+	result := New(DefaultRules()).Lint(Input{Title: "标题", Content: "正文 #旅行", Tags: []string{"#旅行", "美食", "美食"}})
+	require.True(t, result.Passed)
+
+	format := findIssue(result.Warnings, RuleTagFormat)
+	require.NotNil(t, format)
+	require.Equal(t, "tags[0]", format.Field)
+
+	dup := findIssue(result.Warnings, RuleDuplicateTag)
+	require.NotNil(t, dup)
+	require.Equal(t, "tags[2]", dup.Field)
+
+	hashtag := findIssue(result.Warnings, RuleContentHashtags)
+	require.NotNil(t, hashtag)
+	require.Equal(t, 3, hashtag.Start)
+}
+
+func TestLoadRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"max_tags": 5, "banned_words": ["团购"], "allowed_words": ["私信"]}`), 0644))
+
+	rules, err := LoadRules(path)
+	require.NoError(t, err)
+	require.Equal(t, 5, rules.MaxTags)
+	require.Equal(t, 1000, rules.MaxContentLength)
+
+	result := New(rules).Lint(Input{Title: "标题", Content: "团购价，私信我"})
+	require.NotNil(t, findIssue(result.Errors, RuleBannedWord))
+	require.Nil(t, findIssue(result.Warnings, RuleSensitiveWord))
+
+	// 文件不存在时使用内置规则
+	rules, err = LoadRules(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	require.Equal(t, DefaultRules(), rules)
+}
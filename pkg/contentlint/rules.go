@@ -0,0 +1,77 @@
+package contentlint
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed default_rules.json
+var defaultRulesJSON []byte
+
+// Rules 发布前校验规则，可通过 JSON 文件配置
+type Rules struct {
+	MaxTitleWidth    int      `json:"max_title_width"`    // 标题最大宽度，中文占 2、英文占 1
+	MaxContentLength int      `json:"max_content_length"` // 正文最大字数
+	MaxTags          int      `json:"max_tags"`           // 最多话题标签数
+	BannedWords      []string `json:"banned_words"`       // 违禁词，出现即报错
+	SensitiveWords   []string `json:"sensitive_words"`    // 敏感词，出现时给出警告
+	AllowedWords     []string `json:"allowed_words"`      // 白名单，从违禁词和敏感词中剔除
+}
+
+// DefaultRules 返回内置规则
+func DefaultRules() Rules {
+	var rules Rules
+	if err := json.Unmarshal(defaultRulesJSON, &rules); err != nil {
+		panic("invalid default_rules.json: " + err.Error())
+	}
+	return rules
+}
+
+// LoadRules 加载规则文件并与内置规则合并：数值非零时覆盖内置值，词表追加到内置词表。
+// 文件不存在时返回内置规则。
+func LoadRules(path string) (Rules, error) {
+	rules := DefaultRules()
+	if path == "" {
+		return rules, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return rules, nil
+	}
+	if err != nil {
+		return rules, errors.Wrapf(err, "读取校验规则失败: %s", path)
+	}
+
+	var custom Rules
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return rules, errors.Wrapf(err, "解析校验规则失败: %s", path)
+	}
+
+	if custom.MaxTitleWidth > 0 {
+		rules.MaxTitleWidth = custom.MaxTitleWidth
+	}
+	if custom.MaxContentLength > 0 {
+		rules.MaxContentLength = custom.MaxContentLength
+	}
+	if custom.MaxTags > 0 {
+		rules.MaxTags = custom.MaxTags
+	}
+	rules.BannedWords = append(rules.BannedWords, custom.BannedWords...)
+	rules.SensitiveWords = append(rules.SensitiveWords, custom.SensitiveWords...)
+	rules.AllowedWords = append(rules.AllowedWords, custom.AllowedWords...)
+
+	return rules, nil
+}
+
+// GetRulesFilePath 获取校验规则文件路径
+func GetRulesFilePath() string {
+	path := os.Getenv("CONTENT_LINT_RULES_PATH")
+	if path == "" {
+		path = "content_lint_rules.json"
+	}
+	return path
+}
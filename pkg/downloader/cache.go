@@ -0,0 +1,177 @@
+package downloader
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// staleTempAge 超过该时长的下载临时文件视为中断遗留
+const staleTempAge = time.Hour
+
+// cleanInterval 同一进程中两次自动清理的最小间隔
+const cleanInterval = time.Hour
+
+// 下载器生成的文件名前缀
+const (
+	cachedImagePrefix  = "img_"
+	downloadTempPrefix = ".download-"
+	preprocessedPrefix = "pre_"
+)
+
+// generatedPrefixes 同一目录中由本服务生成的文件，包括下载缓存、预处理结果，
+// 以及拼图（collage_）、水印（wm_）和文字卡片（card_）的输出，都可以按需重新生成，按相同策略清理。
+// 其他文件（如调用方放入目录的图片）不做清理
+var generatedPrefixes = []string{cachedImagePrefix, downloadTempPrefix, preprocessedPrefix, "collage_", "wm_", "card_"}
+
+// CachePolicy 缓存清理策略
+type CachePolicy struct {
+	TTL      time.Duration // 超过该时长未使用的文件被删除，0 表示不按时间清理
+	MaxBytes int64         // 缓存总大小上限，超过时删除最久未使用的文件，0 表示不限制
+}
+
+// DefaultCachePolicy 从配置读取图片缓存清理策略
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{
+		TTL:      configs.GetImagesCacheTTL(),
+		MaxBytes: configs.GetImagesCacheMaxBytes(),
+	}
+}
+
+// CleanCache 按策略清理 dir 中本服务生成的文件，文件的修改时间即最近使用时间。返回删除的文件数。
+func CleanCache(dir string, policy CachePolicy) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, errors.Wrap(err, "failed to read cache dir")
+	}
+
+	type cached struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	now := time.Now()
+	removed := 0
+	var files []cached
+	var total int64
+
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !isCacheFile(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		age := now.Sub(info.ModTime())
+
+		expired := policy.TTL > 0 && age > policy.TTL
+		staleTemp := strings.HasPrefix(e.Name(), downloadTempPrefix) && age > staleTempAge
+		if expired || staleTemp {
+			if os.Remove(path) == nil {
+				removed++
+			}
+			continue
+		}
+
+		files = append(files, cached{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	// 超过大小上限时从最久未使用的文件开始删除
+	if policy.MaxBytes > 0 && total > policy.MaxBytes {
+		sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+		for _, f := range files {
+			if total <= policy.MaxBytes {
+				break
+			}
+			if os.Remove(f.path) == nil {
+				removed++
+				total -= f.size
+			}
+		}
+	}
+
+	// URL 索引指向的文件可能已被删除，过期的索引一并清理
+	removed += cleanURLIndex(filepath.Join(dir, urlIndexDir), dir, policy.TTL)
+
+	return removed, nil
+}
+
+// isCacheFile 是否为本服务生成的可清理文件
+func isCacheFile(name string) bool {
+	for _, prefix := range generatedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanURLIndex 删除过期或指向已删除文件的 URL 索引
+func cleanURLIndex(indexDir, dir string, ttl time.Duration) int {
+	entries, err := os.ReadDir(indexDir)
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, e := range entries {
+		path := filepath.Join(indexDir, e.Name())
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		stale := ttl > 0 && time.Since(info.ModTime()) > ttl
+		if !stale {
+			name, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			_, err = os.Stat(filepath.Join(dir, filepath.Base(strings.TrimSpace(string(name)))))
+			stale = os.IsNotExist(err)
+		}
+		if stale && os.Remove(path) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+var (
+	cleanMu   sync.Mutex
+	lastClean = map[string]time.Time{}
+)
+
+// maybeCleanCache 按默认策略清理缓存，同一目录每小时最多清理一次
+func maybeCleanCache(dir string) {
+	cleanMu.Lock()
+	if time.Since(lastClean[dir]) < cleanInterval {
+		cleanMu.Unlock()
+		return
+	}
+	lastClean[dir] = time.Now()
+	cleanMu.Unlock()
+
+	removed, err := CleanCache(dir, DefaultCachePolicy())
+	if err != nil {
+		logrus.Warnf("清理图片缓存失败: %v", err)
+		return
+	}
+	if removed > 0 {
+		logrus.Infof("清理图片缓存: 删除 %d 个文件", removed)
+	}
+}
@@ -0,0 +1,207 @@
+package downloader
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testPNG(t *testing.T, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, size, size))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDownloadImagesConcurrent(t *testing.T) {
+	images := map[string][]byte{
+		"/a.png": testPNG(t, 1),
+		"/b.png": testPNG(t, 2),
+		"/c.png": testPNG(t, 1), // 与 a.png 内容相同
+	}
+
+	var inFlight, maxInFlight, requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write(images[r.URL.Path])
+	}))
+	defer srv.Close()
+
+	d := NewImageDownloader(t.TempDir())
+	d.concurrency = 2
+
+	urls := []string{srv.URL + "/b.png", srv.URL + "/a.png", srv.URL + "/c.png"}
+	paths, err := d.DownloadImages(urls)
+	if err != nil {
+		t.Fatalf("DownloadImages failed: %v", err)
+	}
+
+	// 返回顺序与输入一致
+	for i, u := range urls {
+		got, _ := os.ReadFile(paths[i])
+		if !bytes.Equal(got, images[u[len(srv.URL):]]) {
+			t.Errorf("paths[%d] content mismatch for %s", i, u)
+		}
+	}
+
+	// 相同内容保存为同一个文件
+	if paths[1] != paths[2] {
+		t.Errorf("same content should reuse the same file: %s, %s", paths[1], paths[2])
+	}
+	if maxInFlight > 2 {
+		t.Errorf("max concurrent downloads = %d, expected <= 2", maxInFlight)
+	}
+
+	// 重复的 URL 直接复用，不再请求
+	before := atomic.LoadInt32(&requests)
+	again, err := d.DownloadImage(urls[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != paths[0] || atomic.LoadInt32(&requests) != before {
+		t.Errorf("repeated URL should reuse downloaded file without a new request")
+	}
+}
+
+func TestDownloadImageRetryAndLimits(t *testing.T) {
+	img := testPNG(t, 4)
+	var flaky int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky.png":
+			if atomic.AddInt32(&flaky, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write(img)
+		case "/missing.png":
+			w.WriteHeader(http.StatusNotFound)
+		case "/large.png":
+			w.Write(bytes.Repeat(img, 100))
+		default:
+			w.Write([]byte("<html>not an image</html>"))
+		}
+	}))
+	defer srv.Close()
+
+	d := NewImageDownloader(t.TempDir())
+	d.retryDelay = time.Millisecond
+	d.maxBytes = int64(len(img) * 10)
+
+	if _, err := d.DownloadImage(srv.URL + "/flaky.png"); err != nil {
+		t.Errorf("flaky download should succeed after retries: %v", err)
+	}
+	if flaky != 3 {
+		t.Errorf("flaky requests = %d, expected 3", flaky)
+	}
+
+	for _, path := range []string{"/missing.png", "/large.png", "/page.html"} {
+		if _, err := d.DownloadImage(srv.URL + path); err == nil {
+			t.Errorf("%s: expected error", path)
+		}
+	}
+
+	// 失败的下载不留下临时文件
+	entries, _ := os.ReadDir(d.savePath)
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".download-") {
+			t.Errorf("temp file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestCleanCache(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	write := func(name string, size int, age time.Duration) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	expired := write("img_expired.png", 10, 48*time.Hour)
+	oldest := write("img_oldest.png", 100, 3*time.Hour)
+	recent := write("img_recent.png", 100, time.Minute)
+	temp := write(".download-123", 10, 2*time.Hour)
+	// 拼图、水印、卡片和预处理输出可以重新生成，按相同策略清理
+	outputs := []string{
+		write("collage_abc.jpg", 100, 48*time.Hour),
+		write("wm_abc.jpg", 100, 48*time.Hour),
+		write("card_abc_1.png", 100, 48*time.Hour),
+		write("pre_abc.jpg", 100, 48*time.Hour),
+	}
+	// 不是本服务生成的文件不清理
+	other := write("photo.jpg", 100, 48*time.Hour)
+
+	// URL 索引指向已过期的文件
+	if err := os.MkdirAll(filepath.Join(dir, urlIndexDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	index := filepath.Join(dir, urlIndexDir, "abc")
+	if err := os.WriteFile(index, []byte("img_expired.png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := CleanCache(dir, CachePolicy{TTL: 24 * time.Hour, MaxBytes: 150})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 8 {
+		t.Errorf("removed = %d, expected 8", removed)
+	}
+
+	for _, path := range append(outputs, expired, oldest, temp, index) {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", filepath.Base(path))
+		}
+	}
+	for _, path := range []string{other, recent} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should be kept: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestProcessImagesKeepsOrder(t *testing.T) {
+	img := testPNG(t, 3)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(img)
+	}))
+	defer srv.Close()
+
+	p := &ImageProcessor{downloader: NewImageDownloader(t.TempDir())}
+	paths, err := p.ProcessImages([]string{"/local/1.jpg", srv.URL + "/2.png", "/local/3.jpg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) != 3 || paths[0] != "/local/1.jpg" || paths[2] != "/local/3.jpg" || !strings.HasPrefix(filepath.Base(paths[1]), "img_") {
+		t.Errorf("unexpected paths: %s", fmt.Sprint(paths))
+	}
+}
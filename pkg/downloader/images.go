@@ -9,16 +9,33 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/h2non/filetype"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
-// ImageDownloader 图片下载器
+const (
+	// DefaultMaxImageBytes 单张图片的下载大小上限
+	DefaultMaxImageBytes int64 = 50 << 20
+	// DefaultDownloadConcurrency 同时下载的图片数
+	DefaultDownloadConcurrency = 4
+	// defaultDownloadAttempts 网络错误、5xx 和 429 时的最大尝试次数
+	defaultDownloadAttempts = 3
+	// urlIndexDir 记录 URL 对应的已下载文件，重复的 URL 不再发起请求
+	urlIndexDir = ".urls"
+)
+
+// ImageDownloader 图片下载器。图片按内容哈希命名保存，相同内容只保存一份。
 type ImageDownloader struct {
-	savePath   string
-	httpClient *http.Client
+	savePath    string
+	httpClient  *http.Client
+	maxBytes    int64
+	concurrency int
+	attempts    int
+	retryDelay  time.Duration
 }
 
 // NewImageDownloader 创建图片下载器
@@ -33,6 +50,10 @@ func NewImageDownloader(savePath string) *ImageDownloader {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		maxBytes:    DefaultMaxImageBytes,
+		concurrency: DefaultDownloadConcurrency,
+		attempts:    defaultDownloadAttempts,
+		retryDelay:  500 * time.Millisecond,
 	}
 }
 
@@ -44,66 +65,156 @@ func (d *ImageDownloader) DownloadImage(imageURL string) (string, error) {
 		return "", errors.New("invalid image URL format")
 	}
 
-	// 下载图片数据
+	// 同一 URL 已下载过时直接复用
+	if path, ok := d.lookupURL(imageURL); ok {
+		touch(path)
+		touch(d.urlIndexPath(imageURL))
+		return path, nil
+	}
+
+	var (
+		path  string
+		err   error
+		retry bool
+	)
+	for attempt := 1; attempt <= d.attempts; attempt++ {
+		path, retry, err = d.download(imageURL)
+		if err == nil || !retry {
+			break
+		}
+		if attempt < d.attempts {
+			logrus.Warnf("图片下载失败，准备重试（%d/%d）: %s: %v", attempt, d.attempts, imageURL, err)
+			time.Sleep(d.retryDelay * time.Duration(attempt))
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	d.rememberURL(imageURL, path)
+	return path, nil
+}
+
+// download 边下载边写入临时文件并计算内容哈希，完成后按哈希重命名。返回的 retry 表示是否值得重试。
+func (d *ImageDownloader) download(imageURL string) (path string, retry bool, err error) {
 	resp, err := d.httpClient.Get(imageURL)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to download image")
+		return "", true, errors.Wrap(err, "failed to download image")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("download failed with status: %d", resp.StatusCode)
+		retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return "", retry, fmt.Errorf("download failed with status: %d", resp.StatusCode)
 	}
+	if resp.ContentLength > d.maxBytes {
+		return "", false, fmt.Errorf("image too large: %d bytes, limit %d bytes", resp.ContentLength, d.maxBytes)
+	}
+
+	// 读取响应失败（连接中断等）时可以重试，内容校验失败时不重试
+	body := &readErrRecorder{r: resp.Body}
+	path, err = d.save(body)
+	return path, body.err != nil, err
+}
+
+// save 边写入临时文件边计算内容哈希，校验为图片后按哈希重命名，相同内容的文件已存在时直接复用
+func (d *ImageDownloader) save(r io.Reader) (string, error) {
+	tmp, err := os.CreateTemp(d.savePath, downloadTempPrefix+"*")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create temp file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
 
-	// 读取图片数据
-	imageData, err := io.ReadAll(resp.Body)
+	// 多读 1 字节用于判断是否超过大小上限
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(r, d.maxBytes+1))
 	if err != nil {
 		return "", errors.Wrap(err, "failed to read image data")
 	}
+	if n > d.maxBytes {
+		return "", fmt.Errorf("image too large: exceeds %d bytes", d.maxBytes)
+	}
 
 	// 检测图片格式
-	kind, err := filetype.Match(imageData)
+	head := make([]byte, 262)
+	m, _ := tmp.ReadAt(head, 0)
+	if !filetype.IsImage(head[:m]) {
+		return "", errors.New("file is not a valid image")
+	}
+	kind, err := filetype.Match(head[:m])
 	if err != nil {
 		return "", errors.Wrap(err, "failed to detect file type")
 	}
 
-	if !filetype.IsImage(imageData) {
-		return "", errors.New("downloaded file is not a valid image")
-	}
-
-	// 生成唯一文件名
-	fileName := d.generateFileName(imageURL, kind.Extension)
-	filePath := filepath.Join(d.savePath, fileName)
+	path := filepath.Join(d.savePath, d.generateFileName(fmt.Sprintf("%x", h.Sum(nil)), kind.Extension))
 
-	// 如果文件已存在，直接返回路径
-	if _, err := os.Stat(filePath); err == nil {
-		return filePath, nil
+	// 相同内容的文件已存在时直接复用
+	if _, err := os.Stat(path); err == nil {
+		touch(path)
+		return path, nil
 	}
 
-	// 保存到文件
-	if err := os.WriteFile(filePath, imageData, 0644); err != nil {
+	if err := tmp.Close(); err != nil {
+		return "", errors.Wrap(err, "failed to save image")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
 		return "", errors.Wrap(err, "failed to save image")
 	}
 
-	return filePath, nil
+	return path, nil
 }
 
-// DownloadImages 批量下载图片
+// readErrRecorder 记录读取过程中的错误
+type readErrRecorder struct {
+	r   io.Reader
+	err error
+}
+
+func (r *readErrRecorder) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// DownloadImages 并发下载图片，返回的路径与 imageURLs 顺序一致
 func (d *ImageDownloader) DownloadImages(imageURLs []string) ([]string, error) {
-	var localPaths []string
-	var errs []error
+	paths := make([]string, len(imageURLs))
+	errs := make([]error, len(imageURLs))
 
-	for _, imageURL := range imageURLs {
-		localPath, err := d.DownloadImage(imageURL)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("failed to download %s: %w", imageURL, err))
+	sem := make(chan struct{}, max(d.concurrency, 1))
+	var wg sync.WaitGroup
+	for i, imageURL := range imageURLs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, imageURL string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			localPath, err := d.DownloadImage(imageURL)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to download %s: %w", imageURL, err)
+				return
+			}
+			paths[i] = localPath
+		}(i, imageURL)
+	}
+	wg.Wait()
+
+	var localPaths []string
+	var failed []error
+	for i := range imageURLs {
+		if errs[i] != nil {
+			failed = append(failed, errs[i])
 			continue
 		}
-		localPaths = append(localPaths, localPath)
+		localPaths = append(localPaths, paths[i])
 	}
 
-	if len(errs) > 0 {
-		return localPaths, fmt.Errorf("download errors occurred: %v", errs)
+	if len(failed) > 0 {
+		return localPaths, fmt.Errorf("download errors occurred: %v", failed)
 	}
 
 	return localPaths, nil
@@ -111,6 +222,11 @@ func (d *ImageDownloader) DownloadImages(imageURLs []string) ([]string, error) {
 
 // isValidImageURL 检查是否为有效的图片URL
 func (d *ImageDownloader) isValidImageURL(rawURL string) bool {
+	return isValidHTTPURL(rawURL)
+}
+
+// isValidHTTPURL 检查是否为有效的 http/https URL
+func isValidHTTPURL(rawURL string) bool {
 	// 检查是否以http/https开头
 	if !strings.HasPrefix(strings.ToLower(rawURL), "http://") &&
 		!strings.HasPrefix(strings.ToLower(rawURL), "https://") {
@@ -126,19 +242,49 @@ func (d *ImageDownloader) isValidImageURL(rawURL string) bool {
 	return parsedURL.Scheme != "" && parsedURL.Host != ""
 }
 
-// generateFileName 生成唯一的文件名
-func (d *ImageDownloader) generateFileName(imageURL, extension string) string {
-	// 使用URL的SHA256哈希作为文件名，确保唯一性
+// generateFileName 根据图片内容的 SHA256 生成文件名，相同内容得到相同文件名
+func (d *ImageDownloader) generateFileName(contentHash, extension string) string {
+	if len(contentHash) > 32 {
+		contentHash = contentHash[:32]
+	}
+	return fmt.Sprintf("%s%s.%s", cachedImagePrefix, contentHash, extension)
+}
+
+// urlIndexPath URL 索引文件路径，文件内容为已下载图片的文件名
+func (d *ImageDownloader) urlIndexPath(imageURL string) string {
 	hash := sha256.Sum256([]byte(imageURL))
-	hashStr := fmt.Sprintf("%x", hash)
+	return filepath.Join(d.savePath, urlIndexDir, fmt.Sprintf("%x", hash[:16]))
+}
+
+// lookupURL 查找 URL 已下载的文件，文件已被清理时返回 false
+func (d *ImageDownloader) lookupURL(imageURL string) (string, bool) {
+	name, err := os.ReadFile(d.urlIndexPath(imageURL))
+	if err != nil {
+		return "", false
+	}
 
-	// 取前16位哈希值作为文件名
-	shortHash := hashStr[:16]
+	path := filepath.Join(d.savePath, filepath.Base(strings.TrimSpace(string(name))))
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
 
-	// 添加时间戳确保更好的唯一性
-	timestamp := time.Now().Unix()
+// rememberURL 记录 URL 对应的文件，失败时只影响复用
+func (d *ImageDownloader) rememberURL(imageURL, path string) {
+	index := d.urlIndexPath(imageURL)
+	if err := os.MkdirAll(filepath.Dir(index), 0755); err != nil {
+		return
+	}
+	if err := os.WriteFile(index, []byte(filepath.Base(path)), 0644); err != nil {
+		logrus.Warnf("failed to save image url index: %v", err)
+	}
+}
 
-	return fmt.Sprintf("img_%s_%d.%s", shortHash, timestamp, extension)
+// touch 更新文件修改时间，缓存清理按修改时间淘汰最久未使用的文件
+func touch(path string) {
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
 }
 
 // IsImageURL 判断字符串是否为图片URL
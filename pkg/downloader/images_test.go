@@ -78,10 +78,10 @@ func TestImageDownloader_isValidImageURL(t *testing.T) {
 func TestImageDownloader_generateFileName(t *testing.T) {
 	downloader := NewImageDownloader(os.TempDir())
 
-	url := "https://example.com/image.jpg"
+	hash := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
 	extension := "jpg"
 
-	fileName1 := downloader.generateFileName(url, extension)
+	fileName1 := downloader.generateFileName(hash, extension)
 
 	// 文件名应该包含扩展名
 	if filepath.Ext(fileName1) != "."+extension {
@@ -93,10 +93,15 @@ func TestImageDownloader_generateFileName(t *testing.T) {
 		t.Errorf("fileName should start with img_, got %s", fileName1)
 	}
 
-	// 不同URL应该生成不同的文件名
-	url2 := "https://example.com/different.jpg"
-	fileName2 := downloader.generateFileName(url2, extension)
+	// 相同内容应该生成相同的文件名
+	if fileName := downloader.generateFileName(hash, extension); fileName != fileName1 {
+		t.Errorf("same content should generate same file name, got %s and %s", fileName1, fileName)
+	}
+
+	// 不同内容应该生成不同的文件名
+	hash2 := "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"
+	fileName2 := downloader.generateFileName(hash2, extension)
 	if fileName1 == fileName2 {
-		t.Errorf("different URLs should generate different file names")
+		t.Errorf("different content should generate different file names")
 	}
 }
@@ -0,0 +1,90 @@
+package downloader
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/h2non/filetype"
+	"github.com/pkg/errors"
+)
+
+// minBase64ImageLen 短于该长度的字符串不当作 base64 图片，避免把短的本地路径误判为 base64
+const minBase64ImageLen = 64
+
+// IsDataURI 判断是否为 data: URI，如 data:image/png;base64,iVBORw0...
+func IsDataURI(s string) bool {
+	return len(s) > 5 && strings.EqualFold(s[:5], "data:")
+}
+
+// IsBase64Image 判断是否为 base64 编码的图片（不含 data: 前缀）。
+// 只有能解码且文件头为图片的字符串才会被识别，其余按本地路径处理。
+func IsBase64Image(s string) bool {
+	if len(s) < minBase64ImageLen || IsImageURL(s) || IsDataURI(s) {
+		return false
+	}
+	data, err := decodeBase64(s)
+	return err == nil && filetype.IsImage(data)
+}
+
+// DecodeInlineImage 解码 data: URI 或 base64 字符串
+func DecodeInlineImage(s string) ([]byte, error) {
+	if !IsDataURI(s) {
+		return decodeBase64(s)
+	}
+
+	meta, payload, ok := strings.Cut(s[5:], ",")
+	if !ok {
+		return nil, errors.New("invalid data URI: missing comma")
+	}
+
+	params := strings.Split(meta, ";")
+	if mediaType := strings.TrimSpace(params[0]); mediaType != "" && !strings.HasPrefix(strings.ToLower(mediaType), "image/") {
+		return nil, errors.Errorf("data URI is not an image: %s", mediaType)
+	}
+	for _, param := range params[1:] {
+		if strings.EqualFold(strings.TrimSpace(param), "base64") {
+			return decodeBase64(payload)
+		}
+	}
+
+	data, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data URI")
+	}
+	return []byte(data), nil
+}
+
+// decodeBase64 兼容标准和 URL 安全字母表、有无填充以及换行
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n':
+			return -1
+		}
+		return r
+	}, s)
+
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if data, err := enc.DecodeString(s); err == nil {
+			return data, nil
+		}
+	}
+	return nil, errors.New("invalid base64 image data")
+}
+
+// SaveInlineImage 解码 data: URI 或 base64 图片并保存到图片目录，返回本地路径
+func (d *ImageDownloader) SaveInlineImage(s string) (string, error) {
+	data, err := DecodeInlineImage(s)
+	if err != nil {
+		return "", err
+	}
+	return d.save(bytes.NewReader(data))
+}
+
+// SaveImage 将上传的图片保存到图片目录，返回本地路径
+func (d *ImageDownloader) SaveImage(r io.Reader) (string, error) {
+	return d.save(r)
+}
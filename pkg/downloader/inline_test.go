@@ -0,0 +1,92 @@
+package downloader
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDecodeInlineImage(t *testing.T) {
+	img := testPNG(t, 2)
+	std := base64.StdEncoding.EncodeToString(img)
+
+	tests := []struct {
+		name  string
+		input string
+		ok    bool
+	}{
+		{"data uri", "data:image/png;base64," + std, true},
+		{"data uri without media type", "data:;base64," + std, true},
+		{"raw base64", std, true},
+		{"url-safe base64 without padding", base64.RawURLEncoding.EncodeToString(img), true},
+		{"base64 with line breaks", std[:40] + "\n" + std[40:], true},
+		{"non-image data uri", "data:text/plain;base64,aGVsbG8=", false},
+		{"missing comma", "data:image/png;base64", false},
+		{"invalid base64", "not base64!", false},
+	}
+
+	for _, test := range tests {
+		data, err := DecodeInlineImage(test.input)
+		if (err == nil) != test.ok {
+			t.Errorf("%s: err = %v, expected ok = %v", test.name, err, test.ok)
+			continue
+		}
+		if test.ok && !bytes.Equal(data, img) {
+			t.Errorf("%s: decoded data mismatch", test.name)
+		}
+	}
+}
+
+func TestIsBase64Image(t *testing.T) {
+	img := testPNG(t, 2)
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{base64.StdEncoding.EncodeToString(img), true},
+		{"/Users/user/Pictures/image.jpg", false},
+		{"https://example.com/image.jpg", false},
+		{"data:image/png;base64," + base64.StdEncoding.EncodeToString(img), false},
+		// 能解码但不是图片
+		{base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("text"), 32)), false},
+	}
+
+	for _, test := range tests {
+		if result := IsBase64Image(test.input); result != test.expected {
+			t.Errorf("IsBase64Image(%.40q) = %v, expected %v", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestProcessInlineImages(t *testing.T) {
+	img := testPNG(t, 5)
+	p := &ImageProcessor{downloader: NewImageDownloader(t.TempDir())}
+
+	paths, err := p.ProcessImages([]string{
+		"data:image/png;base64," + base64.StdEncoding.EncodeToString(img),
+		"/local/2.jpg",
+		base64.StdEncoding.EncodeToString(img),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) != 3 || paths[1] != "/local/2.jpg" {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+	// 相同内容保存为同一个文件
+	if paths[0] != paths[2] || !strings.HasSuffix(paths[0], ".png") {
+		t.Errorf("inline images should be saved by content hash: %v", paths)
+	}
+	if got, _ := os.ReadFile(paths[0]); !bytes.Equal(got, img) {
+		t.Errorf("saved image content mismatch")
+	}
+
+	// 解码后不是图片时报错
+	if _, err := p.ProcessImages([]string{"data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("not an image"))}); err == nil {
+		t.Errorf("expected error for invalid image data")
+	}
+}
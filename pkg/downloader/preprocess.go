@@ -0,0 +1,494 @@
+package downloader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // 注册 GIF 解码器
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/h2non/filetype"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册 WebP 解码器
+)
+
+// 小红书图片上传限制
+const (
+	DefaultMaxSide  = 4096     // 最长边像素上限
+	DefaultMaxBytes = 20 << 20 // 单张图片大小上限
+)
+
+// maxDecodePixels 解码前允许的最大像素数。解码后每像素至少占 4 字节，
+// 压缩率很高的 PNG/WebP 几十 MB 就可能有上万像素的边长，解码前按文件头中的尺寸拒绝
+const maxDecodePixels = 100_000_000
+
+// 目标宽高比
+const (
+	Aspect3x4 = "3:4"
+	Aspect1x1 = "1:1"
+	Aspect4x3 = "4:3"
+)
+
+// 宽高比适配方式
+const (
+	FitCrop = "crop" // 居中裁剪
+	FitPad  = "pad"  // 补白边
+)
+
+// 输出格式
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+)
+
+const (
+	defaultJPEGQuality = 92
+	minJPEGQuality     = 60
+	minScaledSide      = 720 // 压缩文件大小时最长边不低于该值
+)
+
+// aspectRatios 支持的宽高比
+var aspectRatios = map[string][2]int{
+	Aspect3x4: {3, 4},
+	Aspect1x1: {1, 1},
+	Aspect4x3: {4, 3},
+}
+
+// PreprocessOptions 图片预处理选项，零值表示使用默认设置：
+// 转换为 JPEG（带透明通道的 PNG 保持 PNG），缩放到平台尺寸限制内，去除 EXIF 等元数据
+type PreprocessOptions struct {
+	Aspect   string `json:"aspect,omitempty"`    // 目标宽高比：3:4|1:1|4:3，为空保持原比例
+	Fit      string `json:"fit,omitempty"`       // 宽高比适配方式：crop|pad，默认 crop
+	Format   string `json:"format,omitempty"`    // 输出格式：jpeg|png，为空自动选择
+	MaxSide  int    `json:"max_side,omitempty"`  // 最长边像素上限，默认 DefaultMaxSide
+	MaxBytes int64  `json:"max_bytes,omitempty"` // 单张文件大小上限，默认 DefaultMaxBytes
+}
+
+// Normalize 校验选项并填充默认值
+func (o PreprocessOptions) Normalize() (PreprocessOptions, error) {
+	o.Aspect = strings.TrimSpace(o.Aspect)
+	if o.Aspect != "" {
+		if _, ok := aspectRatios[o.Aspect]; !ok {
+			return o, errors.Errorf("不支持的宽高比: %s，可选值: 3:4|1:1|4:3", o.Aspect)
+		}
+	}
+
+	o.Fit = strings.ToLower(strings.TrimSpace(o.Fit))
+	switch o.Fit {
+	case "":
+		o.Fit = FitCrop
+	case FitCrop, FitPad:
+	default:
+		return o, errors.Errorf("不支持的适配方式: %s，可选值: crop|pad", o.Fit)
+	}
+
+	o.Format = strings.ToLower(strings.TrimSpace(o.Format))
+	switch o.Format {
+	case "", FormatJPEG, FormatPNG:
+	case "jpg":
+		o.Format = FormatJPEG
+	default:
+		return o, errors.Errorf("不支持的输出格式: %s，可选值: jpeg|png", o.Format)
+	}
+
+	if o.MaxSide <= 0 || o.MaxSide > DefaultMaxSide {
+		o.MaxSide = DefaultMaxSide
+	}
+	if o.MaxBytes <= 0 || o.MaxBytes > DefaultMaxBytes {
+		o.MaxBytes = DefaultMaxBytes
+	}
+
+	return o, nil
+}
+
+// PreprocessImages 预处理本地图片，返回处理后的文件路径
+func (p *ImageProcessor) PreprocessImages(paths []string, opts PreprocessOptions) ([]string, error) {
+	opts, err := opts.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		output, err := preprocessImage(path, p.downloader.savePath, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to preprocess %s: %w", path, err)
+		}
+		outputs = append(outputs, output)
+	}
+
+	return outputs, nil
+}
+
+// preprocessImage 解码图片，校正方向、调整宽高比和尺寸后重新编码，
+// 重新编码会去掉 EXIF（含 GPS）等元数据。相同输入和选项的结果会复用。
+func preprocessImage(path, saveDir string, opts PreprocessOptions) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read image")
+	}
+
+	optsKey, _ := json.Marshal(opts)
+	h := sha256.New()
+	h.Write(data)
+	h.Write(optsKey)
+	baseName := fmt.Sprintf("%s%x", preprocessedPrefix, h.Sum(nil)[:8])
+	for _, ext := range []string{"jpg", "png"} {
+		cached := filepath.Join(saveDir, baseName+"."+ext)
+		if _, err := os.Stat(cached); err == nil {
+			touch(cached)
+			return cached, nil
+		}
+	}
+
+	src, srcFormat, data, err := decodeImage(path, data)
+	if err != nil {
+		return "", err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = FormatJPEG
+		if srcFormat == "png" && !isOpaque(src) {
+			format = FormatPNG
+		}
+	}
+
+	img := toNRGBA(src, format == FormatJPEG)
+	if srcFormat == "jpeg" {
+		img = applyOrientation(img, readJPEGOrientation(data))
+	}
+
+	if opts.Aspect != "" {
+		ratio := aspectRatios[opts.Aspect]
+		if opts.Fit == FitPad {
+			// 先缩小再补边，避免细长图片补边后的画布过大
+			img = padToAspect(resizeToFit(img, opts.MaxSide), ratio[0], ratio[1])
+		} else {
+			img = cropToAspect(img, ratio[0], ratio[1])
+		}
+	}
+
+	img = resizeToFit(img, opts.MaxSide)
+
+	encoded, err := encodeWithinLimit(img, format, opts.MaxBytes)
+	if err != nil {
+		return "", err
+	}
+
+	ext := "jpg"
+	if format == FormatPNG {
+		ext = "png"
+	}
+	output := filepath.Join(saveDir, baseName+"."+ext)
+	if err := os.WriteFile(output, encoded, 0644); err != nil {
+		return "", errors.Wrap(err, "failed to save image")
+	}
+
+	b := img.Bounds()
+	logrus.Infof("图片预处理完成: %s -> %s (%dx%d, %d KB)", path, output, b.Dx(), b.Dy(), len(encoded)/1024)
+	return output, nil
+}
+
+// LoadImage 解码本地图片（支持 HEIC），并按 EXIF 校正方向
+func LoadImage(path string) (*image.NRGBA, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read image")
+	}
+
+	src, format, data, err := decodeImage(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	img := toNRGBA(src, false)
+	if format == "jpeg" {
+		img = applyOrientation(img, readJPEGOrientation(data))
+	}
+	return img, nil
+}
+
+// decodeImage 解码图片数据，HEIC 先转换为 JPEG，同时返回实际解码的数据
+func decodeImage(path string, data []byte) (image.Image, string, []byte, error) {
+	if kind, _ := filetype.Match(data); kind.Extension == "heif" {
+		var err error
+		if data, err = convertHEIC(path); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", nil, errors.Wrap(err, "unsupported image format")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, "", nil, errors.Errorf("图片尺寸无效: %dx%d", cfg.Width, cfg.Height)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > maxDecodePixels {
+		return nil, "", nil, errors.Errorf("图片尺寸 %dx%d 超过 %d 万像素的上限，请先缩小图片", cfg.Width, cfg.Height, maxDecodePixels/10000)
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", nil, errors.Wrap(err, "unsupported image format")
+	}
+	return src, format, data, nil
+}
+
+// convertHEIC 使用系统中的 heif-convert 或 ImageMagick 将 HEIC 转为 JPEG
+func convertHEIC(path string) ([]byte, error) {
+	tmp, err := os.CreateTemp("", "heic-*.jpg")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp file")
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	commands := [][]string{
+		{"heif-convert", path, tmp.Name()},
+		{"magick", path, tmp.Name()},
+		{"convert", path, tmp.Name()},
+	}
+	for _, args := range commands {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		if out, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+			logrus.Warnf("%s 转换 HEIC 失败: %v %s", args[0], err, out)
+			continue
+		}
+		return os.ReadFile(tmp.Name())
+	}
+
+	return nil, errors.New("HEIC 图片需要安装 libheif（heif-convert）或 ImageMagick 才能转换，请先转换为 JPEG 后再发布")
+}
+
+// isOpaque 判断图片是否不含透明像素
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return true
+}
+
+// toNRGBA 转为 NRGBA，flatten 为 true 时将透明区域合成到白色背景上
+func toNRGBA(src image.Image, flatten bool) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	if flatten {
+		draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+		draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Over)
+	} else {
+		draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	}
+	return dst
+}
+
+// readJPEGOrientation 读取 JPEG 中 EXIF 的 Orientation 标签，没有时返回 1
+func readJPEGOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		// SOS 之后是图像数据，不再有元数据段
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + size
+		if size < 2 || end > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return parseTIFFOrientation(segment[6:])
+		}
+		pos = end
+	}
+
+	return 1
+}
+
+// parseTIFFOrientation 从 EXIF 的 TIFF 结构中读取 IFD0 的 Orientation（0x0112）
+func parseTIFFOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) != 0x0112 {
+			continue
+		}
+		v := int(order.Uint16(tiff[entry+8:]))
+		if v < 1 || v > 8 {
+			return 1
+		}
+		return v
+	}
+
+	return 1
+}
+
+// applyOrientation 按 EXIF Orientation 旋转/翻转图片，使其以正确方向显示
+func applyOrientation(src *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // 水平翻转
+				dx, dy = w-1-x, y
+			case 3: // 旋转 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // 垂直翻转
+				dx, dy = x, h-1-y
+			case 5: // 沿主对角线翻转
+				dx, dy = y, x
+			case 6: // 顺时针旋转 90°
+				dx, dy = h-1-y, x
+			case 7: // 沿副对角线翻转
+				dx, dy = h-1-y, w-1-x
+			case 8: // 逆时针旋转 90°
+				dx, dy = y, w-1-x
+			}
+			dst.SetNRGBA(dx, dy, src.NRGBAAt(x, y))
+		}
+	}
+
+	return dst
+}
+
+// cropRect 计算按 aw:ah 居中裁剪 w×h 图片的区域，极端宽高比时至少保留 1 像素
+func cropRect(w, h, aw, ah int) image.Rectangle {
+	if w*ah > h*aw {
+		nw := max(h*aw/ah, 1)
+		x0 := (w - nw) / 2
+		return image.Rect(x0, 0, x0+nw, h)
+	}
+	nh := max(w*ah/aw, 1)
+	y0 := (h - nh) / 2
+	return image.Rect(0, y0, w, y0+nh)
+}
+
+// padSize 计算 w×h 图片补边到 aw:ah 后的画布尺寸，宽高至少为 1 像素
+func padSize(w, h, aw, ah int) (int, int) {
+	w, h = max(w, 1), max(h, 1)
+	if w*ah > h*aw {
+		return w, (w*ah + aw - 1) / aw
+	}
+	return (h*aw + ah - 1) / ah, h
+}
+
+// cropToAspect 居中裁剪到目标宽高比
+func cropToAspect(src *image.NRGBA, aw, ah int) *image.NRGBA {
+	r := cropRect(src.Bounds().Dx(), src.Bounds().Dy(), aw, ah)
+	dst := image.NewNRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, r.Min, draw.Src)
+	return dst
+}
+
+// padToAspect 以白色补边到目标宽高比，原图居中
+func padToAspect(src *image.NRGBA, aw, ah int) *image.NRGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	cw, ch := padSize(w, h, aw, ah)
+	dst := image.NewNRGBA(image.Rect(0, 0, cw, ch))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	offset := image.Pt((cw-w)/2, (ch-h)/2)
+	draw.Draw(dst, image.Rectangle{Min: offset, Max: offset.Add(image.Pt(w, h))}, src, image.Point{}, draw.Over)
+	return dst
+}
+
+// resizeToFit 最长边超过 maxSide 时等比缩小
+func resizeToFit(src *image.NRGBA, maxSide int) *image.NRGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= maxSide && h <= maxSide {
+		return src
+	}
+
+	nw, nh := maxSide, h*maxSide/w
+	if h > w {
+		nw, nh = w*maxSide/h, maxSide
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, max(nw, 1), max(nh, 1)))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+	return dst
+}
+
+// encodeWithinLimit 编码图片，超过大小上限时依次降低 JPEG 质量和缩小尺寸
+func encodeWithinLimit(img *image.NRGBA, format string, maxBytes int64) ([]byte, error) {
+	for {
+		qualities := []int{0}
+		if format == FormatJPEG {
+			qualities = []int{defaultJPEGQuality, 85, 75, minJPEGQuality}
+		}
+
+		for _, quality := range qualities {
+			var buf bytes.Buffer
+			var err error
+			if format == FormatPNG {
+				err = png.Encode(&buf, img)
+			} else {
+				err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+			}
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to encode image")
+			}
+			if int64(buf.Len()) <= maxBytes {
+				return buf.Bytes(), nil
+			}
+		}
+
+		side := max(img.Bounds().Dx(), img.Bounds().Dy())
+		if side <= minScaledSide {
+			return nil, errors.Errorf("图片压缩后仍超过 %d KB", maxBytes/1024)
+		}
+		img = resizeToFit(img, max(side*4/5, minScaledSide))
+	}
+}
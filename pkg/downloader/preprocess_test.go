@@ -0,0 +1,219 @@
+package downloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreprocessOptionsNormalize(t *testing.T) {
+	opts, err := PreprocessOptions{Aspect: " 3:4 ", Format: "JPG"}.Normalize()
+	if err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	if opts.Aspect != Aspect3x4 || opts.Fit != FitCrop || opts.Format != FormatJPEG {
+		t.Errorf("Normalize() = %+v", opts)
+	}
+	if opts.MaxSide != DefaultMaxSide || opts.MaxBytes != DefaultMaxBytes {
+		t.Errorf("Normalize() limits = %d/%d, expected defaults", opts.MaxSide, opts.MaxBytes)
+	}
+
+	for _, bad := range []PreprocessOptions{{Aspect: "16:9"}, {Fit: "stretch"}, {Format: "gif"}} {
+		if _, err := bad.Normalize(); err == nil {
+			t.Errorf("Normalize(%+v) expected error", bad)
+		}
+	}
+}
+
+func TestCropRectAndPadSize(t *testing.T) {
+	tests := []struct {
+		w, h, aw, ah int
+		crop         image.Rectangle
+		padW, padH   int
+	}{
+		{1000, 1000, 3, 4, image.Rect(125, 0, 875, 1000), 1000, 1334},
+		{1200, 900, 1, 1, image.Rect(150, 0, 1050, 900), 1200, 1200},
+		{900, 1600, 4, 3, image.Rect(0, 462, 900, 1137), 2134, 1600},
+		{300, 400, 3, 4, image.Rect(0, 0, 300, 400), 300, 400},
+		// 极端宽高比不会得到空的区域
+		{1, 5000, 4, 3, image.Rect(0, 2499, 1, 2500), 6667, 5000},
+		{5000, 1, 3, 4, image.Rect(2499, 0, 2500, 1), 5000, 6667},
+	}
+
+	for _, test := range tests {
+		if got := cropRect(test.w, test.h, test.aw, test.ah); got != test.crop {
+			t.Errorf("cropRect(%d, %d, %d:%d) = %v, expected %v", test.w, test.h, test.aw, test.ah, got, test.crop)
+		}
+		if w, h := padSize(test.w, test.h, test.aw, test.ah); w != test.padW || h != test.padH {
+			t.Errorf("padSize(%d, %d, %d:%d) = %dx%d, expected %dx%d", test.w, test.h, test.aw, test.ah, w, h, test.padW, test.padH)
+		}
+	}
+}
+
+// makeExifJPEG 构造带 Orientation 标签的 JPEG 数据
+func makeExifJPEG(t *testing.T, order binary.ByteOrder, orientation uint16) []byte {
+	t.Helper()
+
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, 0xD8, 0xFF, 0xE1})
+	binary.Write(&buf, binary.BigEndian, uint16(len(segment)+2))
+	buf.Write(segment)
+
+	var body bytes.Buffer
+	if err := jpeg.Encode(&body, image.NewRGBA(image.Rect(0, 0, 4, 2)), nil); err != nil {
+		t.Fatal(err)
+	}
+	buf.Write(body.Bytes()[2:])
+
+	return buf.Bytes()
+}
+
+func TestReadJPEGOrientation(t *testing.T) {
+	if got := readJPEGOrientation(makeExifJPEG(t, binary.LittleEndian, 6)); got != 6 {
+		t.Errorf("readJPEGOrientation(II) = %d, expected 6", got)
+	}
+	if got := readJPEGOrientation(makeExifJPEG(t, binary.BigEndian, 8)); got != 8 {
+		t.Errorf("readJPEGOrientation(MM) = %d, expected 8", got)
+	}
+	if got := readJPEGOrientation([]byte("not a jpeg")); got != 1 {
+		t.Errorf("readJPEGOrientation(invalid) = %d, expected 1", got)
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// 2x1 的图片：左红右蓝
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+	src.SetNRGBA(0, 0, red)
+	src.SetNRGBA(1, 0, blue)
+
+	// 顺时针旋转 90° 后为 1x2：上红下蓝
+	rotated := applyOrientation(src, 6)
+	if rotated.Bounds().Dx() != 1 || rotated.Bounds().Dy() != 2 {
+		t.Fatalf("applyOrientation(6) size = %v", rotated.Bounds())
+	}
+	if rotated.NRGBAAt(0, 0) != red || rotated.NRGBAAt(0, 1) != blue {
+		t.Errorf("applyOrientation(6) pixels = %v %v", rotated.NRGBAAt(0, 0), rotated.NRGBAAt(0, 1))
+	}
+
+	// 逆时针旋转 90° 后为 1x2：上蓝下红
+	rotated = applyOrientation(src, 8)
+	if rotated.NRGBAAt(0, 0) != blue || rotated.NRGBAAt(0, 1) != red {
+		t.Errorf("applyOrientation(8) pixels = %v %v", rotated.NRGBAAt(0, 0), rotated.NRGBAAt(0, 1))
+	}
+
+	if applyOrientation(src, 1) != src {
+		t.Error("applyOrientation(1) should return the source image")
+	}
+}
+
+func TestPreprocessImage(t *testing.T) {
+	dir := t.TempDir()
+
+	// 带 EXIF 的竖拍照片（Orientation=6），存储尺寸 4x2
+	src := filepath.Join(dir, "photo.jpg")
+	if err := os.WriteFile(src, makeExifJPEG(t, binary.LittleEndian, 6), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, _ := PreprocessOptions{}.Normalize()
+	output, err := preprocessImage(src, dir, opts)
+	if err != nil {
+		t.Fatalf("preprocessImage() error = %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("Exif")) {
+		t.Error("preprocessed image should not contain EXIF data")
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 2 || cfg.Height != 4 {
+		t.Errorf("preprocessed size = %dx%d, expected 2x4", cfg.Width, cfg.Height)
+	}
+
+	// 相同输入和选项复用结果
+	again, err := preprocessImage(src, dir, opts)
+	if err != nil || again != output {
+		t.Errorf("preprocessImage() again = %s, %v, expected %s", again, err, output)
+	}
+}
+
+func TestPreprocessImageAspectAndResize(t *testing.T) {
+	dir := t.TempDir()
+
+	// 带透明通道的 PNG 保持 PNG 格式
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	src := filepath.Join(dir, "wide.png")
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, _ := PreprocessOptions{Aspect: Aspect3x4, Fit: FitPad, MaxSide: 100}.Normalize()
+	output, err := preprocessImage(src, dir, opts)
+	if err != nil {
+		t.Fatalf("preprocessImage() error = %v", err)
+	}
+	if filepath.Ext(output) != ".png" {
+		t.Errorf("preprocessImage() output = %s, expected png", output)
+	}
+
+	f, err := os.Open(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cfg, err := png.DecodeConfig(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 74 || cfg.Height != 100 {
+		t.Errorf("preprocessed size = %dx%d, expected 74x100", cfg.Width, cfg.Height)
+	}
+}
+
+func TestDecodeImageRejectsHugeDimensions(t *testing.T) {
+	// 只有文件头的 GIF，声明 60000x60000 的画布
+	data := []byte("GIF89a")
+	data = binary.LittleEndian.AppendUint16(data, 60000)
+	data = binary.LittleEndian.AppendUint16(data, 60000)
+	data = append(data, 0x80, 0, 0, 0, 0, 0, 0xFF, 0xFF, 0xFF, ';')
+
+	_, _, _, err := decodeImage("huge.gif", data)
+	if err == nil || !strings.Contains(err.Error(), "超过") {
+		t.Fatalf("expected pixel limit error, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package downloader
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
 )
@@ -13,36 +14,57 @@ type ImageProcessor struct {
 
 // NewImageProcessor 创建图片处理器
 func NewImageProcessor() *ImageProcessor {
+	savePath := configs.GetImagesPath()
+	downloader := NewImageDownloader(savePath)
+	maybeCleanCache(savePath)
+
 	return &ImageProcessor{
-		downloader: NewImageDownloader(configs.GetImagesPath()),
+		downloader: downloader,
 	}
 }
 
-// ProcessImages 处理图片列表，返回本地文件路径
-// 支持两种输入格式：
+// ProcessImages 处理图片列表，返回本地文件路径，顺序与输入一致
+// 支持以下输入格式：
 // 1. URL格式 (http/https开头) - 自动下载到本地
-// 2. 本地文件路径 - 直接使用
+// 2. data: URI 或 base64 编码的图片 - 解码后保存到本地
+// 3. 本地文件路径 - 直接使用
 func (p *ImageProcessor) ProcessImages(images []string) ([]string, error) {
-	var localPaths []string
 	var urlsToDownload []string
 
 	// 分离URL和本地路径
 	for _, image := range images {
 		if IsImageURL(image) {
 			urlsToDownload = append(urlsToDownload, image)
-		} else {
-			// 本地路径直接添加
-			localPaths = append(localPaths, image)
 		}
 	}
 
-	// 批量下载URL图片
+	// 并发下载URL图片
+	downloaded := make(map[string]string, len(urlsToDownload))
 	if len(urlsToDownload) > 0 {
 		downloadedPaths, err := p.downloader.DownloadImages(urlsToDownload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to download images: %w", err)
 		}
-		localPaths = append(localPaths, downloadedPaths...)
+		for i, u := range urlsToDownload {
+			downloaded[u] = downloadedPaths[i]
+		}
+	}
+
+	var localPaths []string
+	for i, image := range images {
+		switch {
+		case IsImageURL(image):
+			localPaths = append(localPaths, downloaded[image])
+		case IsDataURI(image) || IsBase64Image(image):
+			path, err := p.downloader.SaveInlineImage(image)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode image #%d: %w", i+1, err)
+			}
+			localPaths = append(localPaths, path)
+		default:
+			// 本地路径直接添加
+			localPaths = append(localPaths, image)
+		}
 	}
 
 	if len(localPaths) == 0 {
@@ -51,3 +73,17 @@ func (p *ImageProcessor) ProcessImages(images []string) ([]string, error) {
 
 	return localPaths, nil
 }
+
+// SaveImage 保存上传的图片到图片目录，返回本地路径
+func (p *ImageProcessor) SaveImage(r io.Reader) (string, error) {
+	return p.downloader.SaveImage(r)
+}
+
+// ProcessImage 处理单张图片，URL 自动下载，data: URI 和 base64 解码保存，本地路径直接返回
+func (p *ImageProcessor) ProcessImage(image string) (string, error) {
+	paths, err := p.ProcessImages([]string{image})
+	if err != nil {
+		return "", err
+	}
+	return paths[0], nil
+}
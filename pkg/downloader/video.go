@@ -0,0 +1,291 @@
+package downloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h2non/filetype"
+	"github.com/h2non/filetype/matchers"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// DefaultMaxVideoBytes 视频下载大小上限，与平台的上传上限一致
+const DefaultMaxVideoBytes int64 = 20 << 30
+
+// videoDownloadAttempts 下载中断时自动续传的次数
+const videoDownloadAttempts = 3
+
+// VideoDownloader 视频下载器，边下载边写入磁盘，中断后通过 HTTP Range 续传
+type VideoDownloader struct {
+	savePath   string
+	maxBytes   int64
+	retryDelay time.Duration // 续传前等待的时长，按重试次数递增
+	httpClient *http.Client
+}
+
+// NewVideoDownloader 创建视频下载器，maxBytes 为 0 时使用 DefaultMaxVideoBytes
+func NewVideoDownloader(savePath string, maxBytes int64) *VideoDownloader {
+	if err := os.MkdirAll(savePath, 0755); err != nil {
+		panic(fmt.Sprintf("failed to create save path: %v", err))
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxVideoBytes
+	}
+
+	return &VideoDownloader{
+		savePath:   savePath,
+		maxBytes:   maxBytes,
+		retryDelay: time.Second,
+		// 视频下载耗时不确定，不设置整体超时，只限制等待响应头的时间
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				ResponseHeaderTimeout: 30 * time.Second,
+			},
+		},
+	}
+}
+
+// ProcessVideo 处理视频，URL 下载到本地后返回本地路径，本地路径直接返回
+func ProcessVideo(ctx context.Context, video string) (string, error) {
+	if !IsVideoURL(video) {
+		return video, nil
+	}
+	return NewVideoDownloader(configs.GetVideosPath(), 0).DownloadVideo(ctx, video)
+}
+
+// IsVideoURL 判断字符串是否为视频URL
+func IsVideoURL(path string) bool {
+	return IsImageURL(path)
+}
+
+// DownloadVideo 下载视频，返回本地文件路径。
+// 未完成的下载保存在 .part 文件中，再次下载同一 URL 时从断点继续。
+func (d *VideoDownloader) DownloadVideo(ctx context.Context, videoURL string) (string, error) {
+	if !isValidHTTPURL(videoURL) {
+		return "", errors.New("invalid video URL format")
+	}
+
+	base := filepath.Join(d.savePath, d.generateFileName(videoURL))
+	for _, ext := range []string{".mp4", ".mov"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext, nil
+		}
+	}
+
+	part := base + ".part"
+	var err error
+	for attempt := 1; attempt <= videoDownloadAttempts; attempt++ {
+		var retry bool
+		retry, err = d.download(ctx, videoURL, part)
+		if err == nil || !retry || ctx.Err() != nil || attempt == videoDownloadAttempts {
+			break
+		}
+		logrus.Warnf("视频下载中断，准备续传（%d/%d）: %v", attempt, videoDownloadAttempts, err)
+
+		select {
+		case <-ctx.Done():
+			return "", errors.Wrap(ctx.Err(), "failed to download video")
+		case <-time.After(d.retryDelay * time.Duration(attempt)):
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	ext, err := verifyVideo(part)
+	if err != nil {
+		_ = os.Remove(part)
+		_ = os.Remove(part + ".validator")
+		return "", err
+	}
+
+	path := base + "." + ext
+	if err := os.Rename(part, path); err != nil {
+		return "", errors.Wrap(err, "failed to save video")
+	}
+	_ = os.Remove(part + ".validator")
+
+	return path, nil
+}
+
+// download 下载到 part 文件，已有部分内容时使用 Range 续传。返回的 retry 表示错误是否可以通过续传恢复。
+func (d *VideoDownloader) download(ctx context.Context, videoURL, part string) (retry bool, err error) {
+	var offset int64
+	if st, err := os.Stat(part); err == nil {
+		offset = st.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, videoURL, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create request")
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		// 远端文件已变化时服务器返回完整内容，避免拼接出损坏的文件
+		if validator, err := os.ReadFile(part + ".validator"); err == nil && len(validator) > 0 {
+			req.Header.Set("If-Range", string(validator))
+		}
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return true, errors.Wrap(err, "failed to download video")
+	}
+	defer resp.Body.Close()
+
+	flag := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, total, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil || start != offset {
+			// 无法从断点续传，丢弃已下载的部分，下次从头下载
+			_ = os.Remove(part)
+			_ = os.Remove(part + ".validator")
+			return true, errors.Errorf("unexpected Content-Range: %s", resp.Header.Get("Content-Range"))
+		}
+		if total > d.maxBytes {
+			return false, errors.Errorf("视频大小 %dMB，超过 %dMB 限制", total>>20, d.maxBytes>>20)
+		}
+		flag |= os.O_APPEND
+	case http.StatusOK:
+		// 服务器不支持 Range 或文件已变化，从头下载
+		offset = 0
+		flag |= os.O_TRUNC
+		if resp.ContentLength > d.maxBytes {
+			return false, errors.Errorf("视频大小 %dMB，超过 %dMB 限制", resp.ContentLength>>20, d.maxBytes>>20)
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// 断点已超出远端文件大小，丢弃后下次从头下载
+		_ = os.Remove(part)
+		return true, errors.New("resume offset out of range")
+	default:
+		return resp.StatusCode >= 500, fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	}
+
+	if err := checkVideoContentType(resp.Header.Get("Content-Type")); err != nil {
+		return false, err
+	}
+
+	if validator := responseValidator(resp); validator != "" {
+		_ = os.WriteFile(part+".validator", []byte(validator), 0644)
+	} else {
+		_ = os.Remove(part + ".validator")
+	}
+
+	f, err := os.OpenFile(part, flag, 0644)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create video file")
+	}
+	defer f.Close()
+
+	// 多读 1 字节用于判断是否超过大小上限
+	n, err := io.Copy(f, io.LimitReader(resp.Body, d.maxBytes-offset+1))
+	if offset+n > d.maxBytes {
+		_ = f.Close()
+		_ = os.Remove(part)
+		_ = os.Remove(part + ".validator")
+		return false, errors.Errorf("视频大小超过 %dMB 限制", d.maxBytes>>20)
+	}
+	if err != nil {
+		return true, errors.Wrap(err, "failed to read video data")
+	}
+	if resp.ContentLength >= 0 && n < resp.ContentLength {
+		return true, errors.Wrap(io.ErrUnexpectedEOF, "failed to read video data")
+	}
+
+	return false, nil
+}
+
+// generateFileName 使用 URL 的哈希作为文件名，同一 URL 可以续传和复用已下载的文件
+func (d *VideoDownloader) generateFileName(videoURL string) string {
+	hash := sha256.Sum256([]byte(videoURL))
+	return fmt.Sprintf("video_%x", hash[:8])
+}
+
+// checkVideoContentType 拒绝明确不是视频的响应，如返回了 HTML 错误页
+func checkVideoContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	if strings.HasPrefix(mediaType, "video/") ||
+		mediaType == "application/octet-stream" ||
+		mediaType == "binary/octet-stream" ||
+		mediaType == "application/mp4" {
+		return nil
+	}
+	return errors.Errorf("链接返回的不是视频文件: %s", mediaType)
+}
+
+// verifyVideo 通过文件头判断视频格式，仅支持 MP4/MOV
+func verifyVideo(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open video")
+	}
+	defer f.Close()
+
+	head := make([]byte, 262)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", errors.Wrap(err, "failed to read video data")
+	}
+
+	kind, _ := filetype.Match(head[:n])
+	switch kind {
+	case matchers.TypeMp4, matchers.TypeM4v:
+		return "mp4", nil
+	case matchers.TypeMov:
+		return "mov", nil
+	}
+	if kind == filetype.Unknown {
+		return "", errors.New("downloaded file is not a valid video")
+	}
+	return "", errors.Errorf("不支持的视频格式 %s，仅支持 MP4/MOV", kind.Extension)
+}
+
+// responseValidator 用于 If-Range 的校验值，优先使用强 ETag
+func responseValidator(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
+// parseContentRange 解析 "bytes start-end/total"，total 未知时返回 -1
+func parseContentRange(value string) (start, total int64, err error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "bytes ")
+	rangePart, totalPart, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0, errors.New("invalid Content-Range")
+	}
+	startPart, _, ok := strings.Cut(rangePart, "-")
+	if !ok {
+		return 0, 0, errors.New("invalid Content-Range")
+	}
+	if start, err = strconv.ParseInt(startPart, 10, 64); err != nil {
+		return 0, 0, errors.Wrap(err, "invalid Content-Range")
+	}
+	if totalPart == "*" {
+		return start, -1, nil
+	}
+	if total, err = strconv.ParseInt(totalPart, 10, 64); err != nil {
+		return 0, 0, errors.Wrap(err, "invalid Content-Range")
+	}
+	return start, total, nil
+}
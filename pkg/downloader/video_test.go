@@ -0,0 +1,204 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testVideo 返回以 MP4 文件头开始的测试数据
+func testVideo(size int) []byte {
+	data := make([]byte, size)
+	copy(data, []byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00isomiso2"))
+	for i := 24; i < size; i++ {
+		data[i] = byte(i)
+	}
+	return data
+}
+
+func TestDownloadVideoResume(t *testing.T) {
+	video := testVideo(64 * 1024)
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("Content-Type", "video/mp4")
+		http.ServeContent(w, r, "video.mp4", time.Unix(1700000000, 0), bytes.NewReader(video))
+	}))
+	defer srv.Close()
+
+	d := NewVideoDownloader(t.TempDir(), 0)
+	url := srv.URL + "/video.mp4"
+
+	// 模拟上次下载中断，已保存前一半
+	part := filepath.Join(d.savePath, d.generateFileName(url)) + ".part"
+	if err := os.WriteFile(part, video[:32*1024], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := d.DownloadVideo(context.Background(), url)
+	if err != nil {
+		t.Fatalf("DownloadVideo failed: %v", err)
+	}
+	if !strings.HasSuffix(path, ".mp4") {
+		t.Errorf("path = %q, expected .mp4 suffix", path)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, video) {
+		t.Errorf("downloaded content mismatch: got %d bytes, expected %d", len(got), len(video))
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=32768-" {
+		t.Errorf("Range headers = %v, expected [bytes=32768-]", ranges)
+	}
+	if _, err := os.Stat(part); !os.IsNotExist(err) {
+		t.Errorf("part file should be removed after download")
+	}
+
+	// 已下载的文件直接复用
+	if _, err := d.DownloadVideo(context.Background(), url); err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != 1 {
+		t.Errorf("expected no new request for downloaded video, got %d requests", len(ranges))
+	}
+}
+
+func TestDownloadVideoRetry(t *testing.T) {
+	video := testVideo(64 * 1024)
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 第一次请求只返回一半数据后断开连接
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Content-Length", "65536")
+			w.Write(video[:20000])
+			return
+		}
+		http.ServeContent(w, r, "video.mp4", time.Time{}, bytes.NewReader(video))
+	}))
+	defer srv.Close()
+
+	d := NewVideoDownloader(t.TempDir(), 0)
+	d.retryDelay = time.Millisecond
+	path, err := d.DownloadVideo(context.Background(), srv.URL+"/video.mp4")
+	if err != nil {
+		t.Fatalf("DownloadVideo failed: %v", err)
+	}
+
+	got, _ := os.ReadFile(path)
+	if !bytes.Equal(got, video) {
+		t.Errorf("downloaded content mismatch: got %d bytes, expected %d", len(got), len(video))
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, expected 2", requests)
+	}
+}
+
+func TestDownloadVideoRangeMismatch(t *testing.T) {
+	video := testVideo(64 * 1024)
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("Content-Type", "video/mp4")
+		if r.Header.Get("Range") != "" {
+			// 忽略请求的断点，从其他位置返回
+			w.Header().Set("Content-Range", "bytes 0-1023/65536")
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(video[:1024])
+			return
+		}
+		w.Write(video)
+	}))
+	defer srv.Close()
+
+	d := NewVideoDownloader(t.TempDir(), 0)
+	d.retryDelay = time.Millisecond
+	url := srv.URL + "/video.mp4"
+
+	part := filepath.Join(d.savePath, d.generateFileName(url)) + ".part"
+	if err := os.WriteFile(part, video[:32*1024], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 断点不匹配时丢弃已下载的部分，从头下载
+	path, err := d.DownloadVideo(context.Background(), url)
+	if err != nil {
+		t.Fatalf("DownloadVideo failed: %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if !bytes.Equal(got, video) {
+		t.Errorf("downloaded content mismatch: got %d bytes, expected %d", len(got), len(video))
+	}
+	if len(ranges) != 2 || ranges[0] != "bytes=32768-" || ranges[1] != "" {
+		t.Errorf("Range headers = %q, expected [bytes=32768- \"\"]", ranges)
+	}
+}
+
+func TestDownloadVideoRejects(t *testing.T) {
+	video := testVideo(64 * 1024)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html></html>"))
+		case "/fake.mp4":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(bytes.Repeat([]byte("not a video"), 100))
+		default:
+			w.Header().Set("Content-Type", "video/mp4")
+			w.Write(video)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		maxBytes int64
+	}{
+		{"content type", "/page.html", 0},
+		{"magic number", "/fake.mp4", 0},
+		{"size cap", "/video.mp4", 1024},
+	}
+
+	for _, test := range tests {
+		d := NewVideoDownloader(t.TempDir(), test.maxBytes)
+		if _, err := d.DownloadVideo(context.Background(), srv.URL+test.path); err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+
+		if entries, _ := os.ReadDir(d.savePath); len(entries) > 0 {
+			t.Errorf("%s: unexpected file left behind: %s", test.name, entries[0].Name())
+		}
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		value string
+		start int64
+		total int64
+		ok    bool
+	}{
+		{"bytes 100-199/1000", 100, 1000, true},
+		{"bytes 0-99/*", 0, -1, true},
+		{"bytes */1000", 0, 0, false},
+		{"invalid", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		start, total, err := parseContentRange(test.value)
+		if (err == nil) != test.ok || start != test.start || total != test.total {
+			t.Errorf("parseContentRange(%q) = %d, %d, %v", test.value, start, total, err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package imagekit
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	// MaxCollageImages 拼图最多使用的图片数量
+	MaxCollageImages = 9
+
+	defaultCollageWidth  = 1080
+	defaultCollageAspect = "3:4"
+	defaultCollageGap    = 8 // 按 1080 宽度
+	maxCollageWidth      = 4096
+)
+
+// CollageOptions 拼图选项，图片按行排列，最后一行图片较少时拉宽填满
+type CollageOptions struct {
+	Columns    int    `json:"columns,omitempty"`    // 每行图片数，默认按图片数量自动选择
+	Aspect     string `json:"aspect,omitempty"`     // 画布宽高比，如 3:4|1:1|4:3，默认 3:4
+	Width      int    `json:"width,omitempty"`      // 画布宽度，默认 1080
+	Gap        *int   `json:"gap,omitempty"`        // 图片间距，默认按宽度缩放的 8 像素，0 为无间距
+	Background string `json:"background,omitempty"` // 间距颜色 #RRGGBB，默认白色
+	OutputDir  string `json:"-"`                    // 输出目录，默认与下载图片相同的临时目录
+}
+
+// Normalize 校验选项并填充默认值
+func (o CollageOptions) Normalize() (CollageOptions, error) {
+	if o.Columns < 0 || o.Columns > MaxCollageImages {
+		return o, errors.Errorf("拼图列数需在 1-%d 之间: %d", MaxCollageImages, o.Columns)
+	}
+
+	o.Aspect = strings.TrimSpace(o.Aspect)
+	if o.Aspect == "" {
+		o.Aspect = defaultCollageAspect
+	}
+	if _, _, err := parseAspect(o.Aspect); err != nil {
+		return o, err
+	}
+
+	if o.Width <= 0 {
+		o.Width = defaultCollageWidth
+	}
+	if o.Width > maxCollageWidth {
+		o.Width = maxCollageWidth
+	}
+
+	if o.Gap == nil {
+		gap := defaultCollageGap * o.Width / defaultCollageWidth
+		o.Gap = &gap
+	}
+	if *o.Gap < 0 || *o.Gap > o.Width/10 {
+		return o, errors.Errorf("拼图间距需在 0-%d 之间: %d", o.Width/10, *o.Gap)
+	}
+
+	if _, err := parseColor(o.Background, color.NRGBA{}); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// Collage 将 2-9 张图片拼成一张网格图，每张图片居中裁剪填满格子，返回拼图路径
+func Collage(paths []string, opts CollageOptions) (string, error) {
+	if len(paths) < 2 || len(paths) > MaxCollageImages {
+		return "", errors.Errorf("拼图需要 2-%d 张图片，当前 %d 张", MaxCollageImages, len(paths))
+	}
+
+	opts, err := opts.Normalize()
+	if err != nil {
+		return "", err
+	}
+
+	dir := outputDir(opts.OutputDir)
+	name, err := cacheName("collage", paths, opts)
+	if err != nil {
+		return "", err
+	}
+	if cached, ok := lookupCache(dir, name); ok {
+		return cached, nil
+	}
+
+	aw, ah, _ := parseAspect(opts.Aspect)
+	width, height := opts.Width, opts.Width*ah/aw
+
+	background, _ := parseColor(opts.Background, color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF})
+	canvas := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	cells := gridCells(len(paths), opts.Columns, width, height, *opts.Gap)
+	for i, path := range paths {
+		img, err := downloader.LoadImage(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "加载第 %d 张图片失败", i+1)
+		}
+		fillCell(canvas, cells[i], img)
+	}
+
+	return save(canvas, dir, name)
+}
+
+// gridCells 计算 n 张图片在 width×height 画布上的格子。
+// 每行 columns 张，最后一行不足时平分整行宽度；columns 为 0 时取 ceil(sqrt(n))。
+func gridCells(n, columns, width, height, gap int) []image.Rectangle {
+	if columns <= 0 {
+		columns = int(math.Ceil(math.Sqrt(float64(n))))
+	}
+	columns = min(columns, n)
+	rows := (n + columns - 1) / columns
+
+	cells := make([]image.Rectangle, 0, n)
+	for row := 0; row < rows; row++ {
+		count := min(columns, n-row*columns)
+		y0 := gap + row*(height-gap)/rows
+		y1 := (row + 1) * (height - gap) / rows
+
+		for col := 0; col < count; col++ {
+			x0 := gap + col*(width-gap)/count
+			x1 := (col + 1) * (width - gap) / count
+			cells = append(cells, image.Rect(x0, y0, x1, y1))
+		}
+	}
+	return cells
+}
+
+// fillCell 将图片居中裁剪到格子的宽高比后缩放填满格子
+func fillCell(dst *image.NRGBA, cell image.Rectangle, src *image.NRGBA) {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	cw, ch := cell.Dx(), cell.Dy()
+	if cw <= 0 || ch <= 0 {
+		return
+	}
+
+	crop := src.Bounds()
+	if sw*ch > sh*cw {
+		nw := sh * cw / ch
+		crop.Min.X += (sw - nw) / 2
+		crop.Max.X = crop.Min.X + nw
+	} else {
+		nh := sw * ch / cw
+		crop.Min.Y += (sh - nh) / 2
+		crop.Max.Y = crop.Min.Y + nh
+	}
+
+	xdraw.CatmullRom.Scale(dst, cell, src, crop, xdraw.Over, nil)
+}
+
+// parseAspect 解析 w:h 格式的宽高比
+func parseAspect(s string) (int, int, error) {
+	w, h, ok := strings.Cut(s, ":")
+	if ok {
+		aw, err1 := strconv.Atoi(strings.TrimSpace(w))
+		ah, err2 := strconv.Atoi(strings.TrimSpace(h))
+		if err1 == nil && err2 == nil && aw > 0 && ah > 0 && aw <= 4*ah && ah <= 4*aw {
+			return aw, ah, nil
+		}
+	}
+	return 0, 0, errors.Errorf("无效的宽高比: %s，请使用如 3:4 的格式", s)
+}
@@ -0,0 +1,64 @@
+package imagekit
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+)
+
+func TestGridCells(t *testing.T) {
+	cells := gridCells(5, 0, 1000, 1000, 10)
+	require.Equal(t, []image.Rectangle{
+		image.Rect(10, 10, 330, 495),
+		image.Rect(340, 10, 660, 495),
+		image.Rect(670, 10, 990, 495),
+		// 最后一行两张平分宽度
+		image.Rect(10, 505, 495, 990),
+		image.Rect(505, 505, 990, 990),
+	}, cells)
+
+	cells = gridCells(2, 1, 300, 400, 0)
+	require.Equal(t, []image.Rectangle{image.Rect(0, 0, 300, 200), image.Rect(0, 200, 300, 400)}, cells)
+}
+
+func TestCollage(t *testing.T) {
+	dir := t.TempDir()
+
+	colors := []color.NRGBA{
+		{R: 0xFF, A: 0xFF},
+		{G: 0xFF, A: 0xFF},
+		{B: 0xFF, A: 0xFF},
+	}
+	var paths []string
+	for i, c := range colors {
+		path := filepath.Join(dir, fmt.Sprintf("%d.png", i))
+		writePNG(t, path, 200+i*100, 300, c)
+		paths = append(paths, path)
+	}
+
+	gap := 0
+	out, err := Collage(paths, CollageOptions{Columns: 3, Aspect: "1:1", Width: 300, Gap: &gap, OutputDir: dir})
+	require.NoError(t, err)
+
+	img, err := downloader.LoadImage(out)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 300, 300), img.Bounds())
+
+	for i, c := range colors {
+		p := img.NRGBAAt(50+i*100, 150)
+		require.InDelta(t, float64(c.R), float64(p.R), 8, "cell %d", i)
+		require.InDelta(t, float64(c.G), float64(p.G), 8, "cell %d", i)
+		require.InDelta(t, float64(c.B), float64(p.B), 8, "cell %d", i)
+	}
+
+	_, err = Collage(paths[:1], CollageOptions{OutputDir: dir})
+	require.ErrorContains(t, err, "拼图需要")
+
+	_, err = Collage(paths, CollageOptions{Aspect: "16-9", OutputDir: dir})
+	require.ErrorContains(t, err, "无效的宽高比")
+}
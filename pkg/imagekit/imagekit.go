@@ -0,0 +1,114 @@
+package imagekit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// outputJPEGQuality 合成结果的 JPEG 质量，发布前还会按平台限制再压缩一次
+const outputJPEGQuality = 95
+
+// parseColor 解析 #RRGGBB 或 #RGB 颜色，为空时返回 def
+func parseColor(s string, def color.NRGBA) (color.NRGBA, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if s == "" {
+		return def, nil
+	}
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) != 6 {
+		return def, errors.Errorf("无效的颜色: #%s，请使用 #RRGGBB 格式", s)
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xFF}, nil
+}
+
+// cacheName 根据输入文件内容和选项生成输出文件名（不含扩展名），相同输入复用已有结果
+func cacheName(prefix string, files []string, opts any) (string, error) {
+	h := sha256.New()
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read image")
+		}
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read image")
+		}
+	}
+
+	key, _ := json.Marshal(opts)
+	h.Write(key)
+	return fmt.Sprintf("%s_%x", prefix, h.Sum(nil)[:8]), nil
+}
+
+// lookupCache 查找已生成的结果，命中时更新修改时间，缓存清理按修改时间淘汰最久未使用的文件
+func lookupCache(dir, name string) (string, bool) {
+	for _, ext := range []string{"jpg", "png"} {
+		path := filepath.Join(dir, name+"."+ext)
+		if _, err := os.Stat(path); err == nil {
+			now := time.Now()
+			_ = os.Chtimes(path, now, now)
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// save 保存合成结果，不透明的图片保存为 JPEG，否则保存为 PNG
+func save(img *image.NRGBA, dir, name string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrap(err, "failed to create output dir")
+	}
+
+	var (
+		buf bytes.Buffer
+		ext = "jpg"
+		err error
+	)
+	if img.Opaque() {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: outputJPEGQuality})
+	} else {
+		ext = "png"
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode image")
+	}
+
+	path := filepath.Join(dir, name+"."+ext)
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return "", errors.Wrap(err, "failed to save image")
+	}
+
+	b := img.Bounds()
+	logrus.Infof("图片合成完成: %s (%dx%d, %d KB)", path, b.Dx(), b.Dy(), buf.Len()/1024)
+	return path, nil
+}
+
+// outputDir 输出目录，为空时使用下载图片的临时目录
+func outputDir(dir string) string {
+	if dir == "" {
+		return configs.GetImagesPath()
+	}
+	return dir
+}
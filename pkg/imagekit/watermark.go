@@ -0,0 +1,227 @@
+package imagekit
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/textcard"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// 水印位置
+const (
+	PositionTopLeft     = "top-left"
+	PositionTopRight    = "top-right"
+	PositionBottomLeft  = "bottom-left"
+	PositionBottomRight = "bottom-right"
+	PositionCenter      = "center"
+)
+
+const (
+	defaultOpacity = 0.6
+	defaultSize    = 0.05 // 水印高度占图片短边的比例
+	maxSize        = 0.3
+	marginRatio    = 0.03 // 水印与图片边缘的距离占短边的比例
+	minMarkHeight  = 12
+)
+
+var positions = []string{PositionTopLeft, PositionTopRight, PositionBottomLeft, PositionBottomRight, PositionCenter}
+
+// WatermarkOptions 水印选项，文字和 Logo 至少提供一个，同时提供时 Logo 在文字左侧
+type WatermarkOptions struct {
+	Text      string  `json:"text,omitempty"`      // 水印文字
+	Logo      string  `json:"logo,omitempty"`      // Logo 图片本地路径，建议使用透明背景的 PNG
+	Position  string  `json:"position,omitempty"`  // 位置：top-left|top-right|bottom-left|bottom-right|center，默认 bottom-right
+	Opacity   float64 `json:"opacity,omitempty"`   // 不透明度 0-1，默认 0.6
+	Size      float64 `json:"size,omitempty"`      // 水印高度占图片短边的比例，默认 0.05
+	Color     string  `json:"color,omitempty"`     // 文字颜色 #RRGGBB，默认白色
+	FontPath  string  `json:"font_path,omitempty"` // 字体文件路径，为空时与文字卡片相同的方式查找
+	OutputDir string  `json:"-"`                   // 输出目录，默认与下载图片相同的临时目录
+}
+
+// Normalize 校验选项并填充默认值
+func (o WatermarkOptions) Normalize() (WatermarkOptions, error) {
+	o.Text = strings.TrimSpace(o.Text)
+	o.Logo = strings.TrimSpace(o.Logo)
+	if o.Text == "" && o.Logo == "" {
+		return o, errors.New("水印文字和 Logo 不能都为空")
+	}
+
+	o.Position = strings.ToLower(strings.TrimSpace(o.Position))
+	if o.Position == "" {
+		o.Position = PositionBottomRight
+	}
+	valid := false
+	for _, p := range positions {
+		valid = valid || o.Position == p
+	}
+	if !valid {
+		return o, errors.Errorf("不支持的水印位置: %s，可选值: %s", o.Position, strings.Join(positions, "|"))
+	}
+
+	if o.Opacity < 0 || o.Opacity > 1 {
+		return o, errors.Errorf("水印不透明度需在 0-1 之间: %v", o.Opacity)
+	}
+	if o.Opacity == 0 {
+		o.Opacity = defaultOpacity
+	}
+
+	if o.Size < 0 || o.Size > maxSize {
+		return o, errors.Errorf("水印大小需在 0-%v 之间: %v", maxSize, o.Size)
+	}
+	if o.Size == 0 {
+		o.Size = defaultSize
+	}
+
+	if _, err := parseColor(o.Color, color.NRGBA{}); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// Watermark 为图片添加水印，返回新图片的路径，原图不变
+func Watermark(path string, opts WatermarkOptions) (string, error) {
+	opts, err := opts.Normalize()
+	if err != nil {
+		return "", err
+	}
+
+	files := []string{path}
+	if opts.Logo != "" {
+		files = append(files, opts.Logo)
+	}
+	dir := outputDir(opts.OutputDir)
+	name, err := cacheName("wm", files, opts)
+	if err != nil {
+		return "", err
+	}
+	if cached, ok := lookupCache(dir, name); ok {
+		return cached, nil
+	}
+
+	img, err := downloader.LoadImage(path)
+	if err != nil {
+		return "", err
+	}
+
+	b := img.Bounds()
+	short := min(b.Dx(), b.Dy())
+	height := max(int(float64(short)*opts.Size), minMarkHeight)
+
+	mark, err := renderMark(opts, height)
+	if err != nil {
+		return "", err
+	}
+
+	// 水印超出图片宽度时等比缩小
+	margin := int(float64(short) * marginRatio)
+	if limit := b.Dx() - 2*margin; mark.Bounds().Dx() > limit && limit > 0 {
+		mark = scale(mark, limit, mark.Bounds().Dy()*limit/mark.Bounds().Dx())
+	}
+
+	at := markPosition(b.Size(), mark.Bounds().Size(), opts.Position, margin)
+	alpha := image.NewUniform(color.Alpha{A: uint8(math.Round(opts.Opacity * 0xFF))})
+	draw.DrawMask(img, mark.Bounds().Add(at), mark, image.Point{}, alpha, image.Point{}, draw.Over)
+
+	return save(img, dir, name)
+}
+
+// renderMark 绘制透明背景的水印：Logo 和文字排成一行，高度为 height
+func renderMark(opts WatermarkOptions, height int) (*image.NRGBA, error) {
+	var logo *image.NRGBA
+	if opts.Logo != "" {
+		src, err := downloader.LoadImage(opts.Logo)
+		if err != nil {
+			return nil, errors.Wrap(err, "加载水印 Logo 失败")
+		}
+		sb := src.Bounds()
+		logo = scale(src, max(sb.Dx()*height/sb.Dy(), 1), height)
+	}
+
+	var (
+		face      font.Face
+		textWidth int
+	)
+	if opts.Text != "" {
+		f, err := textcard.ResolveFont(opts.FontPath, textcard.ContainsCJK(opts.Text))
+		if err != nil {
+			return nil, err
+		}
+		face, err = opentype.NewFace(f, &opentype.FaceOptions{Size: float64(height) * 0.8, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create font face")
+		}
+		defer face.Close()
+		textWidth = font.MeasureString(face, opts.Text).Ceil()
+	}
+
+	gap := 0
+	width := textWidth
+	if logo != nil {
+		width += logo.Bounds().Dx()
+		if face != nil {
+			gap = height / 3
+			width += gap
+		}
+	}
+	// 文字阴影向右下偏移，预留空间
+	shadow := max(height/24, 1)
+
+	mark := image.NewNRGBA(image.Rect(0, 0, width+shadow, height+shadow))
+
+	x := 0
+	if logo != nil {
+		draw.Draw(mark, logo.Bounds(), logo, image.Point{}, draw.Over)
+		x = logo.Bounds().Dx() + gap
+	}
+
+	if face != nil {
+		textColor, _ := parseColor(opts.Color, color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF})
+		m := face.Metrics()
+		ascent, descent := m.Ascent.Ceil(), m.Descent.Ceil()
+		baseline := (height-ascent-descent)/2 + ascent
+
+		// 先绘制半透明阴影，保证浅色背景上也能看清
+		d := &font.Drawer{Dst: mark, Src: image.NewUniform(color.NRGBA{A: 0x60}), Face: face, Dot: fixed.P(x+shadow, baseline+shadow)}
+		d.DrawString(opts.Text)
+		d = &font.Drawer{Dst: mark, Src: image.NewUniform(textColor), Face: face, Dot: fixed.P(x, baseline)}
+		d.DrawString(opts.Text)
+	}
+
+	return mark, nil
+}
+
+// markPosition 计算水印左上角在图片中的坐标
+func markPosition(img, mark image.Point, position string, margin int) image.Point {
+	left, top := margin, margin
+	right, bottom := img.X-mark.X-margin, img.Y-mark.Y-margin
+
+	switch position {
+	case PositionTopLeft:
+		return image.Pt(left, top)
+	case PositionTopRight:
+		return image.Pt(right, top)
+	case PositionBottomLeft:
+		return image.Pt(left, bottom)
+	case PositionCenter:
+		return image.Pt((img.X-mark.X)/2, (img.Y-mark.Y)/2)
+	default:
+		return image.Pt(right, bottom)
+	}
+}
+
+// scale 缩放到指定尺寸
+func scale(src *image.NRGBA, w, h int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, max(w, 1), max(h, 1)))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+	return dst
+}
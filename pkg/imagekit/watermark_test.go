@@ -0,0 +1,92 @@
+package imagekit
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// writePNG 生成纯色测试图片
+func writePNG(t *testing.T, path string, w, h int, c color.Color) {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, png.Encode(f, img))
+}
+
+func TestWatermarkOptionsNormalize(t *testing.T) {
+	opts, err := WatermarkOptions{Text: " @brand ", Position: "Top-Left"}.Normalize()
+	require.NoError(t, err)
+	require.Equal(t, "@brand", opts.Text)
+	require.Equal(t, PositionTopLeft, opts.Position)
+	require.Equal(t, defaultOpacity, opts.Opacity)
+	require.Equal(t, defaultSize, opts.Size)
+
+	for _, bad := range []WatermarkOptions{
+		{},
+		{Text: "x", Position: "middle"},
+		{Text: "x", Opacity: 1.5},
+		{Text: "x", Size: 0.9},
+		{Text: "x", Color: "red"},
+	} {
+		_, err := bad.Normalize()
+		require.Error(t, err, "%+v", bad)
+	}
+}
+
+func TestMarkPosition(t *testing.T) {
+	img, mark := image.Pt(1000, 800), image.Pt(200, 50)
+
+	require.Equal(t, image.Pt(10, 10), markPosition(img, mark, PositionTopLeft, 10))
+	require.Equal(t, image.Pt(790, 10), markPosition(img, mark, PositionTopRight, 10))
+	require.Equal(t, image.Pt(10, 740), markPosition(img, mark, PositionBottomLeft, 10))
+	require.Equal(t, image.Pt(790, 740), markPosition(img, mark, PositionBottomRight, 10))
+	require.Equal(t, image.Pt(400, 375), markPosition(img, mark, PositionCenter, 10))
+}
+
+func TestWatermark(t *testing.T) {
+	dir := t.TempDir()
+	fontPath := filepath.Join(dir, "goregular.ttf")
+	require.NoError(t, os.WriteFile(fontPath, goregular.TTF, 0644))
+
+	src := filepath.Join(dir, "photo.png")
+	writePNG(t, src, 400, 300, color.NRGBA{B: 0xFF, A: 0xFF})
+	logo := filepath.Join(dir, "logo.png")
+	writePNG(t, logo, 64, 64, color.NRGBA{R: 0xFF, A: 0xFF})
+
+	opts := WatermarkOptions{Text: "Brand", Logo: logo, Position: PositionBottomLeft, Opacity: 1, Size: 0.2, FontPath: fontPath, OutputDir: dir}
+	out, err := Watermark(src, opts)
+	require.NoError(t, err)
+	require.Equal(t, ".jpg", filepath.Ext(out))
+
+	img, err := downloader.LoadImage(out)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 400, 300), img.Bounds())
+
+	// Logo 在左下角，右上角保持原图颜色
+	margin := int(300 * marginRatio)
+	logoPixel := img.NRGBAAt(margin+10, 300-margin-10)
+	require.Greater(t, logoPixel.R, uint8(0xC0))
+	require.Less(t, logoPixel.B, uint8(0x40))
+	corner := img.NRGBAAt(390, 10)
+	require.Less(t, corner.R, uint8(0x20))
+	require.Greater(t, corner.B, uint8(0xE0))
+
+	// 相同输入复用结果
+	again, err := Watermark(src, opts)
+	require.NoError(t, err)
+	require.Equal(t, out, again)
+}
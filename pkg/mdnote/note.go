@@ -0,0 +1,184 @@
+package mdnote
+
+import (
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Note 从 Markdown 文件解析出的笔记
+type Note struct {
+	Title       string   `json:"title"`
+	Content     string   `json:"content"` // 转换后的纯文本正文
+	Images      []string `json:"images"`  // 按文档顺序排列的图片，本地路径已转为绝对路径
+	Tags        []string `json:"tags,omitempty"`
+	Visibility  string   `json:"visibility,omitempty"`
+	PublishAt   string   `json:"publish_at,omitempty"`
+	Draft       bool     `json:"draft,omitempty"`
+	Original    bool     `json:"original,omitempty"`
+	Declaration string   `json:"declaration,omitempty"`
+	Location    string   `json:"location,omitempty"`
+	Mentions    []string `json:"mentions,omitempty"`
+}
+
+// frontMatter 支持的 front matter 字段
+type frontMatter struct {
+	Title       string     `yaml:"title"`
+	Tags        stringList `yaml:"tags"`
+	Visibility  string     `yaml:"visibility"`
+	PublishAt   string     `yaml:"publish_at"`
+	Schedule    string     `yaml:"schedule"` // publish_at 的别名
+	Draft       bool       `yaml:"draft"`
+	Original    bool       `yaml:"original"`
+	Declaration string     `yaml:"declaration"`
+	Location    string     `yaml:"location"`
+	Mentions    stringList `yaml:"mentions"`
+	Images      stringList `yaml:"images"` // 额外的图片，排在正文图片之前
+}
+
+// stringList 兼容 YAML 列表和逗号分隔的字符串
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		for _, item := range strings.FieldsFunc(node.Value, func(r rune) bool { return r == ',' || r == '，' }) {
+			if item = strings.TrimSpace(item); item != "" {
+				*l = append(*l, item)
+			}
+		}
+		return nil
+	}
+
+	var items []string
+	if err := node.Decode(&items); err != nil {
+		return err
+	}
+	*l = items
+	return nil
+}
+
+// ParseFile 解析 Markdown 文件，图片的相对路径相对于文件所在目录
+func ParseFile(path string) (*Note, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "读取 Markdown 文件失败: %s", path)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "解析文件路径失败: %s", path)
+	}
+
+	return Parse(data, filepath.Dir(abs))
+}
+
+// Parse 解析 Markdown 内容，baseDir 用于解析图片的相对路径
+func Parse(data []byte, baseDir string) (*Note, error) {
+	fm, body, err := splitFrontMatter(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta frontMatter
+	if len(fm) > 0 {
+		if err := yaml.Unmarshal(fm, &meta); err != nil {
+			return nil, errors.Wrap(err, "解析 front matter 失败")
+		}
+	}
+
+	// 没有 title 时使用正文的第一个一级标题
+	r := render(string(body), meta.Title == "")
+
+	note := &Note{
+		Title:       strings.TrimSpace(meta.Title),
+		Content:     r.text,
+		Tags:        normalizeTags(meta.Tags),
+		Visibility:  meta.Visibility,
+		PublishAt:   meta.PublishAt,
+		Draft:       meta.Draft,
+		Original:    meta.Original,
+		Declaration: meta.Declaration,
+		Location:    meta.Location,
+		Mentions:    meta.Mentions,
+	}
+	if note.PublishAt == "" {
+		note.PublishAt = meta.Schedule
+	}
+
+	if note.Title == "" {
+		note.Title = r.title
+	}
+	if note.Title == "" {
+		return nil, errors.New("缺少标题：请在 front matter 中设置 title 或使用一级标题")
+	}
+
+	for _, img := range append([]string(meta.Images), r.images...) {
+		note.Images = append(note.Images, resolveImage(img, baseDir))
+	}
+	if len(note.Images) == 0 {
+		return nil, errors.New("没有找到图片：请在正文中引用本地图片，如 ![](./photo.jpg)")
+	}
+
+	return note, nil
+}
+
+// splitFrontMatter 拆分以 --- 包围的 YAML front matter
+func splitFrontMatter(data []byte) ([]byte, []byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	if !bytes.HasPrefix(data, []byte("---\n")) {
+		return nil, data, nil
+	}
+
+	rest := data[4:]
+	if bytes.HasPrefix(rest, []byte("---\n")) {
+		return nil, rest[4:], nil
+	}
+	end := bytes.Index(rest, []byte("\n---\n"))
+	if end < 0 {
+		if bytes.HasSuffix(rest, []byte("\n---")) {
+			return rest[:len(rest)-4], nil, nil
+		}
+		return nil, nil, errors.New("front matter 缺少结束的 ---")
+	}
+
+	return rest[:end], rest[end+5:], nil
+}
+
+// resolveImage 本地相对路径转为绝对路径，链接、data URI 和绝对路径保持不变
+func resolveImage(ref, baseDir string) string {
+	lower := strings.ToLower(ref)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "data:") {
+		return ref
+	}
+
+	if strings.HasPrefix(ref, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ref[2:])
+		}
+	}
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+	if filepath.IsAbs(ref) || baseDir == "" {
+		return filepath.Clean(ref)
+	}
+	return filepath.Join(baseDir, ref)
+}
+
+// normalizeTags 去掉标签的 # 前缀和空白
+func normalizeTags(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#")); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package mdnote
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	md := `---
+title: 周末去哪儿
+tags: [旅行, "#周末"]
+visibility: 仅自己可见
+schedule: 2025-01-02 15:04
+---
+
+# 杭州一日游
+
+![封面](./cover.jpg)
+
+**西湖** 边走走，[攻略](https://example.com/guide)在这里。
+
+## 行程
+
+1. 早上：*断桥*
+2. 中午：` + "`楼外楼`" + `
+- 雷峰塔
+  - 夜景
+- [x] 订酒店
+- [ ] 买门票
+
+> 记得带伞
+
+<img src="images/night.png" alt="夜景">
+
+---
+
+| 项目 | 花费 |
+|------|------|
+| 门票 | 40 |
+
+` + "```" + `
+**原样保留**
+` + "```" + `
+`
+
+	note, err := Parse([]byte(md), "/notes")
+	require.NoError(t, err)
+
+	require.Equal(t, "周末去哪儿", note.Title)
+	require.Equal(t, []string{"旅行", "周末"}, note.Tags)
+	require.Equal(t, "仅自己可见", note.Visibility)
+	require.Equal(t, "2025-01-02 15:04", note.PublishAt)
+	require.Equal(t, []string{"/notes/cover.jpg", "/notes/images/night.png"}, note.Images)
+
+	expected := `📌 杭州一日游
+
+西湖 边走走，攻略在这里。
+
+📌 行程
+
+1️⃣ 早上：断桥
+2️⃣ 中午：楼外楼
+• 雷峰塔
+  • 夜景
+✅ 订酒店
+⬜ 买门票
+
+💬 记得带伞
+
+项目 | 花费
+门票 | 40
+
+**原样保留**`
+	require.Equal(t, expected, note.Content)
+}
+
+func TestParseTitleFromHeading(t *testing.T) {
+	md := "# 我的标题\n\n正文第一段\n\n![](/abs/1.jpg)\n![](https://example.com/2.png)\n"
+
+	note, err := Parse([]byte(md), "/notes")
+	require.NoError(t, err)
+	require.Equal(t, "我的标题", note.Title)
+	require.Equal(t, "正文第一段", note.Content)
+	require.Equal(t, []string{"/abs/1.jpg", "https://example.com/2.png"}, note.Images)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse([]byte("没有标题\n\n![](a.jpg)"), "")
+	require.ErrorContains(t, err, "缺少标题")
+
+	_, err = Parse([]byte("# 标题\n\n没有图片"), "")
+	require.ErrorContains(t, err, "没有找到图片")
+
+	_, err = Parse([]byte("---\ntitle: 标题\n"), "")
+	require.ErrorContains(t, err, "front matter")
+}
+
+func TestParseFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "note.md")
+	md := "---\r\ntitle: 标题\r\ntags: 美食, 探店\r\nimages:\r\n  - extra.jpg\r\n---\r\n正文\r\n\r\n![](my%20photo.jpg)\r\n"
+	require.NoError(t, os.WriteFile(path, []byte(md), 0644))
+
+	note, err := ParseFile(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"美食", "探店"}, note.Tags)
+	require.Equal(t, "正文", note.Content)
+	require.Equal(t, []string{filepath.Join(dir, "extra.jpg"), filepath.Join(dir, "my photo.jpg")}, note.Images)
+}
@@ -0,0 +1,194 @@
+package mdnote
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingPattern   = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	hrPattern        = regexp.MustCompile(`^\s{0,3}([-*_])(?:\s*([-*_])){2,}\s*$`)
+	quotePattern     = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	taskPattern      = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	bulletPattern    = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedPattern   = regexp.MustCompile(`^(\s*)(\d{1,3})[.)]\s+(.*)$`)
+	fencePattern     = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+	tableSepPattern  = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	htmlComment      = regexp.MustCompile(`<!--.*?-->`)
+	imagePattern     = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)>\s]+)>?(?:\s+["'][^"']*["'])?\s*\)`)
+	htmlImagePattern = regexp.MustCompile(`(?i)<img\s[^>]*?src=["']([^"']+)["'][^>]*>`)
+	linkPattern      = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	autoLinkPattern  = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	codePattern      = regexp.MustCompile("`+([^`]+)`+")
+	boldPattern      = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	italicPattern    = regexp.MustCompile(`\*([^*\s][^*]*?)\*|(^|[^\w])_([^_\s][^_]*?)_([^\w]|$)`)
+	strikePattern    = regexp.MustCompile(`~~(.+?)~~`)
+	escapePattern    = regexp.MustCompile("\\\\([\\\\`*_{}\\[\\]()#+\\-.!>|~])")
+	htmlTagPattern   = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	blankLines       = regexp.MustCompile(`\n{3,}`)
+)
+
+// 转换后的段落前缀，使正文在小红书中有层次
+const (
+	headingPrefix    = "📌 "
+	subheadingPrefix = "🔹 "
+	bulletPrefix     = "• "
+	quotePrefix      = "💬 "
+	taskDonePrefix   = "✅ "
+	taskTodoPrefix   = "⬜ "
+)
+
+// keycaps 有序列表 1-10 使用数字表情
+var keycaps = []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"}
+
+// rendered Markdown 转换结果
+type rendered struct {
+	text   string
+	title  string   // 作为标题取出的一级标题
+	images []string // 按文档顺序出现的图片
+}
+
+// render 将 Markdown 转为适合小红书的纯文本，取出图片引用。
+// takeTitle 为 true 时第一个一级标题作为笔记标题，不出现在正文中。
+func render(body string, takeTitle bool) rendered {
+	var (
+		r       rendered
+		out     []string
+		inFence bool
+	)
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, " \t")
+
+		// 代码块原样保留，只去掉围栏
+		if fencePattern.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+
+		line = htmlComment.ReplaceAllString(line, "")
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			out = append(out, "")
+
+		case hrPattern.MatchString(line):
+			out = append(out, "")
+
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			text := r.inline(m[2])
+			if len(m[1]) == 1 && takeTitle && r.title == "" {
+				r.title = text
+				continue
+			}
+			if text == "" {
+				continue
+			}
+			prefix := headingPrefix
+			if len(m[1]) > 2 {
+				prefix = subheadingPrefix
+			}
+			out = append(out, prefix+text)
+
+		case quotePattern.MatchString(line):
+			if text := r.inline(quotePattern.FindStringSubmatch(line)[1]); text != "" {
+				out = append(out, quotePrefix+text)
+			}
+
+		case taskPattern.MatchString(line):
+			m := taskPattern.FindStringSubmatch(line)
+			prefix := taskTodoPrefix
+			if m[2] != " " {
+				prefix = taskDonePrefix
+			}
+			out = append(out, indent(m[1])+prefix+r.inline(m[3]))
+
+		case bulletPattern.MatchString(line):
+			m := bulletPattern.FindStringSubmatch(line)
+			if text := r.inline(m[2]); text != "" {
+				out = append(out, indent(m[1])+bulletPrefix+text)
+			}
+
+		case orderedPattern.MatchString(line):
+			m := orderedPattern.FindStringSubmatch(line)
+			out = append(out, indent(m[1])+keycap(m[2])+" "+r.inline(m[3]))
+
+		case strings.HasPrefix(strings.TrimSpace(line), "|"):
+			if tableSepPattern.MatchString(line) {
+				continue
+			}
+			out = append(out, r.tableRow(line))
+
+		default:
+			// 只有图片的行去掉后不留空行
+			if text := r.inline(line); text != "" {
+				out = append(out, text)
+			} else if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+		}
+	}
+
+	r.text = strings.TrimSpace(collapseBlankLines(strings.Join(out, "\n")))
+	return r
+}
+
+// inline 转换行内格式：取出图片，链接保留文字，去掉强调、代码和 HTML 标记
+func (r *rendered) inline(text string) string {
+	text = imagePattern.ReplaceAllStringFunc(text, func(s string) string {
+		r.images = append(r.images, imagePattern.FindStringSubmatch(s)[1])
+		return ""
+	})
+	text = htmlImagePattern.ReplaceAllStringFunc(text, func(s string) string {
+		r.images = append(r.images, htmlImagePattern.FindStringSubmatch(s)[1])
+		return ""
+	})
+
+	text = linkPattern.ReplaceAllString(text, "$1")
+	text = autoLinkPattern.ReplaceAllString(text, "$1")
+	text = codePattern.ReplaceAllString(text, "$1")
+	text = boldPattern.ReplaceAllString(text, "$1$2")
+	text = italicPattern.ReplaceAllString(text, "$1$2$3$4")
+	text = strikePattern.ReplaceAllString(text, "$1")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = escapePattern.ReplaceAllString(text, "$1")
+
+	return strings.TrimSpace(text)
+}
+
+// tableRow 表格行转为以竖线分隔的文字
+func (r *rendered) tableRow(line string) string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+
+	var cells []string
+	for _, cell := range strings.Split(line, "|") {
+		if cell = r.inline(cell); cell != "" {
+			cells = append(cells, cell)
+		}
+	}
+	return strings.Join(cells, " | ")
+}
+
+// indent 嵌套列表每级缩进两个空格
+func indent(spaces string) string {
+	n := len(strings.ReplaceAll(spaces, "\t", "    ")) / 2
+	return strings.Repeat("  ", n)
+}
+
+func keycap(num string) string {
+	if n, err := strconv.Atoi(num); err == nil && n >= 1 && n <= len(keycaps) {
+		return keycaps[n-1]
+	}
+	return num + "."
+}
+
+func collapseBlankLines(text string) string {
+	return blankLines.ReplaceAllString(text, "\n\n")
+}
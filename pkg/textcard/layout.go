@@ -0,0 +1,180 @@
+package textcard
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 行类型
+const (
+	lineTitle = iota
+	lineText
+	lineTitleGap     // 标题与正文之间的间隔，绘制装饰线
+	lineParagraphGap // 段落之间的间隔
+)
+
+// line 排版后的一行
+type line struct {
+	kind int
+	text string
+}
+
+// metrics 各类行的高度和单页可用高度，单位像素
+type metrics struct {
+	titleLine    int
+	textLine     int
+	titleGap     int
+	paragraphGap int
+	pageHeight   int // 单页可用于正文的高度
+}
+
+// height 返回该行占用的高度
+func (m metrics) height(l line) int {
+	switch l.kind {
+	case lineTitle:
+		return m.titleLine
+	case lineTitleGap:
+		return m.titleGap
+	case lineParagraphGap:
+		return m.paragraphGap
+	default:
+		return m.textLine
+	}
+}
+
+// closingPunct 不能出现在行首的标点
+const closingPunct = "，。、；：？！）》」』】〉”’…—,.;:?!)]}%"
+
+// splitParagraphs 按换行拆分段落，去掉空段落
+func splitParagraphs(text string) []string {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
+// tokenize 切分为折行的最小单位：中日韩文字和全角符号逐字切分，
+// 连续的英文字母、数字和半角符号作为一个单词，空格单独成为一个单位
+func tokenize(s string) []string {
+	var tokens []string
+	var word strings.Builder
+
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+			tokens = append(tokens, " ")
+		case r > 0x2E7F || (r > unicode.MaxASCII && strings.ContainsRune(closingPunct, r)):
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// wrapText 按最大宽度折行，单词超过一行时逐字断开，行首标点挤入上一行
+func wrapText(text string, maxWidth int, measure func(string) int) []string {
+	var lines []string
+	var cur string
+
+	var add func(tok string)
+	add = func(tok string) {
+		if cur == "" && tok == " " {
+			return
+		}
+		if measure(cur+tok) <= maxWidth {
+			cur += tok
+			return
+		}
+		if strings.Contains(closingPunct, tok) && cur != "" {
+			cur += tok
+			return
+		}
+		if cur == "" || measure(tok) > maxWidth {
+			// 超长单词逐字断开
+			runes := []rune(tok)
+			if len(runes) > 1 {
+				for _, r := range runes {
+					add(string(r))
+				}
+				return
+			}
+			if cur == "" {
+				cur = tok
+				return
+			}
+		}
+		lines = append(lines, strings.TrimRight(cur, " "))
+		cur = strings.TrimLeft(tok, " ")
+	}
+
+	for _, tok := range tokenize(text) {
+		add(tok)
+	}
+	if cur = strings.TrimRight(cur, " "); cur != "" {
+		lines = append(lines, cur)
+	}
+
+	return lines
+}
+
+// layoutLines 将标题和段落排成行
+func layoutLines(titleLines []string, paragraphs [][]string) []line {
+	var lines []line
+	for _, t := range titleLines {
+		lines = append(lines, line{kind: lineTitle, text: t})
+	}
+	if len(titleLines) > 0 {
+		lines = append(lines, line{kind: lineTitleGap})
+	}
+
+	for i, p := range paragraphs {
+		if i > 0 {
+			lines = append(lines, line{kind: lineParagraphGap})
+		}
+		for _, t := range p {
+			lines = append(lines, line{kind: lineText, text: t})
+		}
+	}
+
+	return lines
+}
+
+// paginate 按单页可用高度分页，新页不以段落间隔开头
+func paginate(lines []line, m metrics) [][]line {
+	var pages [][]line
+	var cur []line
+	used := 0
+
+	for _, l := range lines {
+		h := m.height(l)
+		if used+h > m.pageHeight && len(cur) > 0 {
+			pages = append(pages, cur)
+			cur, used = nil, 0
+		}
+		if len(cur) == 0 && l.kind == lineParagraphGap {
+			continue
+		}
+		cur = append(cur, l)
+		used += h
+	}
+	if len(cur) > 0 {
+		pages = append(pages, cur)
+	}
+
+	return pages
+}
@@ -0,0 +1,59 @@
+package textcard
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runeWidth 每个字符宽度为 1 的测量函数
+func runeWidth(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+func TestSplitParagraphs(t *testing.T) {
+	require.Equal(t, []string{"第一段", "第二段"}, splitParagraphs("第一段\r\n\n  \n 第二段 \n"))
+	require.Empty(t, splitParagraphs(" \n "))
+}
+
+func TestTokenize(t *testing.T) {
+	require.Equal(t, []string{"今", "天", "去", " ", "Costco", " ", "买", "了", "iPhone16", "。"}, tokenize("今天去 Costco 买了iPhone16。"))
+}
+
+func TestWrapText(t *testing.T) {
+	// 中文逐字折行
+	require.Equal(t, []string{"一二三四", "五六七"}, wrapText("一二三四五六七", 4, runeWidth))
+
+	// 英文单词不拆开
+	require.Equal(t, []string{"hello", "world"}, wrapText("hello world", 8, runeWidth))
+
+	// 超长单词逐字断开
+	require.Equal(t, []string{"abcd", "efg"}, wrapText("abcdefg", 4, runeWidth))
+
+	// 行首标点挤入上一行
+	require.Equal(t, []string{"一二三四。", "五六"}, wrapText("一二三四。五六", 4, runeWidth))
+}
+
+func TestPaginate(t *testing.T) {
+	m := metrics{titleLine: 20, textLine: 10, titleGap: 10, paragraphGap: 5, pageHeight: 50}
+
+	lines := layoutLines([]string{"标题"}, [][]string{{"a", "b"}, {"c", "d", "e"}})
+	pages := paginate(lines, m)
+
+	// 第一页：标题(20) + 间隔(10) + a + b = 50
+	require.Len(t, pages, 2)
+	require.Equal(t, []line{
+		{kind: lineTitle, text: "标题"},
+		{kind: lineTitleGap},
+		{kind: lineText, text: "a"},
+		{kind: lineText, text: "b"},
+	}, pages[0])
+
+	// 新页不以段落间隔开头
+	require.Equal(t, []line{
+		{kind: lineText, text: "c"},
+		{kind: lineText, text: "d"},
+		{kind: lineText, text: "e"},
+	}, pages[1])
+}
@@ -0,0 +1,303 @@
+package textcard
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// DefaultWidth 卡片默认宽度，高度按 3:4 计算
+const DefaultWidth = 1080
+
+// FontPathEnv 指定中文字体文件的环境变量
+const FontPathEnv = "XHS_CARD_FONT"
+
+// systemFontPaths 未指定字体时依次尝试的系统中文字体
+var systemFontPaths = []string{
+	"/System/Library/Fonts/PingFang.ttc",
+	"/System/Library/Fonts/STHeiti Medium.ttc",
+	"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+	"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc",
+	"/usr/share/fonts/google-noto-cjk/NotoSansCJK-Regular.ttc",
+	"/usr/share/fonts/truetype/wqy/wqy-microhei.ttc",
+	"/usr/share/fonts/wenquanyi/wqy-microhei/wqy-microhei.ttc",
+	`C:\Windows\Fonts\msyh.ttc`,
+	`C:\Windows\Fonts\simhei.ttf`,
+}
+
+// Options 渲染选项
+type Options struct {
+	Theme     string // 主题名称，为空使用默认主题
+	FontPath  string // 字体文件路径（ttf/otf/ttc），为空时依次查找环境变量和系统字体
+	Watermark string // 水印文字，显示在卡片左下角
+	Width     int    // 卡片宽度，默认 DefaultWidth
+	OutputDir string // 输出目录，默认与下载图片相同的临时目录
+	MaxPages  int    // 最多渲染的卡片数，正文超出时返回错误，0 表示不限制
+}
+
+// Render 将标题和正文渲染为一张或多张 3:4 的 PNG 卡片，返回图片路径。
+// 正文按换行分段，超出一页时自动分页，标题只出现在第一页。
+func Render(title, text string, opts Options) ([]string, error) {
+	title = strings.TrimSpace(title)
+	paragraphs := splitParagraphs(text)
+	if title == "" && len(paragraphs) == 0 {
+		return nil, errors.New("卡片标题和正文不能都为空")
+	}
+
+	theme, err := GetTheme(opts.Theme)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Width <= 0 {
+		opts.Width = DefaultWidth
+	}
+	if opts.OutputDir == "" {
+		opts.OutputDir = configs.GetImagesPath()
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return nil, errors.Wrap(err, "failed to create output dir")
+	}
+
+	f, err := ResolveFont(opts.FontPath, ContainsCJK(title+text+opts.Watermark))
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := newRenderer(f, theme, opts.Width)
+	if err != nil {
+		return nil, err
+	}
+	defer r.close()
+
+	pages := r.layout(title, paragraphs)
+	if opts.MaxPages > 0 && len(pages) > opts.MaxPages {
+		return nil, errors.Errorf("正文需要 %d 张卡片，超过 %d 张的上限，请精简正文", len(pages), opts.MaxPages)
+	}
+
+	key, _ := json.Marshal([]any{title, paragraphs, opts.Theme, opts.FontPath, opts.Watermark, opts.Width})
+	sum := sha256.Sum256(key)
+
+	paths := make([]string, 0, len(pages))
+	for i, page := range pages {
+		img := r.draw(page, i, len(pages), opts.Watermark)
+
+		path := filepath.Join(opts.OutputDir, fmt.Sprintf("card_%x_%d.png", sum[:8], i+1))
+		if err := savePNG(path, img); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	logrus.Infof("文字卡片渲染完成: %d 张", len(paths))
+	return paths, nil
+}
+
+// renderer 持有字体和按卡片宽度缩放后的尺寸
+type renderer struct {
+	theme     Theme
+	width     int
+	height    int
+	padding   int
+	titleFace font.Face
+	textFace  font.Face
+	smallFace font.Face
+	metrics   metrics
+	footer    int // 底部水印和页码区域的高度
+}
+
+// newRenderer 创建渲染器，字号和边距按 1080 宽度等比缩放
+func newRenderer(f *opentype.Font, theme Theme, width int) (*renderer, error) {
+	scale := float64(width) / DefaultWidth
+	titleSize := theme.TitleSize * scale
+	textSize := theme.TextSize * scale
+
+	newFace := func(size float64) (font.Face, error) {
+		return opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	}
+	titleFace, err := newFace(titleSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create font face")
+	}
+	textFace, _ := newFace(textSize)
+	smallFace, _ := newFace(textSize * 0.6)
+
+	r := &renderer{
+		theme:     theme,
+		width:     width,
+		height:    width * 4 / 3,
+		padding:   int(float64(theme.Padding) * scale),
+		titleFace: titleFace,
+		textFace:  textFace,
+		smallFace: smallFace,
+		footer:    int(textSize * 1.5),
+	}
+	r.metrics = metrics{
+		titleLine:    int(titleSize * 1.45),
+		textLine:     int(textSize * theme.LineSpacing),
+		titleGap:     int(textSize * 1.4),
+		paragraphGap: int(textSize * 0.6),
+		pageHeight:   r.height - 2*r.padding - r.footer,
+	}
+
+	return r, nil
+}
+
+// close 释放字体资源
+func (r *renderer) close() {
+	r.titleFace.Close()
+	r.textFace.Close()
+	r.smallFace.Close()
+}
+
+// layout 折行并分页
+func (r *renderer) layout(title string, paragraphs []string) [][]line {
+	maxWidth := r.width - 2*r.padding
+
+	var titleLines []string
+	if title != "" {
+		titleLines = wrapText(title, maxWidth, measureWith(r.titleFace))
+	}
+
+	wrapped := make([][]string, 0, len(paragraphs))
+	for _, p := range paragraphs {
+		wrapped = append(wrapped, wrapText(p, maxWidth, measureWith(r.textFace)))
+	}
+
+	return paginate(layoutLines(titleLines, wrapped), r.metrics)
+}
+
+// draw 绘制一页卡片
+func (r *renderer) draw(lines []line, index, total int, watermark string) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, r.width, r.height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(r.theme.Background), image.Point{}, draw.Src)
+
+	y := r.padding
+	for _, l := range lines {
+		h := r.metrics.height(l)
+		switch l.kind {
+		case lineTitle:
+			r.drawText(img, r.titleFace, r.theme.TitleColor, l.text, r.padding, y, h)
+		case lineText:
+			r.drawText(img, r.textFace, r.theme.TextColor, l.text, r.padding, y, h)
+		case lineTitleGap:
+			bar := image.Rect(r.padding, y+h/2-r.width/360, r.padding+r.width/12, y+h/2+r.width/360)
+			draw.Draw(img, bar, image.NewUniform(r.theme.AccentColor), image.Point{}, draw.Src)
+		}
+		y += h
+	}
+
+	footerY := r.height - r.padding/2 - r.footer
+	if watermark != "" {
+		r.drawText(img, r.smallFace, r.theme.WatermarkColor, watermark, r.padding, footerY, r.footer)
+	}
+	if total > 1 {
+		pageNo := fmt.Sprintf("%d/%d", index+1, total)
+		x := r.width - r.padding - measureWith(r.smallFace)(pageNo)
+		r.drawText(img, r.smallFace, r.theme.WatermarkColor, pageNo, x, footerY, r.footer)
+	}
+
+	return img
+}
+
+// drawText 在高度为 h 的行内垂直居中绘制文字
+func (r *renderer) drawText(img *image.RGBA, face font.Face, c color.Color, text string, x, y, h int) {
+	m := face.Metrics()
+	ascent, descent := m.Ascent.Ceil(), m.Descent.Ceil()
+	baseline := y + (h-ascent-descent)/2 + ascent
+
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, baseline)}
+	d.DrawString(text)
+}
+
+// measureWith 返回使用指定字体测量文字宽度的函数
+func measureWith(face font.Face) func(string) int {
+	return func(s string) int {
+		return font.MeasureString(face, s).Ceil()
+	}
+}
+
+// ResolveFont 按 fontPath、环境变量、系统字体的顺序加载字体。
+// 都找不到时，不含中日韩文字的内容使用内置的英文字体。
+func ResolveFont(fontPath string, needCJK bool) (*opentype.Font, error) {
+	if fontPath != "" {
+		return loadFont(fontPath)
+	}
+	if env := os.Getenv(FontPathEnv); env != "" {
+		return loadFont(env)
+	}
+
+	for _, path := range systemFontPaths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		f, err := loadFont(path)
+		if err != nil {
+			logrus.Warnf("加载系统字体失败: %s %v", path, err)
+			continue
+		}
+		return f, nil
+	}
+
+	if needCJK {
+		return nil, errors.Errorf("没有找到中文字体，请通过 font_path 参数或 %s 环境变量指定 ttf/otf/ttc 字体文件", FontPathEnv)
+	}
+	return opentype.Parse(goregular.TTF)
+}
+
+// loadFont 加载字体文件，字体集合（ttc）使用其中第一个字体
+func loadFont(path string) (*opentype.Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "读取字体文件失败: %s", path)
+	}
+
+	collection, err := opentype.ParseCollection(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "解析字体文件失败: %s", path)
+	}
+	f, err := collection.Font(0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "解析字体文件失败: %s", path)
+	}
+	return f, nil
+}
+
+// ContainsCJK 是否包含中日韩文字
+func ContainsCJK(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
+
+// savePNG 保存 PNG 图片
+func savePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to create image file")
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
+		return errors.Wrap(err, "failed to encode image")
+	}
+	return nil
+}
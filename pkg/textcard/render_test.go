@@ -0,0 +1,58 @@
+package textcard
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestGetTheme(t *testing.T) {
+	theme, err := GetTheme("")
+	require.NoError(t, err)
+	require.Equal(t, themes[DefaultTheme], theme)
+
+	_, err = GetTheme("Dark")
+	require.NoError(t, err)
+
+	_, err = GetTheme("neon")
+	require.ErrorContains(t, err, "不支持的主题")
+}
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	fontPath := filepath.Join(dir, "goregular.ttf")
+	require.NoError(t, os.WriteFile(fontPath, goregular.TTF, 0644))
+
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+	paragraphs := strings.Repeat(text+"\n", 4)
+
+	paths, err := Render("Weekend notes", paragraphs, Options{
+		Theme:     "dark",
+		FontPath:  fontPath,
+		Watermark: "@xiaohongshu-mcp",
+		OutputDir: dir,
+	})
+	require.NoError(t, err)
+	require.Greater(t, len(paths), 1)
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		cfg, err := png.DecodeConfig(f)
+		f.Close()
+		require.NoError(t, err)
+		require.Equal(t, DefaultWidth, cfg.Width)
+		require.Equal(t, DefaultWidth*4/3, cfg.Height)
+	}
+
+	_, err = Render("Weekend notes", paragraphs, Options{FontPath: fontPath, OutputDir: dir, MaxPages: 1})
+	require.ErrorContains(t, err, "超过 1 张的上限")
+
+	_, err = Render("", " \n", Options{FontPath: fontPath, OutputDir: dir})
+	require.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package textcard
+
+import (
+	"image/color"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultTheme 默认主题
+const DefaultTheme = "light"
+
+// Theme 卡片主题：配色、边距、字号和水印样式
+type Theme struct {
+	Background     color.RGBA
+	TitleColor     color.RGBA
+	TextColor      color.RGBA
+	AccentColor    color.RGBA // 标题下方装饰线
+	WatermarkColor color.RGBA
+	Padding        int     // 四周留白，单位像素（按 1080 宽度）
+	TitleSize      float64 // 标题字号
+	TextSize       float64 // 正文字号
+	LineSpacing    float64 // 行高与字号的比例
+}
+
+// themes 内置主题
+var themes = map[string]Theme{
+	"light": {
+		Background:     rgb(0xFFFFFF),
+		TitleColor:     rgb(0x222222),
+		TextColor:      rgb(0x333333),
+		AccentColor:    rgb(0xFF2442),
+		WatermarkColor: rgb(0xBBBBBB),
+		Padding:        96,
+		TitleSize:      64,
+		TextSize:       44,
+		LineSpacing:    1.7,
+	},
+	"dark": {
+		Background:     rgb(0x1E1E1E),
+		TitleColor:     rgb(0xFFFFFF),
+		TextColor:      rgb(0xE0E0E0),
+		AccentColor:    rgb(0xFF2442),
+		WatermarkColor: rgb(0x666666),
+		Padding:        96,
+		TitleSize:      64,
+		TextSize:       44,
+		LineSpacing:    1.7,
+	},
+	"warm": {
+		Background:     rgb(0xFFF6E9),
+		TitleColor:     rgb(0x5C3D2E),
+		TextColor:      rgb(0x6B4F3F),
+		AccentColor:    rgb(0xE0A96D),
+		WatermarkColor: rgb(0xC9B29B),
+		Padding:        110,
+		TitleSize:      62,
+		TextSize:       42,
+		LineSpacing:    1.8,
+	},
+	"mint": {
+		Background:     rgb(0xE8F6F0),
+		TitleColor:     rgb(0x1F4E3D),
+		TextColor:      rgb(0x2F5D4C),
+		AccentColor:    rgb(0x48B08A),
+		WatermarkColor: rgb(0x9CC5B5),
+		Padding:        110,
+		TitleSize:      62,
+		TextSize:       42,
+		LineSpacing:    1.8,
+	},
+}
+
+// GetTheme 根据名称获取主题，名称为空时返回默认主题
+func GetTheme(name string) (Theme, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultTheme
+	}
+
+	theme, ok := themes[name]
+	if !ok {
+		return Theme{}, errors.Errorf("不支持的主题: %s，可选值: %s", name, strings.Join(ThemeNames(), "|"))
+	}
+	return theme, nil
+}
+
+// ThemeNames 返回所有内置主题名称
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rgb 将 0xRRGGBB 转为不透明颜色
+func rgb(v uint32) color.RGBA {
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xFF}
+}
@@ -0,0 +1,200 @@
+package videoprobe
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Limits 平台对上传视频的限制，可通过 JSON 文件配置。数值为 0 表示不限制，编码列表为空表示不限制。
+type Limits struct {
+	MaxSizeMB          int64    `json:"max_size_mb"`          // 文件大小上限
+	MinDurationSeconds float64  `json:"min_duration_seconds"` // 最短时长
+	MaxDurationSeconds float64  `json:"max_duration_seconds"` // 最长时长
+	MinShortSide       int      `json:"min_short_side"`       // 分辨率短边下限
+	MaxLongSide        int      `json:"max_long_side"`        // 分辨率长边上限
+	MaxBitrateKbps     int64    `json:"max_bitrate_kbps"`     // 平均码率上限
+	VideoCodecs        []string `json:"video_codecs"`         // 支持的视频编码，如 h264、h265
+	AudioCodecs        []string `json:"audio_codecs"`         // 支持的音频编码，如 aac
+}
+
+// DefaultLimits 返回小红书网页端公布的上传限制：20GB 以内、60 分钟以内。不符合时拒绝上传
+func DefaultLimits() Limits {
+	return Limits{
+		MaxSizeMB:          20 * 1024,
+		MaxDurationSeconds: 60 * 60,
+	}
+}
+
+// RecommendedLimits 返回平台推荐的视频规格：720P 及以上，H.264/H.265 编码。不符合时只给出警告
+func RecommendedLimits() Limits {
+	return Limits{
+		MinShortSide: 720,
+		VideoCodecs:  []string{"h264", "h265"},
+	}
+}
+
+// LoadLimits 加载限制配置文件，文件中非零的数值和非空的编码列表覆盖默认值。
+// 文件不存在时返回默认限制。
+func LoadLimits(path string) (Limits, error) {
+	limits := DefaultLimits()
+	if path == "" {
+		return limits, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return limits, nil
+	}
+	if err != nil {
+		return limits, errors.Wrapf(err, "读取视频限制配置失败: %s", path)
+	}
+
+	var custom Limits
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return limits, errors.Wrapf(err, "解析视频限制配置失败: %s", path)
+	}
+
+	if custom.MaxSizeMB > 0 {
+		limits.MaxSizeMB = custom.MaxSizeMB
+	}
+	if custom.MinDurationSeconds > 0 {
+		limits.MinDurationSeconds = custom.MinDurationSeconds
+	}
+	if custom.MaxDurationSeconds > 0 {
+		limits.MaxDurationSeconds = custom.MaxDurationSeconds
+	}
+	if custom.MinShortSide > 0 {
+		limits.MinShortSide = custom.MinShortSide
+	}
+	if custom.MaxLongSide > 0 {
+		limits.MaxLongSide = custom.MaxLongSide
+	}
+	if custom.MaxBitrateKbps > 0 {
+		limits.MaxBitrateKbps = custom.MaxBitrateKbps
+	}
+	if len(custom.VideoCodecs) > 0 {
+		limits.VideoCodecs = custom.VideoCodecs
+	}
+	if len(custom.AudioCodecs) > 0 {
+		limits.AudioCodecs = custom.AudioCodecs
+	}
+
+	return limits, nil
+}
+
+// GetLimitsFilePath 获取视频限制配置文件路径
+func GetLimitsFilePath() string {
+	path := os.Getenv("VIDEO_LIMITS_PATH")
+	if path == "" {
+		path = "video_limits.json"
+	}
+	return path
+}
+
+// Violation 不符合平台限制的项
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// 限制项名称
+const (
+	RuleSize         = "size"
+	RuleDuration     = "duration"
+	RuleResolution   = "resolution"
+	RuleBitrate      = "bitrate"
+	RuleVideoCodec   = "video_codec"
+	RuleAudioCodec   = "audio_codec"
+	RuleNoVideoTrack = "no_video_track"
+)
+
+// Check 检查视频信息是否符合限制
+func (l Limits) Check(info *Info) []Violation {
+	var violations []Violation
+	add := func(rule, format string, args ...any) {
+		violations = append(violations, Violation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if l.MaxSizeMB > 0 && info.Size > l.MaxSizeMB<<20 {
+		add(RuleSize, "文件大小 %.1fMB，超过 %dMB 限制", float64(info.Size)/(1<<20), l.MaxSizeMB)
+	}
+
+	if info.VideoCodec == "" {
+		add(RuleNoVideoTrack, "文件中没有视频轨道")
+		return violations
+	}
+
+	seconds := info.Duration.Seconds()
+	if l.MinDurationSeconds > 0 && seconds < l.MinDurationSeconds {
+		add(RuleDuration, "视频时长 %s，短于 %s", formatSeconds(seconds), formatSeconds(l.MinDurationSeconds))
+	}
+	if l.MaxDurationSeconds > 0 && seconds > l.MaxDurationSeconds {
+		add(RuleDuration, "视频时长 %s，超过 %s 限制", formatSeconds(seconds), formatSeconds(l.MaxDurationSeconds))
+	}
+
+	short, long := info.Width, info.Height
+	if short > long {
+		short, long = long, short
+	}
+	if l.MinShortSide > 0 && short < l.MinShortSide {
+		add(RuleResolution, "分辨率 %dx%d 过低，短边至少 %d", info.Width, info.Height, l.MinShortSide)
+	}
+	if l.MaxLongSide > 0 && long > l.MaxLongSide {
+		add(RuleResolution, "分辨率 %dx%d 过高，长边最多 %d", info.Width, info.Height, l.MaxLongSide)
+	}
+
+	if l.MaxBitrateKbps > 0 && info.Bitrate > l.MaxBitrateKbps*1000 {
+		add(RuleBitrate, "平均码率 %dkbps，超过 %dkbps 限制", info.Bitrate/1000, l.MaxBitrateKbps)
+	}
+
+	if !containsFold(l.VideoCodecs, info.VideoCodec) {
+		add(RuleVideoCodec, "视频编码 %s 不受支持，支持: %s", info.VideoCodec, strings.Join(l.VideoCodecs, "/"))
+	}
+	if info.AudioCodec != "" && !containsFold(l.AudioCodecs, info.AudioCodec) {
+		add(RuleAudioCodec, "音频编码 %s 不受支持，支持: %s", info.AudioCodec, strings.Join(l.AudioCodecs, "/"))
+	}
+
+	return violations
+}
+
+// Validate 读取视频信息并检查限制，不符合时返回汇总错误；符合限制时返回不符合推荐规格的项作为警告。
+// 文件不是 MP4/MOV 时返回 ErrUnsupportedFormat，调用方可以跳过检查直接上传。
+func (l Limits) Validate(path string) (*Info, []Violation, error) {
+	info, err := Probe(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	violations := l.Check(info)
+	if len(violations) == 0 {
+		return info, RecommendedLimits().Check(info), nil
+	}
+
+	msgs := make([]string, 0, len(violations))
+	for _, v := range violations {
+		msgs = append(msgs, v.Message)
+	}
+	return info, nil, errors.Errorf("视频不符合平台要求: %s", strings.Join(msgs, "; "))
+}
+
+// containsFold 列表为空时视为不限制
+func containsFold(list []string, s string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), s) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatSeconds(seconds float64) string {
+	return (time.Duration(seconds * float64(time.Second))).Round(100 * time.Millisecond).String()
+}
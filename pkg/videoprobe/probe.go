@@ -0,0 +1,386 @@
+package videoprobe
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnsupportedFormat 文件不是 MP4/MOV 容器，无法读取视频信息
+var ErrUnsupportedFormat = errors.New("无法解析的视频格式，仅能读取 MP4/MOV 的视频信息")
+
+// maxLeafBoxSize 读取到内存中的元数据 box 的大小上限，避免异常文件占用过多内存
+const maxLeafBoxSize = 16 << 20
+
+// Info 从容器中读取的视频信息
+type Info struct {
+	Container  string        `json:"container"`       // mp4/mov
+	Brand      string        `json:"brand,omitempty"` // ftyp 中的 major brand
+	Size       int64         `json:"size"`            // 文件大小（字节）
+	Duration   time.Duration `json:"duration"`
+	Width      int           `json:"width"`    // 显示宽度，已按旋转角度调整
+	Height     int           `json:"height"`   // 显示高度，已按旋转角度调整
+	Rotation   int           `json:"rotation"` // 顺时针旋转角度：0/90/180/270
+	VideoCodec string        `json:"video_codec"`
+	AudioCodec string        `json:"audio_codec,omitempty"`
+	Bitrate    int64         `json:"bitrate"` // 平均码率（bit/s），按文件大小和时长计算
+}
+
+// track 解析 trak 过程中收集的信息
+type track struct {
+	handler   string
+	codec     string
+	width     int
+	height    int
+	rotation  int
+	timescale uint32
+	duration  uint64
+}
+
+// parser 解析 ISO BMFF（MP4）和 QuickTime（MOV）容器，只读取 moov 中的元数据，跳过 mdat
+type parser struct {
+	r      io.ReaderAt
+	info   *Info
+	tracks []*track
+	cur    *track
+
+	timescale    uint32 // mvhd
+	duration     uint64 // mvhd
+	fragDuration uint64 // mvex/mehd，分片 MP4 的总时长
+	hasMoov      bool
+}
+
+// Probe 读取视频文件的时长、分辨率、编码和码率
+func Probe(path string) (*Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "打开视频文件失败: %s", path)
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return nil, errors.Wrapf(err, "读取视频文件信息失败: %s", path)
+	}
+
+	return ProbeReader(f, st.Size())
+}
+
+// ProbeReader 从 r 中读取视频信息，size 为数据总长度
+func ProbeReader(r io.ReaderAt, size int64) (*Info, error) {
+	p := &parser{r: r, info: &Info{Size: size, Container: "mp4"}}
+
+	if err := p.walk(0, size, 0); err != nil {
+		return nil, err
+	}
+	if !p.hasMoov {
+		return nil, ErrUnsupportedFormat
+	}
+
+	p.finish()
+	return p.info, nil
+}
+
+// walk 遍历 [start, end) 范围内的 box
+func (p *parser) walk(start, end int64, depth int) error {
+	if depth > 16 {
+		return errors.New("视频文件 box 嵌套过深")
+	}
+
+	for off := start; off+8 <= end; {
+		var hdr [16]byte
+		if _, err := p.r.ReadAt(hdr[:8], off); err != nil {
+			return errors.Wrap(err, "读取视频文件失败")
+		}
+
+		size := int64(binary.BigEndian.Uint32(hdr[:4]))
+		typ := string(hdr[4:8])
+		headerLen := int64(8)
+
+		switch size {
+		case 0: // 延伸到文件末尾
+			size = end - off
+		case 1: // 64 位长度
+			if _, err := p.r.ReadAt(hdr[8:16], off+8); err != nil {
+				return errors.Wrap(err, "读取视频文件失败")
+			}
+			size = int64(binary.BigEndian.Uint64(hdr[8:16]))
+			headerLen = 16
+		}
+
+		if depth == 0 && off == 0 && !isKnownTopLevel(typ) {
+			return ErrUnsupportedFormat
+		}
+		if size < headerLen || off+size > end {
+			// 文件被截断或 box 长度错误
+			if depth == 0 && p.hasMoov {
+				return nil
+			}
+			return errors.Errorf("视频文件已损坏：%s box 长度异常", strings.TrimSpace(typ))
+		}
+
+		if err := p.box(typ, off+headerLen, off+size, depth); err != nil {
+			return err
+		}
+		off += size
+	}
+
+	return nil
+}
+
+// box 处理单个 box，容器类 box 继续向下遍历
+func (p *parser) box(typ string, start, end int64, depth int) error {
+	switch typ {
+	case "moov":
+		p.hasMoov = true
+		return p.walk(start, end, depth+1)
+	case "trak":
+		p.cur = &track{}
+		p.tracks = append(p.tracks, p.cur)
+		err := p.walk(start, end, depth+1)
+		p.cur = nil
+		return err
+	case "mdia", "minf", "stbl", "mvex":
+		return p.walk(start, end, depth+1)
+	case "ftyp", "mvhd", "mehd", "tkhd", "mdhd", "hdlr", "stsd":
+		data, err := p.read(start, end)
+		if err != nil {
+			return err
+		}
+		p.leaf(typ, data)
+	}
+	return nil
+}
+
+func (p *parser) read(start, end int64) ([]byte, error) {
+	if end-start > maxLeafBoxSize {
+		return nil, errors.New("视频文件已损坏：元数据过大")
+	}
+	data := make([]byte, end-start)
+	if _, err := p.r.ReadAt(data, start); err != nil {
+		return nil, errors.Wrap(err, "读取视频文件失败")
+	}
+	return data, nil
+}
+
+// leaf 解析元数据 box，数据不完整时忽略该 box
+func (p *parser) leaf(typ string, data []byte) {
+	b := reader{data: data}
+
+	switch typ {
+	case "ftyp":
+		brand := b.fourcc()
+		p.info.Brand = strings.TrimSpace(brand)
+		if brand == "qt  " {
+			p.info.Container = "mov"
+		}
+
+	case "mvhd":
+		if b.u8() == 1 {
+			b.skip(3 + 16)
+			p.timescale = b.u32()
+			p.duration = b.u64()
+		} else {
+			b.skip(3 + 8)
+			p.timescale = b.u32()
+			p.duration = uint64(b.u32())
+		}
+
+	case "mehd":
+		if b.u8() == 1 {
+			b.skip(3)
+			p.fragDuration = b.u64()
+		} else {
+			b.skip(3)
+			p.fragDuration = uint64(b.u32())
+		}
+
+	case "tkhd":
+		if p.cur == nil {
+			return
+		}
+		if b.u8() == 1 {
+			b.skip(3 + 32)
+		} else {
+			b.skip(3 + 20)
+		}
+		b.skip(8 + 2 + 2 + 2 + 2)
+		var matrix [9]int32
+		for i := range matrix {
+			matrix[i] = int32(b.u32())
+		}
+		// 宽高为 16.16 定点数
+		width, height := int(b.u32()>>16), int(b.u32()>>16)
+		if b.err {
+			return
+		}
+		p.cur.width, p.cur.height = width, height
+		p.cur.rotation = rotation(matrix)
+
+	case "mdhd":
+		if p.cur == nil {
+			return
+		}
+		if b.u8() == 1 {
+			b.skip(3 + 16)
+			p.cur.timescale = b.u32()
+			p.cur.duration = b.u64()
+		} else {
+			b.skip(3 + 8)
+			p.cur.timescale = b.u32()
+			p.cur.duration = uint64(b.u32())
+		}
+
+	case "hdlr":
+		if p.cur == nil {
+			return
+		}
+		b.skip(4 + 4)
+		p.cur.handler = b.fourcc()
+
+	case "stsd":
+		if p.cur == nil {
+			return
+		}
+		b.skip(4)
+		if b.u32() == 0 {
+			return
+		}
+		// 第一个 sample entry 的类型即编码格式
+		b.skip(4)
+		p.cur.codec = b.fourcc()
+	}
+}
+
+// finish 汇总各轨道的信息
+func (p *parser) finish() {
+	info := p.info
+
+	for _, t := range p.tracks {
+		switch t.handler {
+		case "vide":
+			if info.VideoCodec != "" {
+				continue
+			}
+			info.VideoCodec = codecName(t.codec)
+			info.Rotation = t.rotation
+			info.Width, info.Height = t.width, t.height
+			if t.rotation == 90 || t.rotation == 270 {
+				info.Width, info.Height = t.height, t.width
+			}
+			if info.Duration == 0 && t.timescale > 0 {
+				info.Duration = scaleDuration(t.duration, t.timescale)
+			}
+		case "soun":
+			if info.AudioCodec == "" {
+				info.AudioCodec = codecName(t.codec)
+			}
+		}
+	}
+
+	// 容器时长优先，分片 MP4 的 mvhd 时长可能为 0
+	if p.timescale > 0 {
+		if d := scaleDuration(p.duration, p.timescale); d > 0 {
+			info.Duration = d
+		} else if d := scaleDuration(p.fragDuration, p.timescale); d > 0 {
+			info.Duration = d
+		}
+	}
+
+	if info.Duration > 0 {
+		info.Bitrate = int64(float64(info.Size*8) / info.Duration.Seconds())
+	}
+}
+
+// isKnownTopLevel 判断文件开头的 box 类型，用于识别非 MP4/MOV 文件
+func isKnownTopLevel(typ string) bool {
+	switch typ {
+	case "ftyp", "moov", "mdat", "free", "skip", "wide", "pnot", "uuid", "styp":
+		return true
+	}
+	return false
+}
+
+// rotation 根据 tkhd 变换矩阵计算旋转角度
+func rotation(m [9]int32) int {
+	const one = 1 << 16
+	a, b, c, d := m[0], m[1], m[3], m[4]
+	switch {
+	case a == 0 && b == one && c == -one && d == 0:
+		return 90
+	case a == -one && b == 0 && c == 0 && d == -one:
+		return 180
+	case a == 0 && b == -one && c == one && d == 0:
+		return 270
+	}
+	return 0
+}
+
+func scaleDuration(duration uint64, timescale uint32) time.Duration {
+	if timescale == 0 || duration == 0 || duration == 1<<32-1 || duration == 1<<64-1 {
+		return 0
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+}
+
+// codecName 将 sample entry 类型转换为通用的编码名称
+func codecName(fourcc string) string {
+	switch fourcc {
+	case "avc1", "avc2", "avc3", "avc4":
+		return "h264"
+	case "hvc1", "hev1":
+		return "h265"
+	case "av01":
+		return "av1"
+	case "vp08":
+		return "vp8"
+	case "vp09":
+		return "vp9"
+	case "mp4v":
+		return "mpeg4"
+	case "apch", "apcn", "apcs", "apco", "ap4h", "ap4x":
+		return "prores"
+	case "mp4a":
+		return "aac"
+	case ".mp3":
+		return "mp3"
+	case "ac-3":
+		return "ac3"
+	case "ec-3":
+		return "eac3"
+	case "Opus":
+		return "opus"
+	case "alac":
+		return "alac"
+	case "lpcm", "sowt", "twos", "in24", "in32", "fl32", "fl64":
+		return "pcm"
+	}
+	return strings.TrimSpace(fourcc)
+}
+
+// reader 顺序读取大端序数据，越界时置 err 并返回零值
+type reader struct {
+	data []byte
+	off  int
+	err  bool
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err || r.off+n > len(r.data) {
+		r.err = true
+		return make([]byte, n)
+	}
+	b := r.data[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+func (r *reader) skip(n int)     { r.next(n) }
+func (r *reader) u8() uint8      { return r.next(1)[0] }
+func (r *reader) u32() uint32    { return binary.BigEndian.Uint32(r.next(4)) }
+func (r *reader) u64() uint64    { return binary.BigEndian.Uint64(r.next(8)) }
+func (r *reader) fourcc() string { return string(r.next(4)) }
@@ -0,0 +1,137 @@
+package videoprobe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func box(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	out = append(out, typ...)
+	return append(out, body...)
+}
+
+func u32(v uint32) []byte { return binary.BigEndian.AppendUint32(nil, v) }
+
+func zeros(n int) []byte { return make([]byte, n) }
+
+func mvhd(timescale, duration uint32) []byte {
+	return box("mvhd", zeros(4), zeros(8), u32(timescale), u32(duration), zeros(80))
+}
+
+func tkhd(width, height uint32, matrix [9]int32) []byte {
+	var m []byte
+	for _, v := range matrix {
+		m = append(m, u32(uint32(v))...)
+	}
+	return box("tkhd", zeros(4), zeros(20), zeros(16), m, u32(width<<16), u32(height<<16))
+}
+
+func trak(handler, codec string, width, height uint32, matrix [9]int32) []byte {
+	return box("trak",
+		tkhd(width, height, matrix),
+		box("mdia",
+			box("mdhd", zeros(4), zeros(8), u32(1000), u32(0), zeros(4)),
+			box("hdlr", zeros(4), zeros(4), []byte(handler), zeros(12)),
+			box("minf", box("stbl", box("stsd", zeros(4), u32(1), box(codec, zeros(16)))))),
+	)
+}
+
+var identity = [9]int32{1 << 16, 0, 0, 0, 1 << 16, 0, 0, 0, 1 << 30}
+
+func TestProbeReader(t *testing.T) {
+	moov := box("moov",
+		mvhd(600, 600*30),
+		trak("vide", "avc1", 1920, 1080, identity),
+		trak("soun", "mp4a", 0, 0, identity),
+	)
+	data := bytes.Join([][]byte{
+		box("ftyp", []byte("isom"), zeros(4), []byte("isomavc1")),
+		box("mdat", zeros(1000)),
+		moov,
+	}, nil)
+
+	info, err := ProbeReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Equal(t, "mp4", info.Container)
+	require.Equal(t, "isom", info.Brand)
+	require.Equal(t, 30*time.Second, info.Duration)
+	require.Equal(t, 1920, info.Width)
+	require.Equal(t, 1080, info.Height)
+	require.Equal(t, "h264", info.VideoCodec)
+	require.Equal(t, "aac", info.AudioCodec)
+	require.Equal(t, int64(len(data)*8/30), info.Bitrate)
+}
+
+func TestProbeRotatedMOV(t *testing.T) {
+	rotated := [9]int32{0, 1 << 16, 0, -(1 << 16), 0, 0, 0, 0, 1 << 30}
+	data := bytes.Join([][]byte{
+		box("ftyp", []byte("qt  "), zeros(4), []byte("qt  ")),
+		box("moov", mvhd(1000, 5000), trak("vide", "hvc1", 1920, 1080, rotated)),
+	}, nil)
+
+	info, err := ProbeReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Equal(t, "mov", info.Container)
+	require.Equal(t, 90, info.Rotation)
+	require.Equal(t, 1080, info.Width)
+	require.Equal(t, 1920, info.Height)
+	require.Equal(t, "h265", info.VideoCodec)
+	require.Empty(t, info.AudioCodec)
+}
+
+func TestProbeUnsupported(t *testing.T) {
+	data := []byte("RIFF\x00\x00\x00\x00AVI LIST")
+	_, err := ProbeReader(bytes.NewReader(data), int64(len(data)))
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	// 只有 mdat 没有 moov
+	data = box("mdat", zeros(100))
+	_, err = ProbeReader(bytes.NewReader(data), int64(len(data)))
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestLimitsCheck(t *testing.T) {
+	limits := DefaultLimits()
+
+	ok := &Info{Size: 10 << 20, Duration: 30 * time.Second, Width: 1080, Height: 1920, VideoCodec: "h264", AudioCodec: "aac"}
+	require.Empty(t, limits.Check(ok))
+
+	bad := &Info{Size: 10 << 20, Duration: 2 * time.Hour, Width: 320, Height: 240, VideoCodec: "prores"}
+	rules := map[string]bool{}
+	for _, v := range limits.Check(bad) {
+		rules[v.Rule] = true
+	}
+	require.Equal(t, map[string]bool{RuleDuration: true}, rules)
+
+	// 分辨率和编码只是推荐规格
+	rules = map[string]bool{}
+	for _, v := range RecommendedLimits().Check(bad) {
+		rules[v.Rule] = true
+	}
+	require.Equal(t, map[string]bool{RuleResolution: true, RuleVideoCodec: true}, rules)
+
+	require.Equal(t, []Violation{{Rule: RuleNoVideoTrack, Message: "文件中没有视频轨道"}}, limits.Check(&Info{AudioCodec: "aac"}))
+}
+
+func TestLoadLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video_limits.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"max_duration_seconds": 900, "audio_codecs": ["aac"]}`), 0644))
+
+	limits, err := LoadLimits(path)
+	require.NoError(t, err)
+	require.Equal(t, float64(900), limits.MaxDurationSeconds)
+	require.Equal(t, []string{"aac"}, limits.AudioCodecs)
+	require.Equal(t, DefaultLimits().MaxSizeMB, limits.MaxSizeMB)
+
+	limits, err = LoadLimits(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	require.Equal(t, DefaultLimits(), limits)
+}
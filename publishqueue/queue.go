@@ -0,0 +1,570 @@
+package publishqueue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 任务类型
+const (
+	KindImage = "image"
+	KindVideo = "video"
+)
+
+// 任务状态
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+const (
+	// DefaultAccount 未指定账号时使用的账号名。所有任务共用同一份登录信息和浏览器配置，目前只支持该账号
+	DefaultAccount = "default"
+	// DefaultMaxAttempts 默认最大执行次数（含首次）
+	DefaultMaxAttempts = 3
+
+	// 单次执行的默认超时时间。视频需要上传、等待平台处理（最长 10 分钟）和确认发布结果，耗时远长于图文
+	imageJobTimeout = 10 * time.Minute
+	videoJobTimeout = 30 * time.Minute
+
+	// finishedRetention 已结束任务的保留时长，超过后在写入时清理
+	finishedRetention = 7 * 24 * time.Hour
+)
+
+var (
+	ErrJobNotFound        = errors.New("发布任务不存在")
+	ErrJobNotPending      = errors.New("发布任务不在等待状态")
+	ErrUnsupportedAccount = errors.New("暂不支持多账号，所有任务使用当前登录的账号，account 只能为空或 default")
+)
+
+// permanentError 不应重试的执行错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 标记执行错误不可重试，如已点击发布但未确认结果，重试可能导致重复发布
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// isPermanent 错误是否被标记为不可重试
+func isPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Job 一个待发布任务
+type Job struct {
+	ID          string          `json:"id"`
+	Account     string          `json:"account"`
+	Kind        string          `json:"kind"`    // image/video
+	Payload     json.RawMessage `json:"payload"` // 发布请求，按 Kind 解析
+	RunAt       time.Time       `json:"run_at"`  // 计划（或下一次重试）执行时间
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// finished 任务是否已结束
+func (j *Job) finished() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed || j.Status == StatusCanceled
+}
+
+// Executor 执行一个发布任务，返回的结果会序列化后记录到任务中
+type Executor func(ctx context.Context, job Job) (any, error)
+
+// Options 队列运行参数，零值使用默认值
+type Options struct {
+	PollInterval   time.Duration // 检查到期任务的间隔
+	RetryBaseDelay time.Duration // 第一次重试的等待时间，之后每次翻倍
+	RetryMaxDelay  time.Duration // 重试等待时间上限
+	JobTimeout     time.Duration // 单次执行超时时间，为零时按任务类型选择
+}
+
+func (o Options) withDefaults() Options {
+	if o.PollInterval <= 0 {
+		o.PollInterval = 10 * time.Second
+	}
+	if o.RetryBaseDelay <= 0 {
+		o.RetryBaseDelay = 1 * time.Minute
+	}
+	if o.RetryMaxDelay <= 0 {
+		o.RetryMaxDelay = 30 * time.Minute
+	}
+	return o
+}
+
+// jobTimeout 单次执行的超时时间
+func (o Options) jobTimeout(kind string) time.Duration {
+	if o.JobTimeout > 0 {
+		return o.JobTimeout
+	}
+	if kind == KindVideo {
+		return videoJobTimeout
+	}
+	return imageJobTimeout
+}
+
+// Queue 持久化的发布队列。任务按计划时间依次执行，同一时间只执行一个任务，失败后按指数退避重试。
+type Queue struct {
+	path string
+	exec Executor
+	opts Options
+
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	busy    bool // 是否有任务正在执行，所有任务共用同一个账号和浏览器配置
+	running bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wake   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewQueue 创建发布队列，并从 path 加载已有任务。
+// 上次退出时仍在执行中的任务会被重新置为等待状态。
+func NewQueue(path string, exec Executor, opts Options) *Queue {
+	q := &Queue{
+		path: path,
+		exec: exec,
+		opts: opts.withDefaults(),
+		jobs: make(map[string]*Job),
+		wake: make(chan struct{}, 1),
+	}
+
+	if err := q.load(); err != nil {
+		logrus.Warnf("failed to load publish queue: %v", err)
+	}
+
+	return q
+}
+
+// Start 启动后台调度
+func (q *Queue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.running {
+		return
+	}
+	q.running = true
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+
+	logrus.Infof("启动发布队列，共 %d 个任务", len(q.jobs))
+
+	q.wg.Add(1)
+	go q.loop(q.ctx)
+}
+
+// Stop 停止后台调度，并等待执行中的任务退出。
+// 被中断的任务不计入执行次数，下次启动后继续执行。
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	if !q.running {
+		q.mu.Unlock()
+		return
+	}
+	q.running = false
+	q.cancel()
+	q.mu.Unlock()
+
+	q.wg.Wait()
+	logrus.Info("发布队列已停止")
+}
+
+// Enqueue 添加发布任务。runAt 为零值时尽快执行，maxAttempts <= 0 时使用默认值。
+func (q *Queue) Enqueue(account, kind string, payload any, runAt time.Time, maxAttempts int) (Job, error) {
+	if kind != KindImage && kind != KindVideo {
+		return Job{}, errors.Errorf("不支持的任务类型: %s", kind)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Job{}, errors.Wrap(err, "序列化发布请求失败")
+	}
+
+	if account == "" {
+		account = DefaultAccount
+	}
+	if account != DefaultAccount {
+		return Job{}, errors.Wrapf(ErrUnsupportedAccount, "account=%s", account)
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	now := time.Now()
+	if runAt.IsZero() {
+		runAt = now
+	}
+
+	job := &Job{
+		ID:          newJobID(),
+		Account:     account,
+		Kind:        kind,
+		Payload:     data,
+		RunAt:       runAt,
+		Status:      StatusPending,
+		MaxAttempts: maxAttempts,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	q.mu.Lock()
+	q.jobs[job.ID] = job
+	created := *job
+	err = q.save()
+	q.mu.Unlock()
+
+	if err != nil {
+		return Job{}, errors.Wrap(err, "保存发布队列失败")
+	}
+
+	logrus.Infof("发布任务已加入队列: id=%s account=%s kind=%s run_at=%s", created.ID, account, kind, runAt.Format(time.RFC3339))
+	q.notify()
+
+	return created, nil
+}
+
+// List 按计划时间列出任务，status 为空时返回全部
+func (q *Queue) List(status string) []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := make([]Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		if status != "" && job.Status != status {
+			continue
+		}
+		jobs = append(jobs, *job)
+	}
+
+	sortJobs(jobs)
+	return jobs
+}
+
+// Get 获取任务
+func (q *Queue) Get(id string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return *job, nil
+}
+
+// Reschedule 修改任务的执行时间。已失败的任务会重置执行次数并重新进入等待状态。
+func (q *Queue) Reschedule(id string, runAt time.Time) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+
+	switch job.Status {
+	case StatusPending:
+	case StatusFailed:
+		job.Status = StatusPending
+		job.Attempts = 0
+	default:
+		return *job, errors.Wrapf(ErrJobNotPending, "任务 %s 当前状态为 %s", id, job.Status)
+	}
+
+	if runAt.IsZero() {
+		runAt = time.Now()
+	}
+	job.RunAt = runAt
+	job.UpdatedAt = time.Now()
+
+	if err := q.save(); err != nil {
+		return *job, errors.Wrap(err, "保存发布队列失败")
+	}
+
+	q.notify()
+	return *job, nil
+}
+
+// Cancel 取消等待中的任务
+func (q *Queue) Cancel(id string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	if job.Status != StatusPending {
+		return *job, errors.Wrapf(ErrJobNotPending, "任务 %s 当前状态为 %s", id, job.Status)
+	}
+
+	job.Status = StatusCanceled
+	job.UpdatedAt = time.Now()
+
+	if err := q.save(); err != nil {
+		return *job, errors.Wrap(err, "保存发布队列失败")
+	}
+
+	return *job, nil
+}
+
+// notify 唤醒调度循环，立即检查到期任务
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) loop(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		q.dispatch(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-q.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dispatch 没有任务在执行时，启动最早到期的任务
+func (q *Queue) dispatch(ctx context.Context) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.running || q.busy {
+		return
+	}
+
+	now := time.Now()
+	var due []Job
+	for _, job := range q.jobs {
+		if job.Status == StatusPending && !job.RunAt.After(now) {
+			due = append(due, *job)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+	sortJobs(due)
+
+	job := q.jobs[due[0].ID]
+	job.Status = StatusRunning
+	job.Attempts++
+	job.UpdatedAt = now
+	q.busy = true
+
+	if err := q.save(); err != nil {
+		logrus.Warnf("failed to save publish queue: %v", err)
+	}
+
+	q.wg.Add(1)
+	go q.run(ctx, *job)
+}
+
+// run 执行一次任务并记录结果
+func (q *Queue) run(ctx context.Context, job Job) {
+	defer q.wg.Done()
+
+	logrus.Infof("开始执行发布任务: id=%s account=%s 第 %d/%d 次", job.ID, job.Account, job.Attempts, job.MaxAttempts)
+
+	execCtx, cancel := context.WithTimeout(ctx, q.opts.jobTimeout(job.Kind))
+	result, err := q.safeExec(execCtx, job)
+	cancel()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.busy = false
+
+	current, ok := q.jobs[job.ID]
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	current.UpdatedAt = now
+
+	switch {
+	case err == nil:
+		current.Status = StatusSucceeded
+		current.LastError = ""
+		if data, mErr := json.Marshal(result); mErr == nil {
+			current.Result = data
+		}
+		logrus.Infof("发布任务执行成功: id=%s", job.ID)
+
+	case isPermanent(err):
+		current.Status = StatusFailed
+		current.LastError = err.Error()
+		logrus.Errorf("发布任务执行失败，不再重试: id=%s %v", job.ID, err)
+
+	case ctx.Err() != nil:
+		// 队列停止导致中断，不计入执行次数
+		current.Status = StatusPending
+		current.Attempts--
+		current.LastError = err.Error()
+		logrus.Warnf("发布任务被中断，将在下次启动后继续: id=%s", job.ID)
+
+	case current.Attempts >= current.MaxAttempts:
+		current.Status = StatusFailed
+		current.LastError = err.Error()
+		logrus.Errorf("发布任务执行失败，已达到最大次数: id=%s %v", job.ID, err)
+
+	default:
+		delay := retryDelay(current.Attempts, q.opts.RetryBaseDelay, q.opts.RetryMaxDelay)
+		current.Status = StatusPending
+		current.RunAt = now.Add(delay)
+		current.LastError = err.Error()
+		logrus.Warnf("发布任务执行失败，%s 后重试: id=%s %v", delay, job.ID, err)
+	}
+
+	if err := q.save(); err != nil {
+		logrus.Warnf("failed to save publish queue: %v", err)
+	}
+
+	q.notify()
+}
+
+// safeExec 执行任务，防止 panic 导致队列退出
+func (q *Queue) safeExec(ctx context.Context, job Job) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("发布任务 panic: %v", r)
+		}
+	}()
+	return q.exec(ctx, job)
+}
+
+// retryDelay 第 attempt 次失败后的重试等待时间：base * 2^(attempt-1)，不超过 max
+func retryDelay(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+func sortJobs(jobs []Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].RunAt.Equal(jobs[j].RunAt) {
+			return jobs[i].RunAt.Before(jobs[j].RunAt)
+		}
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+}
+
+func (q *Queue) load() error {
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to read publish queue file")
+	}
+
+	var jobs []*Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return errors.Wrap(err, "failed to unmarshal publish queue")
+	}
+
+	for _, job := range jobs {
+		if job.Status == StatusRunning {
+			// 上次退出时正在执行，重新执行（可通过 last_error 和 attempts 查看执行情况）
+			job.Status = StatusPending
+			logrus.Warnf("发布任务 %s 上次未执行完成，重新加入等待队列", job.ID)
+		}
+		q.jobs[job.ID] = job
+	}
+
+	return nil
+}
+
+// save 写入文件，调用方需持有锁。过期的已结束任务在写入时清理。
+func (q *Queue) save() error {
+	jobs := make([]Job, 0, len(q.jobs))
+	for id, job := range q.jobs {
+		if job.finished() && time.Since(job.UpdatedAt) > finishedRetention {
+			delete(q.jobs, id)
+			continue
+		}
+		jobs = append(jobs, *job)
+	}
+	sortJobs(jobs)
+
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(q.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	// 先写临时文件再重命名，避免写入中断导致队列文件损坏
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.path)
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
+
+// GetStoreFilePath 获取发布队列文件路径。
+// 优先使用环境变量 PUBLISH_QUEUE_PATH，否则使用当前目录下的 publish_queue.json
+func GetStoreFilePath() string {
+	path := os.Getenv("PUBLISH_QUEUE_PATH")
+	if path == "" {
+		path = "publish_queue.json"
+	}
+	return path
+}
@@ -0,0 +1,192 @@
+package publishqueue
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var testOptions = Options{
+	PollInterval:   10 * time.Millisecond,
+	RetryBaseDelay: 10 * time.Millisecond,
+	RetryMaxDelay:  50 * time.Millisecond,
+	JobTimeout:     time.Second,
+}
+
+func waitForStatus(t *testing.T, q *Queue, id, status string) Job {
+	t.Helper()
+
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = q.Get(id)
+		return err == nil && job.Status == status
+	}, 2*time.Second, 5*time.Millisecond)
+
+	return job
+}
+
+func TestQueuePersistAndManage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "publish_queue.json")
+	q := NewQueue(path, nil, testOptions)
+
+	later := time.Now().Add(time.Hour)
+	job, err := q.Enqueue("", KindImage, map[string]string{"title": "t"}, later, 0)
+	require.NoError(t, err)
+	require.Equal(t, DefaultAccount, job.Account)
+	require.Equal(t, DefaultMaxAttempts, job.MaxAttempts)
+
+	_, err = q.Enqueue("", "audio", nil, later, 0)
+	require.Error(t, err)
+
+	_, err = q.Enqueue("other", KindImage, nil, later, 0)
+	require.ErrorIs(t, err, ErrUnsupportedAccount)
+
+	// 重新加载后任务仍在
+	reloaded := NewQueue(path, nil, testOptions)
+	jobs := reloaded.List(StatusPending)
+	require.Len(t, jobs, 1)
+	require.Equal(t, job.ID, jobs[0].ID)
+	require.JSONEq(t, `{"title":"t"}`, string(jobs[0].Payload))
+
+	newTime := later.Add(time.Hour)
+	rescheduled, err := reloaded.Reschedule(job.ID, newTime)
+	require.NoError(t, err)
+	require.True(t, newTime.Equal(rescheduled.RunAt))
+
+	_, err = reloaded.Cancel(job.ID)
+	require.NoError(t, err)
+
+	_, err = reloaded.Cancel(job.ID)
+	require.ErrorIs(t, err, ErrJobNotPending)
+
+	_, err = reloaded.Reschedule("missing", newTime)
+	require.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestQueueRetryUntilSuccess(t *testing.T) {
+	var calls int
+	exec := func(ctx context.Context, job Job) (any, error) {
+		calls++
+		if calls < 2 {
+			return nil, errors.New("发布失败")
+		}
+		return map[string]string{"status": "ok"}, nil
+	}
+
+	q := NewQueue(filepath.Join(t.TempDir(), "publish_queue.json"), exec, testOptions)
+	q.Start()
+	defer q.Stop()
+
+	job, err := q.Enqueue("", KindImage, map[string]string{}, time.Time{}, 3)
+	require.NoError(t, err)
+
+	done := waitForStatus(t, q, job.ID, StatusSucceeded)
+	require.Equal(t, 2, done.Attempts)
+	require.Empty(t, done.LastError)
+	require.JSONEq(t, `{"status":"ok"}`, string(done.Result))
+}
+
+func TestQueueFailAfterMaxAttempts(t *testing.T) {
+	exec := func(ctx context.Context, job Job) (any, error) {
+		return nil, errors.New("发布失败")
+	}
+
+	q := NewQueue(filepath.Join(t.TempDir(), "publish_queue.json"), exec, testOptions)
+	q.Start()
+	defer q.Stop()
+
+	job, err := q.Enqueue("", KindVideo, map[string]string{}, time.Time{}, 2)
+	require.NoError(t, err)
+
+	failed := waitForStatus(t, q, job.ID, StatusFailed)
+	require.Equal(t, 2, failed.Attempts)
+	require.Equal(t, "发布失败", failed.LastError)
+}
+
+func TestQueuePermanentErrorNotRetried(t *testing.T) {
+	var calls int
+	exec := func(ctx context.Context, job Job) (any, error) {
+		calls++
+		return nil, Permanent(errors.New("发布结果未确认"))
+	}
+
+	q := NewQueue(filepath.Join(t.TempDir(), "publish_queue.json"), exec, testOptions)
+	q.Start()
+	defer q.Stop()
+
+	job, err := q.Enqueue("", KindImage, map[string]string{}, time.Time{}, 3)
+	require.NoError(t, err)
+
+	failed := waitForStatus(t, q, job.ID, StatusFailed)
+	require.Equal(t, 1, failed.Attempts)
+	require.Equal(t, 1, calls)
+	require.Equal(t, "发布结果未确认", failed.LastError)
+}
+
+func TestQueueRunsOneJobAtATime(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		active  int
+		overlap bool
+	)
+	exec := func(ctx context.Context, job Job) (any, error) {
+		mu.Lock()
+		active++
+		if active > 1 {
+			overlap = true
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return nil, nil
+	}
+
+	q := NewQueue(filepath.Join(t.TempDir(), "publish_queue.json"), exec, testOptions)
+
+	var ids []string
+	for _, kind := range []string{KindImage, KindImage, KindVideo, KindImage, KindVideo} {
+		job, err := q.Enqueue("", kind, map[string]string{}, time.Time{}, 1)
+		require.NoError(t, err)
+		ids = append(ids, job.ID)
+	}
+
+	q.Start()
+	defer q.Stop()
+
+	for _, id := range ids {
+		waitForStatus(t, q, id, StatusSucceeded)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.False(t, overlap, "任务共用同一个账号和浏览器，不应并发执行")
+}
+
+func TestJobTimeout(t *testing.T) {
+	var opts Options
+	require.Equal(t, imageJobTimeout, opts.jobTimeout(KindImage))
+	require.Equal(t, videoJobTimeout, opts.jobTimeout(KindVideo))
+
+	opts.JobTimeout = time.Minute
+	require.Equal(t, time.Minute, opts.jobTimeout(KindVideo))
+}
+
+func TestRetryDelay(t *testing.T) {
+	base, max := time.Minute, 5*time.Minute
+
+	require.Equal(t, time.Minute, retryDelay(1, base, max))
+	require.Equal(t, 2*time.Minute, retryDelay(2, base, max))
+	require.Equal(t, 4*time.Minute, retryDelay(3, base, max))
+	require.Equal(t, max, retryDelay(4, base, max))
+	require.Equal(t, max, retryDelay(10, base, max))
+}
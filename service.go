@@ -3,8 +3,12 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
@@ -14,24 +18,64 @@ import (
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/idempotency"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/contentlint"
 	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/imagekit"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/mdnote"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/textcard"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/videoprobe"
+	"github.com/xpzouying/xiaohongshu-mcp/publishqueue"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+	"github.com/xpzouying/xiaohongshu-mcp/xsectoken"
 )
 
 // XiaohongshuService 小红书业务服务
-type XiaohongshuService struct{}
+type XiaohongshuService struct {
+	tokens      *xsectoken.Store    // 记录响应中出现过的 xsec_token，调用方可只传 ID
+	linter      *contentlint.Linter // 发布前的内容校验
+	videoLimits videoprobe.Limits   // 发布前的视频校验
+	history     *idempotency.Store  // 发布记录，用于幂等和重复发布检测
+}
 
 // NewXiaohongshuService 创建小红书服务实例
 func NewXiaohongshuService() *XiaohongshuService {
-	return &XiaohongshuService{}
+	rules, err := contentlint.LoadRules(contentlint.GetRulesFilePath())
+	if err != nil {
+		logrus.Warnf("加载内容校验规则失败，使用内置规则: %v", err)
+	}
+	videoLimits, err := videoprobe.LoadLimits(videoprobe.GetLimitsFilePath())
+	if err != nil {
+		logrus.Warnf("加载视频限制配置失败，使用默认限制: %v", err)
+	}
+
+	return &XiaohongshuService{
+		tokens:      xsectoken.NewStore(xsectoken.GetStoreFilePath(), xsectoken.DefaultMaxAge),
+		linter:      contentlint.New(rules),
+		videoLimits: videoLimits,
+		history:     idempotency.NewStore(idempotency.GetStoreFilePath(), idempotency.DefaultWindow),
+	}
 }
 
 // PublishRequest 发布请求
 type PublishRequest struct {
-	Title   string   `json:"title" binding:"required"`
-	Content string   `json:"content" binding:"required"`
-	Images  []string `json:"images" binding:"required,min=1"`
-	Tags    []string `json:"tags,omitempty"`
+	Title       string   `json:"title" binding:"required"`
+	Content     string   `json:"content" binding:"required"`
+	Images      []string `json:"images" binding:"required,min=1"`
+	Tags        []string `json:"tags,omitempty"`
+	PublishAt   string   `json:"publish_at,omitempty"`  // 定时发布时间，为空则立即发布
+	Draft       bool     `json:"draft,omitempty"`       // 仅保存到草稿箱，人工审核后再发布
+	Visibility  string   `json:"visibility,omitempty"`  // 可见范围：公开|仅自己可见|仅互关好友可见，为空则公开
+	Original    bool     `json:"original,omitempty"`    // 是否声明原创
+	Declaration string   `json:"declaration,omitempty"` // 内容类型声明，AI 生成的内容必须声明“笔记含AI合成内容”
+	Mentions    []string `json:"mentions,omitempty"`    // 需要 @ 的用户昵称或小红书号
+	Location    string   `json:"location,omitempty"`    // 地点名称，通过“添加地点”搜索选择
+	EmojiCodes  bool     `json:"emoji_codes,omitempty"` // 正文中的 emoji 转为小红书表情代码，如 😂 → [笑哭R]
+
+	IdempotencyKey string                       `json:"idempotency_key,omitempty"` // 幂等键，相同键的重复请求返回首次结果
+	ImageOptions   downloader.PreprocessOptions `json:"image_options,omitempty"`   // 图片预处理选项：宽高比、尺寸、格式和大小上限
+	Watermark      *imagekit.WatermarkOptions   `json:"watermark,omitempty"`       // 为每张图片添加水印，为空不添加
+	CoverCollage   *imagekit.CollageOptions     `json:"cover_collage,omitempty"`   // 用前几张图片拼成封面，插入为第一张图片
 }
 
 // LoginStatusResponse 登录状态响应
@@ -49,28 +93,54 @@ type LoginQrcodeResponse struct {
 
 // PublishResponse 发布响应
 type PublishResponse struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Images  int    `json:"images"`
-	Status  string `json:"status"`
-	PostID  string `json:"post_id,omitempty"`
+	Title              string   `json:"title"`
+	Content            string   `json:"content"`
+	Images             int      `json:"images"`
+	Status             string   `json:"status"`
+	PostID             string   `json:"post_id,omitempty"`             // 新笔记 ID
+	PostURL            string   `json:"post_url,omitempty"`            // 新笔记的分享链接
+	XsecToken          string   `json:"xsec_token,omitempty"`          // 新笔记的 xsec_token
+	ScheduledAt        string   `json:"scheduled_at,omitempty"`        // 平台确认的定时发布时间
+	Location           string   `json:"location,omitempty"`            // 已添加的地点
+	UnresolvedMentions []string `json:"unresolved_mentions,omitempty"` // 没有找到的 @ 用户
+	UnresolvedLocation string   `json:"unresolved_location,omitempty"` // 没有找到的地点
+	Duplicate          bool     `json:"duplicate,omitempty"`           // 重复请求，返回的是首次发布的结果
 }
 
-// PublishVideoRequest 发布视频请求（仅支持本地单个视频文件）
+// PublishVideoRequest 发布视频请求（单个视频文件，本地路径或链接）
 type PublishVideoRequest struct {
-	Title   string   `json:"title" binding:"required"`
-	Content string   `json:"content" binding:"required"`
-	Video   string   `json:"video" binding:"required"`
-	Tags    []string `json:"tags,omitempty"`
+	Title       string   `json:"title" binding:"required"`
+	Content     string   `json:"content" binding:"required"`
+	Video       string   `json:"video" binding:"required"`
+	Tags        []string `json:"tags,omitempty"`
+	PublishAt   string   `json:"publish_at,omitempty"`  // 定时发布时间，为空则立即发布
+	Draft       bool     `json:"draft,omitempty"`       // 仅保存到草稿箱，人工审核后再发布
+	Visibility  string   `json:"visibility,omitempty"`  // 可见范围：公开|仅自己可见|仅互关好友可见，为空则公开
+	Original    bool     `json:"original,omitempty"`    // 是否声明原创
+	Declaration string   `json:"declaration,omitempty"` // 内容类型声明，AI 生成的内容必须声明“笔记含AI合成内容”
+	Mentions    []string `json:"mentions,omitempty"`    // 需要 @ 的用户昵称或小红书号
+	Location    string   `json:"location,omitempty"`    // 地点名称，通过“添加地点”搜索选择
+	EmojiCodes  bool     `json:"emoji_codes,omitempty"` // 正文中的 emoji 转为小红书表情代码，如 😂 → [笑哭R]
+	CoverTime   string   `json:"cover_time,omitempty"`  // 封面截取时间，如 3.5、01:20，与 cover_image 二选一
+	CoverImage  string   `json:"cover_image,omitempty"` // 封面图片，本地路径或 HTTP/HTTPS 链接
+
+	IdempotencyKey string `json:"idempotency_key,omitempty"` // 幂等键，相同键的重复请求返回首次结果
 }
 
 // PublishVideoResponse 发布视频响应
 type PublishVideoResponse struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Video   string `json:"video"`
-	Status  string `json:"status"`
-	PostID  string `json:"post_id,omitempty"`
+	Title              string   `json:"title"`
+	Content            string   `json:"content"`
+	Video              string   `json:"video"`
+	Status             string   `json:"status"`
+	PostID             string   `json:"post_id,omitempty"`             // 新笔记 ID
+	PostURL            string   `json:"post_url,omitempty"`            // 新笔记的分享链接
+	XsecToken          string   `json:"xsec_token,omitempty"`          // 新笔记的 xsec_token
+	ScheduledAt        string   `json:"scheduled_at,omitempty"`        // 平台确认的定时发布时间
+	Location           string   `json:"location,omitempty"`            // 已添加的地点
+	UnresolvedMentions []string `json:"unresolved_mentions,omitempty"` // 没有找到的 @ 用户
+	UnresolvedLocation string   `json:"unresolved_location,omitempty"` // 没有找到的地点
+	Duplicate          bool     `json:"duplicate,omitempty"`           // 重复请求，返回的是首次发布的结果
 }
 
 // FeedsListResponse Feeds列表响应
@@ -86,6 +156,79 @@ type UserProfileResponse struct {
 	Feeds         []xiaohongshu.Feed             `json:"feeds"`
 }
 
+// MyNotesResponse 自己发布的笔记列表响应
+type MyNotesResponse struct {
+	Notes []xiaohongshu.CreatorNote `json:"notes"`
+	Count int                       `json:"count"`
+}
+
+// DraftsResponse 草稿箱列表响应
+type DraftsResponse struct {
+	Drafts []xiaohongshu.Draft `json:"drafts"`
+	Count  int                 `json:"count"`
+}
+
+// PublishTextCardsRequest 文字卡片发布请求，卡片图片由 card_title 和 card_text 渲染
+type PublishTextCardsRequest struct {
+	Title       string   `json:"title" binding:"required"`
+	Content     string   `json:"content" binding:"required"`
+	Tags        []string `json:"tags,omitempty"`
+	PublishAt   string   `json:"publish_at,omitempty"`
+	Draft       bool     `json:"draft,omitempty"`
+	Visibility  string   `json:"visibility,omitempty"`
+	Original    bool     `json:"original,omitempty"`
+	Declaration string   `json:"declaration,omitempty"`
+
+	IdempotencyKey string `json:"idempotency_key,omitempty"` // 幂等键，相同键的重复请求返回首次结果
+
+	CardTitle string `json:"card_title,omitempty"` // 卡片标题，为空时使用笔记标题
+	CardText  string `json:"card_text" binding:"required"`
+	Theme     string `json:"theme,omitempty"`     // 卡片主题
+	Watermark string `json:"watermark,omitempty"` // 卡片水印文字
+	FontPath  string `json:"font_path,omitempty"` // 中文字体文件路径
+}
+
+// PublishTextCardsResponse 文字卡片发布响应
+type PublishTextCardsResponse struct {
+	PublishResponse
+	Cards []string `json:"cards"` // 渲染出的卡片图片路径
+}
+
+// ComposeImagesRequest 图片合成请求
+type ComposeImagesRequest struct {
+	Images    []string                   `json:"images" binding:"required,min=1"`
+	Collage   *imagekit.CollageOptions   `json:"collage,omitempty"`   // 用前几张图片拼成网格图，插入为第一张图片
+	Watermark *imagekit.WatermarkOptions `json:"watermark,omitempty"` // 为每张图片添加水印
+}
+
+// ComposeImagesResponse 图片合成响应
+type ComposeImagesResponse struct {
+	Images []string `json:"images"` // 合成后的本地图片路径，拼图时第一张为拼图
+}
+
+// PublishMarkdownRequest 从 Markdown 文件发布请求
+type PublishMarkdownRequest struct {
+	FilePath       string `json:"file_path" binding:"required"`
+	Draft          bool   `json:"draft,omitempty"`   // 仅保存到草稿箱，与 front matter 中的 draft 任一为 true 即生效
+	DryRun         bool   `json:"dry_run,omitempty"` // 只解析和校验，不发布
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// PublishMarkdownResponse 从 Markdown 文件发布响应
+type PublishMarkdownResponse struct {
+	Note   *mdnote.Note        `json:"note"`             // 解析出的笔记
+	Lint   *contentlint.Result `json:"lint,omitempty"`   // dry_run 时的校验结果
+	Result *PublishResponse    `json:"result,omitempty"` // 发布结果，dry_run 时为空
+}
+
+// EditNoteRequest 编辑笔记请求
+type EditNoteRequest struct {
+	NoteID  string   `json:"note_id" binding:"required"`
+	Title   string   `json:"title,omitempty"`
+	Content string   `json:"content,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
 // CheckLoginStatus 检查登录状态
 func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatusResponse, error) {
 	b := newBrowser()
@@ -159,52 +302,276 @@ func (s *XiaohongshuService) GetLoginQrcode(ctx context.Context) (*LoginQrcodeRe
 
 // PublishContent 发布内容
 func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
-	// 验证标题长度
-	// 小红书限制：最大40个单位长度
-	// 中文/日文/韩文占2个单位，英文/数字占1个单位
-	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
-		return nil, fmt.Errorf("标题长度超过限制")
+	// 表情代码按平台表情显示，需在校验字数前转换
+	if req.EmojiCodes {
+		req.Content = xiaohongshu.EncodeEmoji(req.Content)
 	}
 
+	// 下载图片和启动浏览器前校验
+	settings, err := s.checkPublishRequest(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	scheduleTime, visibility, declaration := settings.ScheduleTime, settings.Visibility, settings.Declaration
+
 	// 处理图片：下载URL图片或使用本地路径
-	imagePaths, err := s.processImages(req.Images)
+	imagePaths, err := s.processImages(req.Images, req.CoverCollage, req.Watermark, req.ImageOptions)
 	if err != nil {
 		return nil, err
 	}
 
 	// 构建发布内容
 	content := xiaohongshu.PublishImageContent{
-		Title:      req.Title,
-		Content:    req.Content,
-		Tags:       req.Tags,
-		ImagePaths: imagePaths,
+		Title:        req.Title,
+		Content:      req.Content,
+		Tags:         req.Tags,
+		ImagePaths:   imagePaths,
+		ScheduleTime: scheduleTime,
+		Draft:        req.Draft,
+		Visibility:   visibility,
+		Original:     req.Original,
+		Declaration:  declaration,
+		Mentions:     req.Mentions,
+		Location:     req.Location,
+	}
+
+	// 窗口内重复的请求直接返回首次的结果
+	fingerprint, err := idempotency.Fingerprint(idempotency.Content{
+		Kind:    publishqueue.KindImage,
+		Title:   req.Title,
+		Content: req.Content,
+		Tags:    req.Tags,
+		Files:   imagePaths,
+		Extra:   append([]string{req.PublishAt, strconv.FormatBool(req.Draft)}, publishSettingsExtra(visibility, req.Original, declaration, req.Mentions, req.Location)...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var previous PublishResponse
+	duplicate, err := s.beginPublish(req.IdempotencyKey, fingerprint, &previous)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		previous.Duplicate = true
+		return &previous, nil
 	}
 
 	// 执行发布
-	if err := s.publishContent(ctx, content); err != nil {
+	result, err := s.runPublish(req.IdempotencyKey, fingerprint, func() (*xiaohongshu.PublishResult, error) {
+		return s.publishContent(ctx, content)
+	})
+	if err != nil {
 		logrus.Errorf("发布内容失败: title=%s %v", content.Title, err)
 		return nil, err
 	}
+	s.recordPublishedToken(result)
 
 	response := &PublishResponse{
-		Title:   req.Title,
-		Content: req.Content,
-		Images:  len(imagePaths),
-		Status:  "发布完成",
+		Title:              req.Title,
+		Content:            req.Content,
+		Images:             len(imagePaths),
+		Status:             publishStatus(result),
+		PostID:             result.NoteID,
+		PostURL:            result.URL,
+		XsecToken:          result.XsecToken,
+		ScheduledAt:        result.ScheduledAt,
+		Location:           result.Location,
+		UnresolvedMentions: result.UnresolvedMentions,
+		UnresolvedLocation: result.UnresolvedLocation,
 	}
+	s.history.Succeed(req.IdempotencyKey, fingerprint, response)
 
 	return response, nil
 }
 
-// processImages 处理图片列表，支持URL下载和本地路径
-func (s *XiaohongshuService) processImages(images []string) ([]string, error) {
+// publishSettingsExtra 可见范围、原创声明等发布设置，计入内容指纹，只修改设置后重新发布不会被当作重复请求
+func publishSettingsExtra(visibility string, original bool, declaration string, mentions []string, location string) []string {
+	trimmed := make([]string, 0, len(mentions))
+	for _, m := range mentions {
+		if m = strings.TrimSpace(m); m != "" {
+			trimmed = append(trimmed, m)
+		}
+	}
+	return []string{visibility, strconv.FormatBool(original), declaration, strings.Join(trimmed, "\n"), strings.TrimSpace(location)}
+}
+
+// beginPublish 开始幂等发布。窗口内已成功发布过相同请求时，将首次的结果解码到 previous 并返回 true
+func (s *XiaohongshuService) beginPublish(key, fingerprint string, previous any) (bool, error) {
+	record, err := s.history.Begin(key, fingerprint)
+	if err != nil {
+		return false, err
+	}
+	if record == nil {
+		return false, nil
+	}
+
+	logrus.Infof("重复的发布请求，返回首次发布结果: key=%s fingerprint=%s", key, fingerprint[:12])
+	if err := json.Unmarshal(record.Result, previous); err != nil {
+		return false, fmt.Errorf("解析首次发布结果失败: %w", err)
+	}
+	return true, nil
+}
+
+// runPublish 执行发布并记录失败。
+// 浏览器操作中的 rod Must* 调用可能 panic，此时无法确定是否已点击发布，按未确认记录，避免发布记录一直停留在发布中
+func (s *XiaohongshuService) runPublish(key, fingerprint string, publish func() (*xiaohongshu.PublishResult, error)) (result *xiaohongshu.PublishResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("发布过程异常中断: %v\n%s", r, debug.Stack())
+			result, err = nil, fmt.Errorf("%w（发布过程异常中断: %v）", xiaohongshu.ErrPublishUnconfirmed, r)
+		}
+		if err != nil {
+			s.failPublish(key, fingerprint, err)
+		}
+	}()
+
+	return publish()
+}
+
+// failPublish 记录发布失败。点击发布后未能确认结果时笔记可能已经发布，记录为未确认，阻止重试时重复发布
+func (s *XiaohongshuService) failPublish(key, fingerprint string, err error) {
+	if errors.Is(err, xiaohongshu.ErrPublishUnconfirmed) {
+		s.history.Unconfirmed(key, fingerprint, err)
+		return
+	}
+	s.history.Fail(key, fingerprint, err)
+}
+
+// LintContent 按平台规则和违禁词表校验待发布的内容
+func (s *XiaohongshuService) LintContent(title, content string, tags []string) *contentlint.Result {
+	return s.linter.Lint(contentlint.Input{Title: title, Content: content, Tags: tags})
+}
+
+// lintBeforePublish 发布前校验，存在错误时拒绝发布，警告只记录日志
+func (s *XiaohongshuService) lintBeforePublish(title, content string, tags []string) error {
+	result := s.LintContent(title, content, tags)
+	for _, w := range result.Warnings {
+		logrus.Warnf("内容校验警告: %s %s", w.Field, w.Message)
+	}
+	return result.Err()
+}
+
+// checkVideo 解析视频容器，上传前检查时长和大小是否符合平台限制，分辨率和编码不符合推荐规格时只记录警告
+func (s *XiaohongshuService) checkVideo(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("视频文件不存在或不可访问: %v", err)
+	}
+
+	info, warnings, err := s.videoLimits.Validate(path)
+	if errors.Is(err, videoprobe.ErrUnsupportedFormat) {
+		// flv/mkv 等平台支持但无法解析的格式直接上传，由平台校验
+		logrus.Warnf("无法读取视频信息，跳过上传前检查: %s", path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		logrus.Warnf("视频不符合平台推荐规格: %s", w.Message)
+	}
+	logrus.Infof("视频信息: %s %dx%d %s %s/%s %dkbps",
+		info.Container, info.Width, info.Height, info.Duration, info.VideoCodec, info.AudioCodec, info.Bitrate/1000)
+	return nil
+}
+
+// processImages 处理图片列表，支持URL下载和本地路径，按需拼接封面和添加水印，并在上传前统一预处理
+func (s *XiaohongshuService) processImages(images []string, collage *imagekit.CollageOptions, watermark *imagekit.WatermarkOptions, opts downloader.PreprocessOptions) ([]string, error) {
 	processor := downloader.NewImageProcessor()
-	return processor.ProcessImages(images)
+	paths, err := processor.ProcessImages(images)
+	if err != nil {
+		return nil, err
+	}
+	paths, err = composeImages(processor, paths, collage, watermark)
+	if err != nil {
+		return nil, err
+	}
+	return processor.PreprocessImages(paths, opts)
+}
+
+// ComposeImages 下载图片后按需拼接封面和添加水印，返回本地图片路径，处理方式与发布前相同
+func (s *XiaohongshuService) ComposeImages(ctx context.Context, req *ComposeImagesRequest) (*ComposeImagesResponse, error) {
+	if req.Collage == nil && req.Watermark == nil {
+		return nil, errors.New("拼图和水印至少需要设置一项")
+	}
+
+	processor := downloader.NewImageProcessor()
+	paths, err := processor.ProcessImages(req.Images)
+	if err != nil {
+		return nil, err
+	}
+	paths, err = composeImages(processor, paths, req.Collage, req.Watermark)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ComposeImagesResponse{Images: paths}, nil
+}
+
+// composeImages 用前几张图片拼接封面并插入为第一张，再为所有图片添加水印。水印 Logo 支持链接
+func composeImages(processor *downloader.ImageProcessor, paths []string, collage *imagekit.CollageOptions, watermark *imagekit.WatermarkOptions) ([]string, error) {
+	if collage != nil {
+		cover, err := imagekit.Collage(paths[:min(len(paths), imagekit.MaxCollageImages)], *collage)
+		if err != nil {
+			return nil, fmt.Errorf("拼接封面失败: %w", err)
+		}
+		paths = append([]string{cover}, paths...)
+	}
+
+	if watermark == nil {
+		return paths, nil
+	}
+
+	opts := *watermark
+	if opts.Logo != "" {
+		logo, err := processor.ProcessImage(opts.Logo)
+		if err != nil {
+			return nil, fmt.Errorf("处理水印 Logo 失败: %w", err)
+		}
+		opts.Logo = logo
+	}
+
+	marked := make([]string, 0, len(paths))
+	for i, path := range paths {
+		output, err := imagekit.Watermark(path, opts)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 张图片添加水印失败: %w", i+1, err)
+		}
+		marked = append(marked, output)
+	}
+	return marked, nil
+}
+
+// processVideoCover 处理视频封面参数，封面图片为 URL 时自动下载
+func (s *XiaohongshuService) processVideoCover(coverTime, coverImage string) (*xiaohongshu.VideoCover, error) {
+	if coverTime == "" && coverImage == "" {
+		return nil, nil
+	}
+	if coverTime != "" && coverImage != "" {
+		return nil, fmt.Errorf("cover_time 和 cover_image 只能提供一个")
+	}
+
+	if coverTime != "" {
+		frame, err := xiaohongshu.ParseCoverTime(coverTime)
+		if err != nil {
+			return nil, err
+		}
+		return &xiaohongshu.VideoCover{Frame: &frame}, nil
+	}
+
+	path, err := downloader.NewImageProcessor().ProcessImage(coverImage)
+	if err != nil {
+		return nil, fmt.Errorf("处理封面图片失败: %w", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("封面图片不存在或不可访问: %v", err)
+	}
+	return &xiaohongshu.VideoCover{ImagePath: path}, nil
 }
 
 // publishContent 执行内容发布
-func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) error {
-	b := newBrowser()
+func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) (*xiaohongshu.PublishResult, error) {
+	b := newPublishBrowser(content.Draft)
 	defer b.Close()
 
 	page := b.NewPage()
@@ -212,53 +579,144 @@ func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohon
 
 	action, err := xiaohongshu.NewPublishImageAction(page)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 执行发布
 	return action.Publish(ctx, content)
 }
 
+// ExecutePublishJob 执行发布队列中的任务。点击发布后未确认结果的任务不再自动重试，避免重复发布
+func (s *XiaohongshuService) ExecutePublishJob(ctx context.Context, job publishqueue.Job) (any, error) {
+	result, err := s.executePublishJob(ctx, job)
+	if errors.Is(err, xiaohongshu.ErrPublishUnconfirmed) || errors.Is(err, idempotency.ErrUnconfirmed) {
+		return nil, publishqueue.Permanent(err)
+	}
+	return result, err
+}
+
+// executePublishJob 按任务类型解析并执行发布请求
+func (s *XiaohongshuService) executePublishJob(ctx context.Context, job publishqueue.Job) (any, error) {
+	switch job.Kind {
+	case publishqueue.KindImage:
+		var req PublishRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return nil, fmt.Errorf("解析图文发布请求失败: %w", err)
+		}
+		return s.PublishContent(ctx, &req)
+
+	case publishqueue.KindVideo:
+		var req PublishVideoRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return nil, fmt.Errorf("解析视频发布请求失败: %w", err)
+		}
+		return s.PublishVideo(ctx, &req)
+
+	default:
+		return nil, fmt.Errorf("不支持的发布任务类型: %s", job.Kind)
+	}
+}
+
 // PublishVideo 发布视频（本地文件）
 func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideoRequest) (*PublishVideoResponse, error) {
-	// 标题长度校验
-	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
-		return nil, fmt.Errorf("标题长度超过限制")
+	// 表情代码按平台表情显示，需在校验字数前转换
+	if req.EmojiCodes {
+		req.Content = xiaohongshu.EncodeEmoji(req.Content)
 	}
 
-	// 本地视频文件校验
-	if req.Video == "" {
-		return nil, fmt.Errorf("必须提供本地视频文件")
+	// 下载视频和启动浏览器前校验
+	settings, err := s.checkPublishVideoRequest(req, time.Now())
+	if err != nil {
+		return nil, err
 	}
-	if _, err := os.Stat(req.Video); err != nil {
-		return nil, fmt.Errorf("视频文件不存在或不可访问: %v", err)
+	scheduleTime, visibility, declaration := settings.ScheduleTime, settings.Visibility, settings.Declaration
+
+	// 视频链接先下载到本地，再校验视频文件
+	videoPath, err := downloader.ProcessVideo(ctx, req.Video)
+	if err != nil {
+		return nil, fmt.Errorf("下载视频失败: %w", err)
+	}
+	if err := s.checkVideo(videoPath); err != nil {
+		return nil, err
+	}
+
+	cover, err := s.processVideoCover(req.CoverTime, req.CoverImage)
+	if err != nil {
+		return nil, err
 	}
 
 	// 构建发布内容
 	content := xiaohongshu.PublishVideoContent{
-		Title:     req.Title,
-		Content:   req.Content,
-		Tags:      req.Tags,
-		VideoPath: req.Video,
+		Title:        req.Title,
+		Content:      req.Content,
+		Tags:         req.Tags,
+		VideoPath:    videoPath,
+		ScheduleTime: scheduleTime,
+		Draft:        req.Draft,
+		Visibility:   visibility,
+		Original:     req.Original,
+		Declaration:  declaration,
+		Mentions:     req.Mentions,
+		Location:     req.Location,
+		Cover:        cover,
+	}
+
+	// 窗口内重复的请求直接返回首次的结果
+	files := []string{videoPath}
+	if cover != nil && cover.ImagePath != "" {
+		files = append(files, cover.ImagePath)
+	}
+	fingerprint, err := idempotency.Fingerprint(idempotency.Content{
+		Kind:    publishqueue.KindVideo,
+		Title:   req.Title,
+		Content: req.Content,
+		Tags:    req.Tags,
+		Files:   files,
+		Extra:   append([]string{req.PublishAt, strconv.FormatBool(req.Draft), req.CoverTime}, publishSettingsExtra(visibility, req.Original, declaration, req.Mentions, req.Location)...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var previous PublishVideoResponse
+	duplicate, err := s.beginPublish(req.IdempotencyKey, fingerprint, &previous)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		previous.Duplicate = true
+		return &previous, nil
 	}
 
 	// 执行发布
-	if err := s.publishVideo(ctx, content); err != nil {
+	result, err := s.runPublish(req.IdempotencyKey, fingerprint, func() (*xiaohongshu.PublishResult, error) {
+		return s.publishVideo(ctx, content)
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.recordPublishedToken(result)
 
 	resp := &PublishVideoResponse{
-		Title:   req.Title,
-		Content: req.Content,
-		Video:   req.Video,
-		Status:  "发布完成",
+		Title:              req.Title,
+		Content:            req.Content,
+		Video:              req.Video,
+		Status:             publishStatus(result),
+		PostID:             result.NoteID,
+		PostURL:            result.URL,
+		XsecToken:          result.XsecToken,
+		ScheduledAt:        result.ScheduledAt,
+		Location:           result.Location,
+		UnresolvedMentions: result.UnresolvedMentions,
+		UnresolvedLocation: result.UnresolvedLocation,
 	}
+	s.history.Succeed(req.IdempotencyKey, fingerprint, resp)
+
 	return resp, nil
 }
 
 // publishVideo 执行视频发布
-func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) error {
-	b := newBrowser()
+func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) (*xiaohongshu.PublishResult, error) {
+	b := newPublishBrowser(content.Draft)
 	defer b.Close()
 
 	page := b.NewPage()
@@ -266,12 +724,132 @@ func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongs
 
 	action, err := xiaohongshu.NewPublishVideoAction(page)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	return action.PublishVideo(ctx, content)
 }
 
+// publishSettings 校验后的发布设置
+type publishSettings struct {
+	ScheduleTime *time.Time
+	Visibility   string
+	Declaration  string
+}
+
+// checkPublishRequest 下载图片和启动浏览器前校验图文发布请求，加入发布队列前使用相同的校验。
+// runAt 为执行发布的时间，定时发布时间需在其 1 小时后至 14 天内
+func (s *XiaohongshuService) checkPublishRequest(req *PublishRequest, runAt time.Time) (*publishSettings, error) {
+	if len(req.Images) == 0 {
+		return nil, fmt.Errorf("至少需要一张图片")
+	}
+	// 拼图封面会额外插入一张
+	if count := len(req.Images); req.CoverCollage != nil && count+1 > xiaohongshu.MaxImages {
+		return nil, fmt.Errorf("图片数量 %d 加上拼图封面超过平台上限 %d 张，请减少图片或不使用拼图封面", count, xiaohongshu.MaxImages)
+	} else if count > xiaohongshu.MaxImages {
+		return nil, fmt.Errorf("图片数量 %d 超过平台上限 %d 张", count, xiaohongshu.MaxImages)
+	}
+	if _, err := req.ImageOptions.Normalize(); err != nil {
+		return nil, err
+	}
+	if req.Watermark != nil {
+		if _, err := req.Watermark.Normalize(); err != nil {
+			return nil, err
+		}
+	}
+	if req.CoverCollage != nil {
+		if _, err := req.CoverCollage.Normalize(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.checkPublishCommon(req.Title, req.Content, req.Tags, req.EmojiCodes, req.PublishAt, req.Draft, req.Visibility, req.Declaration, runAt)
+}
+
+// checkPublishVideoRequest 下载视频和启动浏览器前校验视频发布请求，加入发布队列前使用相同的校验
+func (s *XiaohongshuService) checkPublishVideoRequest(req *PublishVideoRequest, runAt time.Time) (*publishSettings, error) {
+	if req.Video == "" {
+		return nil, fmt.Errorf("必须提供视频文件路径或链接")
+	}
+	if req.CoverTime != "" && req.CoverImage != "" {
+		return nil, fmt.Errorf("cover_time 和 cover_image 只能提供一个")
+	}
+	if req.CoverTime != "" {
+		if _, err := xiaohongshu.ParseCoverTime(req.CoverTime); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.checkPublishCommon(req.Title, req.Content, req.Tags, req.EmojiCodes, req.PublishAt, req.Draft, req.Visibility, req.Declaration, runAt)
+}
+
+// checkPublishCommon 校验图文和视频共用的内容和设置
+func (s *XiaohongshuService) checkPublishCommon(title, content string, tags []string, emojiCodes bool, publishAt string, draft bool, visibility, declaration string, runAt time.Time) (*publishSettings, error) {
+	// 表情代码按平台表情显示，校验字数时按转换后的正文计算
+	if emojiCodes {
+		content = xiaohongshu.EncodeEmoji(content)
+	}
+	if err := s.lintBeforePublish(title, content, tags); err != nil {
+		return nil, err
+	}
+
+	scheduleTime, err := parsePublishAt(publishAt, runAt)
+	if err != nil {
+		return nil, err
+	}
+	if draft && scheduleTime != nil {
+		return nil, fmt.Errorf("草稿模式不支持定时发布，请在发布草稿时再设置")
+	}
+
+	v, d, err := normalizePublishSettings(visibility, declaration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &publishSettings{ScheduleTime: scheduleTime, Visibility: v, Declaration: d}, nil
+}
+
+// parsePublishAt 解析并校验定时发布时间，为空表示立即发布。runAt 为执行发布的时间
+func parsePublishAt(publishAt string, runAt time.Time) (*time.Time, error) {
+	if publishAt == "" {
+		return nil, nil
+	}
+
+	t, err := xiaohongshu.ParseScheduleTime(publishAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := xiaohongshu.ValidateScheduleTime(t, runAt); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// normalizePublishSettings 校验并规范化可见范围和内容类型声明
+func normalizePublishSettings(visibility, declaration string) (string, string, error) {
+	v, err := xiaohongshu.NormalizeVisibility(visibility)
+	if err != nil {
+		return "", "", err
+	}
+	d, err := xiaohongshu.NormalizeDeclaration(declaration)
+	if err != nil {
+		return "", "", err
+	}
+	return v, d, nil
+}
+
+// publishStatus 根据发布结果生成状态描述
+func publishStatus(result *xiaohongshu.PublishResult) string {
+	if result.Draft {
+		return "已保存到草稿箱，待审核后发布"
+	}
+	if result.ScheduledAt != "" {
+		return "定时发布设置完成，将于 " + result.ScheduledAt + " 发布"
+	}
+	return "发布完成"
+}
+
 // ListFeeds 获取Feeds列表
 func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse, error) {
 	b := newBrowser()
@@ -290,6 +868,8 @@ func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse,
 		return nil, err
 	}
 
+	s.recordFeedTokens("list_feeds", feeds)
+
 	response := &FeedsListResponse{
 		Feeds: feeds,
 		Count: len(feeds),
@@ -312,6 +892,8 @@ func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string, fi
 		return nil, err
 	}
 
+	s.recordFeedTokens("search_feeds", feeds)
+
 	response := &FeedsListResponse{
 		Feeds: feeds,
 		Count: len(feeds),
@@ -322,6 +904,11 @@ func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string, fi
 
 // GetFeedDetail 获取Feed详情
 func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToken string) (*FeedDetailResponse, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindNote, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -337,6 +924,8 @@ func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToke
 		return nil, err
 	}
 
+	s.recordFeedDetailTokens(feedID, xsecToken, result)
+
 	response := &FeedDetailResponse{
 		FeedID: feedID,
 		Data:   result,
@@ -347,6 +936,11 @@ func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToke
 
 // UserProfile 获取用户信息
 func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindUser, userID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -359,6 +953,9 @@ func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken
 	if err != nil {
 		return nil, err
 	}
+
+	s.recordFeedTokens("user_profile", result.Feeds)
+
 	response := &UserProfileResponse{
 		UserBasicInfo: result.UserBasicInfo,
 		Interactions:  result.Interactions,
@@ -371,6 +968,11 @@ func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken
 
 // PostCommentToFeed 发表评论到Feed
 func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsecToken, content string) (*PostCommentResponse, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindNote, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -386,8 +988,96 @@ func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsec
 	return &PostCommentResponse{FeedID: feedID, Success: true, Message: "评论发表成功"}, nil
 }
 
+// ReplyToComment 回复笔记下的指定评论
+func (s *XiaohongshuService) ReplyToComment(ctx context.Context, feedID, xsecToken, commentID, content string) (*CommentActionResult, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindNote, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBrowser()
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	action := xiaohongshu.NewCommentFeedAction(page)
+	if err := action.ReplyToComment(ctx, feedID, xsecToken, commentID, content); err != nil {
+		return nil, err
+	}
+	return &CommentActionResult{FeedID: feedID, CommentID: commentID, Success: true, Message: "回复评论成功"}, nil
+}
+
+// LikeComment 点赞评论
+func (s *XiaohongshuService) LikeComment(ctx context.Context, feedID, xsecToken, commentID string) (*CommentActionResult, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindNote, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBrowser()
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	action := xiaohongshu.NewCommentFeedAction(page)
+	if err := action.LikeComment(ctx, feedID, xsecToken, commentID); err != nil {
+		return nil, err
+	}
+	return &CommentActionResult{FeedID: feedID, CommentID: commentID, Success: true, Message: "点赞评论成功或已点赞"}, nil
+}
+
+// UnlikeComment 取消点赞评论
+func (s *XiaohongshuService) UnlikeComment(ctx context.Context, feedID, xsecToken, commentID string) (*CommentActionResult, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindNote, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBrowser()
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	action := xiaohongshu.NewCommentFeedAction(page)
+	if err := action.UnlikeComment(ctx, feedID, xsecToken, commentID); err != nil {
+		return nil, err
+	}
+	return &CommentActionResult{FeedID: feedID, CommentID: commentID, Success: true, Message: "取消点赞评论成功或未点赞"}, nil
+}
+
+// DeleteComment 删除当前账号在笔记下发表的评论，返回被删除评论的元数据
+func (s *XiaohongshuService) DeleteComment(ctx context.Context, feedID, xsecToken string, opt xiaohongshu.DeleteCommentOption) (*xiaohongshu.DeletedComment, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindNote, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBrowser()
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	action := xiaohongshu.NewCommentFeedAction(page)
+	deleted, err := action.DeleteComment(ctx, feedID, xsecToken, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("评论已删除: feed=%s comment=%s content=%q", deleted.FeedID, deleted.CommentID, deleted.Content)
+	return deleted, nil
+}
+
 // LikeFeed 点赞笔记
 func (s *XiaohongshuService) LikeFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindNote, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -403,6 +1093,11 @@ func (s *XiaohongshuService) LikeFeed(ctx context.Context, feedID, xsecToken str
 
 // UnlikeFeed 取消点赞笔记
 func (s *XiaohongshuService) UnlikeFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindNote, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -418,6 +1113,11 @@ func (s *XiaohongshuService) UnlikeFeed(ctx context.Context, feedID, xsecToken s
 
 // FavoriteFeed 收藏笔记
 func (s *XiaohongshuService) FavoriteFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindNote, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -433,6 +1133,11 @@ func (s *XiaohongshuService) FavoriteFeed(ctx context.Context, feedID, xsecToken
 
 // UnfavoriteFeed 取消收藏笔记
 func (s *XiaohongshuService) UnfavoriteFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindNote, feedID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
 	b := newBrowser()
 	defer b.Close()
 
@@ -446,10 +1151,354 @@ func (s *XiaohongshuService) UnfavoriteFeed(ctx context.Context, feedID, xsecTok
 	return &ActionResult{FeedID: feedID, Success: true, Message: "取消收藏成功或未收藏"}, nil
 }
 
+// ResolveNoteURL 解析分享短链接或笔记/用户主页链接，得到 ID 和 xsec_token
+func (s *XiaohongshuService) ResolveNoteURL(ctx context.Context, rawURL string) (*xiaohongshu.ResolvedURL, error) {
+	link, err := xiaohongshu.ExtractShareURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// 完整链接且已带 xsec_token 时无需打开浏览器
+	if !xiaohongshu.IsShortShareURL(link) {
+		if result, err := xiaohongshu.ParseNoteURL(link); err == nil && result.XsecToken != "" {
+			s.recordResolvedToken(result)
+			return result, nil
+		}
+	}
+
+	var result *xiaohongshu.ResolvedURL
+	err = withBrowserPage(func(page *rod.Page) error {
+		action := xiaohongshu.NewResolveURLAction(page)
+		result, err = action.Resolve(ctx, link)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordResolvedToken(result)
+
+	return result, nil
+}
+
+// FollowUser 关注用户
+func (s *XiaohongshuService) FollowUser(ctx context.Context, userID, xsecToken string) (*UserActionResult, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindUser, userID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBrowser()
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	action := xiaohongshu.NewFollowAction(page)
+	if err := action.Follow(ctx, userID, xsecToken); err != nil {
+		return nil, err
+	}
+	return &UserActionResult{UserID: userID, Success: true, Message: "关注成功或已关注"}, nil
+}
+
+// UnfollowUser 取消关注用户
+func (s *XiaohongshuService) UnfollowUser(ctx context.Context, userID, xsecToken string) (*UserActionResult, error) {
+	xsecToken, err := s.lookupXsecToken(xsectoken.KindUser, userID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBrowser()
+	defer b.Close()
+
+	page := b.NewPage()
+	defer page.Close()
+
+	action := xiaohongshu.NewFollowAction(page)
+	if err := action.Unfollow(ctx, userID, xsecToken); err != nil {
+		return nil, err
+	}
+	return &UserActionResult{UserID: userID, Success: true, Message: "取消关注成功或未关注"}, nil
+}
+
+// ListMyNotes 获取创作中心中自己发布的笔记及其审核状态
+func (s *XiaohongshuService) ListMyNotes(ctx context.Context, status string) (*MyNotesResponse, error) {
+	var notes []xiaohongshu.CreatorNote
+	err := withBrowserPage(func(page *rod.Page) error {
+		action := xiaohongshu.NewCreatorNoteAction(page)
+
+		var err error
+		notes, err = action.ListNotes(ctx, status)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MyNotesResponse{Notes: notes, Count: len(notes)}, nil
+}
+
+// EditMyNote 编辑自己发布的笔记
+func (s *XiaohongshuService) EditMyNote(ctx context.Context, req *EditNoteRequest) (*NoteActionResult, error) {
+	if req.Title != "" {
+		if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
+			return nil, fmt.Errorf("标题长度超过限制")
+		}
+	}
+
+	err := withBrowserPage(func(page *rod.Page) error {
+		action := xiaohongshu.NewCreatorNoteAction(page)
+		return action.EditNote(ctx, req.NoteID, xiaohongshu.EditNoteContent{
+			Title:   req.Title,
+			Content: req.Content,
+			Tags:    req.Tags,
+		})
+	})
+	if err != nil {
+		logrus.Errorf("编辑笔记失败: note=%s %v", req.NoteID, err)
+		return nil, err
+	}
+
+	return &NoteActionResult{NoteID: req.NoteID, Success: true, Message: "笔记修改已保存，需重新审核"}, nil
+}
+
+// DeleteMyNote 删除自己发布的笔记，必须显式确认
+func (s *XiaohongshuService) DeleteMyNote(ctx context.Context, noteID string, confirm bool) (*NoteActionResult, error) {
+	if !confirm {
+		return nil, fmt.Errorf("删除笔记不可恢复，请确认后设置 confirm=true 再调用")
+	}
+
+	var deleted *xiaohongshu.CreatorNote
+	err := withBrowserPage(func(page *rod.Page) error {
+		action := xiaohongshu.NewCreatorNoteAction(page)
+
+		var err error
+		deleted, err = action.DeleteNote(ctx, noteID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("笔记已删除: note=%s title=%q", deleted.NoteID, deleted.Title)
+	return &NoteActionResult{NoteID: noteID, Success: true, Message: "笔记删除成功", Data: deleted}, nil
+}
+
+// ListDrafts 获取创作中心草稿箱中的草稿
+func (s *XiaohongshuService) ListDrafts(ctx context.Context) (*DraftsResponse, error) {
+	var drafts []xiaohongshu.Draft
+	err := withDraftBrowserPage(func(page *rod.Page) error {
+		action := xiaohongshu.NewDraftAction(page)
+
+		var err error
+		drafts, err = action.ListDrafts(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DraftsResponse{Drafts: drafts, Count: len(drafts)}, nil
+}
+
+// PublishTextCards 将文字渲染为卡片图片后按图文发布
+func (s *XiaohongshuService) PublishTextCards(ctx context.Context, req *PublishTextCardsRequest) (*PublishTextCardsResponse, error) {
+	cardTitle := req.CardTitle
+	if cardTitle == "" {
+		cardTitle = req.Title
+	}
+
+	cards, err := textcard.Render(cardTitle, req.CardText, textcard.Options{
+		Theme:     req.Theme,
+		FontPath:  req.FontPath,
+		Watermark: req.Watermark,
+		MaxPages:  xiaohongshu.MaxImages,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.PublishContent(ctx, &PublishRequest{
+		Title:       req.Title,
+		Content:     req.Content,
+		Images:      cards,
+		Tags:        req.Tags,
+		PublishAt:   req.PublishAt,
+		Draft:       req.Draft,
+		Visibility:  req.Visibility,
+		Original:    req.Original,
+		Declaration: req.Declaration,
+
+		IdempotencyKey: req.IdempotencyKey,
+		// 卡片是纯色背景的文字，保持 PNG 避免 JPEG 压缩在文字边缘产生噪点
+		ImageOptions: downloader.PreprocessOptions{Format: downloader.FormatPNG},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublishTextCardsResponse{PublishResponse: *result, Cards: cards}, nil
+}
+
+// PublishMarkdown 解析 Markdown 文件并作为图文笔记发布：
+// front matter 提供标题、标签、可见范围和定时发布时间，正文转为纯文本，引用的图片按文档顺序作为图片列表
+func (s *XiaohongshuService) PublishMarkdown(ctx context.Context, req *PublishMarkdownRequest) (*PublishMarkdownResponse, error) {
+	note, err := mdnote.ParseFile(req.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	note.Draft = note.Draft || req.Draft
+
+	if req.DryRun {
+		return &PublishMarkdownResponse{
+			Note: note,
+			Lint: s.LintContent(note.Title, note.Content, note.Tags),
+		}, nil
+	}
+
+	result, err := s.PublishContent(ctx, &PublishRequest{
+		Title:       note.Title,
+		Content:     note.Content,
+		Images:      note.Images,
+		Tags:        note.Tags,
+		PublishAt:   note.PublishAt,
+		Draft:       note.Draft,
+		Visibility:  note.Visibility,
+		Original:    note.Original,
+		Declaration: note.Declaration,
+		Mentions:    note.Mentions,
+		Location:    note.Location,
+
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublishMarkdownResponse{Note: note, Result: result}, nil
+}
+
+// PublishDraft 发布草稿箱中的草稿
+func (s *XiaohongshuService) PublishDraft(ctx context.Context, draftID string) (*NoteActionResult, error) {
+	if draftID == "" {
+		return nil, fmt.Errorf("缺少草稿ID")
+	}
+
+	var (
+		draft  *xiaohongshu.Draft
+		result *xiaohongshu.PublishResult
+	)
+	err := withDraftBrowserPage(func(page *rod.Page) error {
+		action := xiaohongshu.NewDraftAction(page)
+
+		var err error
+		draft, result, err = action.PublishDraft(ctx, draftID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.recordPublishedToken(result)
+
+	return &NoteActionResult{NoteID: result.NoteID, Success: true, Message: "草稿发布完成", Data: draft}, nil
+}
+
+// lookupXsecToken 未提供 xsec_token 时，从令牌缓存中查找最近记录的令牌
+func (s *XiaohongshuService) lookupXsecToken(kind, id, xsecToken string) (string, error) {
+	if xsecToken != "" {
+		return xsecToken, nil
+	}
+	if id == "" {
+		return "", fmt.Errorf("缺少ID参数")
+	}
+
+	entry, err := s.tokens.Get(kind, id)
+	switch {
+	case errors.Is(err, xsectoken.ErrTokenExpired):
+		return "", fmt.Errorf("%s %s 的 xsec_token 已过期（来源: %s，记录于 %s 前），请重新通过 search_feeds/list_feeds 获取或显式提供 xsec_token",
+			kind, id, entry.Source, entry.Age().Round(time.Minute))
+	case err != nil:
+		return "", fmt.Errorf("缺少xsec_token参数，且未找到 %s %s 的令牌缓存，请先通过 search_feeds/list_feeds/resolve_note_url 获取或显式提供 xsec_token",
+			kind, id)
+	}
+
+	logrus.Infof("使用缓存的 xsec_token: %s %s (来源: %s)", kind, id, entry.Source)
+	return entry.XsecToken, nil
+}
+
+// recordFeedTokens 记录 Feed 列表中笔记和作者的 xsec_token
+func (s *XiaohongshuService) recordFeedTokens(source string, feeds []xiaohongshu.Feed) {
+	entries := make([]xsectoken.Entry, 0, len(feeds)*2)
+	for _, feed := range feeds {
+		entries = append(entries,
+			xsectoken.Entry{Kind: xsectoken.KindNote, ID: feed.ID, XsecToken: feed.XsecToken, Source: source},
+			xsectoken.Entry{Kind: xsectoken.KindUser, ID: feed.NoteCard.User.UserID, XsecToken: feed.NoteCard.User.XsecToken, Source: source},
+		)
+	}
+	s.tokens.Record(entries...)
+}
+
+// recordFeedDetailTokens 记录详情页中笔记、作者及评论用户的 xsec_token
+func (s *XiaohongshuService) recordFeedDetailTokens(feedID, xsecToken string, detail *xiaohongshu.FeedDetailResponse) {
+	const source = "get_feed_detail"
+
+	noteToken := detail.Note.XsecToken
+	if noteToken == "" {
+		noteToken = xsecToken
+	}
+
+	entries := []xsectoken.Entry{
+		{Kind: xsectoken.KindNote, ID: feedID, XsecToken: noteToken, Source: source},
+		{Kind: xsectoken.KindUser, ID: detail.Note.User.UserID, XsecToken: detail.Note.User.XsecToken, Source: source},
+	}
+	for _, comment := range detail.Comments.List {
+		entries = append(entries, xsectoken.Entry{Kind: xsectoken.KindUser, ID: comment.UserInfo.UserID, XsecToken: comment.UserInfo.XsecToken, Source: source})
+		for _, sub := range comment.SubComments {
+			entries = append(entries, xsectoken.Entry{Kind: xsectoken.KindUser, ID: sub.UserInfo.UserID, XsecToken: sub.UserInfo.XsecToken, Source: source})
+		}
+	}
+	s.tokens.Record(entries...)
+}
+
+// recordResolvedToken 记录链接解析得到的 xsec_token
+func (s *XiaohongshuService) recordResolvedToken(result *xiaohongshu.ResolvedURL) {
+	const source = "resolve_note_url"
+
+	if result.Type == xiaohongshu.ResolvedTypeUser {
+		s.tokens.Record(xsectoken.Entry{Kind: xsectoken.KindUser, ID: result.UserID, XsecToken: result.XsecToken, Source: source})
+		return
+	}
+	s.tokens.Record(xsectoken.Entry{Kind: xsectoken.KindNote, ID: result.FeedID, XsecToken: result.XsecToken, Source: source})
+}
+
+// recordPublishedToken 记录新发布笔记的 xsec_token
+func (s *XiaohongshuService) recordPublishedToken(result *xiaohongshu.PublishResult) {
+	s.tokens.Record(xsectoken.Entry{Kind: xsectoken.KindNote, ID: result.NoteID, XsecToken: result.XsecToken, Source: "publish"})
+}
+
 func newBrowser() *headless_browser.Browser {
 	return browser.NewBrowser(configs.IsHeadless(), browser.WithBinPath(configs.GetBinPath()))
 }
 
+// pageBrowser 可以打开页面的浏览器
+type pageBrowser interface {
+	NewPage() *rod.Page
+	Close()
+}
+
+// newDraftBrowser 草稿相关操作使用的浏览器。网页版草稿箱保存在浏览器本地，使用固定的用户目录
+func newDraftBrowser() *browser.ProfileBrowser {
+	return browser.NewProfileBrowser(configs.IsHeadless(), configs.GetProfileDir(), browser.WithBinPath(configs.GetBinPath()))
+}
+
+// newPublishBrowser 保存草稿时使用草稿浏览器，否则使用普通浏览器
+func newPublishBrowser(draft bool) pageBrowser {
+	if draft {
+		return newDraftBrowser()
+	}
+	return newBrowser()
+}
+
 func saveCookies(page *rod.Page) error {
 	cks, err := page.Browser().GetCookies()
 	if err != nil {
@@ -467,7 +1516,15 @@ func saveCookies(page *rod.Page) error {
 
 // withBrowserPage 执行需要浏览器页面的操作的通用函数
 func withBrowserPage(fn func(*rod.Page) error) error {
-	b := newBrowser()
+	return withPage(newBrowser(), fn)
+}
+
+// withDraftBrowserPage 在草稿浏览器中执行操作
+func withDraftBrowserPage(fn func(*rod.Page) error) error {
+	return withPage(newDraftBrowser(), fn)
+}
+
+func withPage(b pageBrowser, fn func(*rod.Page) error) error {
 	defer b.Close()
 
 	page := b.NewPage()
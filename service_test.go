@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/idempotency"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/contentlint"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/imagekit"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/videoprobe"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+func newTestService(t *testing.T) *XiaohongshuService {
+	t.Helper()
+
+	return &XiaohongshuService{
+		linter:      contentlint.New(contentlint.DefaultRules()),
+		videoLimits: videoprobe.DefaultLimits(),
+		history:     idempotency.NewStore(filepath.Join(t.TempDir(), "publish_history.json"), time.Hour),
+	}
+}
+
+func TestPublishVideoWithoutCover(t *testing.T) {
+	s := newTestService(t)
+
+	video := filepath.Join(t.TempDir(), "video.mp4")
+	require.NoError(t, os.WriteFile(video, []byte("not a real video"), 0644))
+
+	// 幂等键已用于其他内容，发布在启动浏览器前返回，覆盖未设置封面时的校验流程
+	_, err := s.history.Begin("used-key", "other")
+	require.NoError(t, err)
+
+	_, err = s.PublishVideo(context.Background(), &PublishVideoRequest{
+		Title:          "视频标题",
+		Content:        "视频正文",
+		Video:          video,
+		IdempotencyKey: "used-key",
+	})
+	require.ErrorIs(t, err, idempotency.ErrKeyConflict)
+}
+
+func TestRunPublishRecoversPanic(t *testing.T) {
+	s := newTestService(t)
+
+	_, err := s.history.Begin("key", "fp")
+	require.NoError(t, err)
+
+	_, err = s.runPublish("key", "fp", func() (*xiaohongshu.PublishResult, error) {
+		panic("element not found")
+	})
+	require.ErrorIs(t, err, xiaohongshu.ErrPublishUnconfirmed)
+
+	// 异常中断后可能已经发布，相同请求不能直接重试
+	_, err = s.history.Begin("key", "fp")
+	require.ErrorIs(t, err, idempotency.ErrUnconfirmed)
+}
+
+func TestPublishSettingsExtra(t *testing.T) {
+	base := publishSettingsExtra("", false, "", []string{"小明"}, "上海")
+	require.Equal(t, base, publishSettingsExtra("", false, "", []string{" 小明 ", ""}, " 上海"))
+
+	// 只修改发布设置时不能被当作重复请求
+	require.NotEqual(t, base, publishSettingsExtra("仅自己可见", false, "", []string{"小明"}, "上海"))
+	require.NotEqual(t, base, publishSettingsExtra("", true, "", []string{"小明"}, "上海"))
+	require.NotEqual(t, base, publishSettingsExtra("", false, "笔记含AI合成内容", []string{"小明"}, "上海"))
+	require.NotEqual(t, base, publishSettingsExtra("", false, "", nil, "上海"))
+	require.NotEqual(t, base, publishSettingsExtra("", false, "", []string{"小明"}, "北京"))
+}
+
+func TestCheckPublishRequest(t *testing.T) {
+	s := newTestService(t)
+	now := time.Now()
+
+	images := make([]string, xiaohongshu.MaxImages)
+	for i := range images {
+		images[i] = "/tmp/image.jpg"
+	}
+	req := &PublishRequest{Title: "标题", Content: "正文", Images: images}
+	_, err := s.checkPublishRequest(req, now)
+	require.NoError(t, err)
+
+	// 拼图封面计入图片数量
+	req.CoverCollage = &imagekit.CollageOptions{}
+	_, err = s.checkPublishRequest(req, now)
+	require.ErrorContains(t, err, "拼图封面")
+
+	req = &PublishRequest{Title: "标题", Content: "正文", Images: images[:1], Draft: true, PublishAt: now.Add(2 * time.Hour).Format(time.RFC3339)}
+	_, err = s.checkPublishRequest(req, now)
+	require.ErrorContains(t, err, "草稿模式不支持定时发布")
+
+	// 定时发布时间按任务执行时间校验
+	req.Draft = false
+	_, err = s.checkPublishRequest(req, now.Add(3*time.Hour))
+	require.ErrorContains(t, err, "过早")
+	settings, err := s.checkPublishRequest(req, now)
+	require.NoError(t, err)
+	require.NotNil(t, settings.ScheduleTime)
+}
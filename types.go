@@ -37,7 +37,7 @@ type MCPContent struct {
 // FeedDetailRequest Feed详情请求
 type FeedDetailRequest struct {
 	FeedID    string `json:"feed_id" binding:"required"`
-	XsecToken string `json:"xsec_token" binding:"required"`
+	XsecToken string `json:"xsec_token,omitempty"`
 }
 
 type SearchFeedsRequest struct {
@@ -54,7 +54,7 @@ type FeedDetailResponse struct {
 // PostCommentRequest 发表评论请求
 type PostCommentRequest struct {
 	FeedID    string `json:"feed_id" binding:"required"`
-	XsecToken string `json:"xsec_token" binding:"required"`
+	XsecToken string `json:"xsec_token,omitempty"`
 	Content   string `json:"content" binding:"required"`
 }
 
@@ -68,7 +68,7 @@ type PostCommentResponse struct {
 // UserProfileRequest 用户主页请求
 type UserProfileRequest struct {
 	UserID    string `json:"user_id" binding:"required"`
-	XsecToken string `json:"xsec_token" binding:"required"`
+	XsecToken string `json:"xsec_token,omitempty"`
 }
 
 // ActionResult 通用动作响应（点赞/收藏等）
@@ -77,3 +77,26 @@ type ActionResult struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 }
+
+// CommentActionResult 评论相关动作响应（回复/点赞评论等）
+type CommentActionResult struct {
+	FeedID    string `json:"feed_id"`
+	CommentID string `json:"comment_id"`
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+}
+
+// UserActionResult 用户相关动作响应（关注/取消关注等）
+type UserActionResult struct {
+	UserID  string `json:"user_id"`
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// NoteActionResult 自己笔记相关动作响应（编辑/删除等）
+type NoteActionResult struct {
+	NoteID  string `json:"note_id"`
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
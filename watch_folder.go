@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/mdnote"
+	"github.com/xpzouying/xiaohongshu-mcp/publishqueue"
+	"github.com/xpzouying/xiaohongshu-mcp/watchfolder"
+)
+
+// enqueueFolder 解析监听目录中文件夹的 post.md，校验后加入发布队列
+func (s *AppServer) enqueueFolder(ctx context.Context, dir string) (publishqueue.Job, error) {
+	note, err := mdnote.ParseFile(filepath.Join(dir, watchfolder.PostFile))
+	if err != nil {
+		return publishqueue.Job{}, err
+	}
+
+	req := &PublishRequest{
+		Title:       note.Title,
+		Content:     note.Content,
+		Images:      note.Images,
+		Tags:        note.Tags,
+		PublishAt:   note.PublishAt,
+		Draft:       note.Draft,
+		Visibility:  note.Visibility,
+		Original:    note.Original,
+		Declaration: note.Declaration,
+		Mentions:    note.Mentions,
+		Location:    note.Location,
+	}
+
+	// 与直接发布一致的预检：图片数量上限、定时发布时间与草稿冲突、发布设置和内容检查
+	if _, err := s.xiaohongshuService.checkPublishRequest(req, time.Now()); err != nil {
+		return publishqueue.Job{}, err
+	}
+	for _, img := range note.Images {
+		if downloader.IsImageURL(img) || downloader.IsDataURI(img) {
+			continue
+		}
+		if _, err := os.Stat(img); err != nil {
+			return publishqueue.Job{}, fmt.Errorf("图片不存在: %s", filepath.Base(img))
+		}
+	}
+
+	logrus.Infof("自动发布: 加入发布队列 - 文件夹: %s, 标题: %s", filepath.Base(dir), note.Title)
+
+	return s.publishQueue.Enqueue(publishqueue.DefaultAccount, publishqueue.KindImage, req, time.Time{}, 0)
+}
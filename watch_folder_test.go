@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/watchfolder"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+func writeTestFolder(t *testing.T, frontMatter string, images int) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	var body strings.Builder
+	body.WriteString("---\ntitle: 标题\n" + frontMatter + "---\n\n正文\n\n")
+	for i := 0; i < images; i++ {
+		name := fmt.Sprintf("%02d.jpg", i)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("jpg"), 0644))
+		fmt.Fprintf(&body, "![](%s)\n", name)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, watchfolder.PostFile), []byte(body.String()), 0644))
+	return dir
+}
+
+func TestEnqueueFolderPreChecks(t *testing.T) {
+	// 预检失败时不会入队，未初始化的发布队列不会被访问
+	s := &AppServer{xiaohongshuService: newTestService(t)}
+
+	_, err := s.enqueueFolder(context.Background(), writeTestFolder(t, "", xiaohongshu.MaxImages+1))
+	require.ErrorContains(t, err, "超过平台上限")
+
+	publishAt := time.Now().Add(2 * time.Hour).Format(time.RFC3339)
+	_, err = s.enqueueFolder(context.Background(), writeTestFolder(t, "draft: true\npublish_at: "+publishAt+"\n", 1))
+	require.ErrorContains(t, err, "草稿模式不支持定时发布")
+}
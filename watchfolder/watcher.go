@@ -0,0 +1,326 @@
+package watchfolder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/publishqueue"
+)
+
+const (
+	// PostFile 文件夹中的笔记文件，存在该文件的子文件夹才会被发布
+	PostFile = "post.md"
+	// ResultFile 处理完成后写入的结果文件
+	ResultFile = "result.json"
+	// DoneDir 发布成功的文件夹移动到该目录
+	DoneDir = "done"
+	// FailedDir 校验或发布失败的文件夹移动到该目录
+	FailedDir = "failed"
+
+	// jobFile 已加入发布队列的文件夹中记录任务 ID，服务重启后继续跟踪任务
+	jobFile = ".job"
+)
+
+// 复制中的临时文件后缀，文件夹中存在这些文件时视为未复制完成
+var partialSuffixes = []string{".part", ".tmp", ".crdownload", ".download", "~"}
+
+// Submitter 校验文件夹中的笔记并加入发布队列
+type Submitter func(ctx context.Context, dir string) (publishqueue.Job, error)
+
+// JobGetter 查询发布任务的状态，*publishqueue.Queue 实现了该接口
+type JobGetter interface {
+	Get(id string) (publishqueue.Job, error)
+}
+
+// Result 写入 result.json 的处理结果
+type Result struct {
+	Folder     string          `json:"folder"`
+	Status     string          `json:"status"` // succeeded/failed/canceled
+	JobID      string          `json:"job_id,omitempty"`
+	Attempts   int             `json:"attempts,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Result     json.RawMessage `json:"result,omitempty"` // 发布成功时的发布结果
+	FinishedAt time.Time       `json:"finished_at"`
+}
+
+// Options 监听参数，零值使用默认值
+type Options struct {
+	PollInterval time.Duration // 扫描目录的间隔
+	SettleTime   time.Duration // 文件夹内容在该时长内没有变化才视为复制完成
+}
+
+func (o Options) withDefaults() Options {
+	if o.PollInterval <= 0 {
+		o.PollInterval = 10 * time.Second
+	}
+	if o.SettleTime <= 0 {
+		o.SettleTime = 30 * time.Second
+	}
+	return o
+}
+
+// Watcher 监听目录，将复制完成的笔记文件夹加入发布队列，
+// 任务结束后把文件夹移动到 done/ 或 failed/ 并写入 result.json。
+type Watcher struct {
+	root   string
+	submit Submitter
+	jobs   JobGetter
+	opts   Options
+
+	mu      sync.Mutex
+	running bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewWatcher 创建目录监听，root 不存在时自动创建
+func NewWatcher(root string, submit Submitter, jobs JobGetter, opts Options) (*Watcher, error) {
+	for _, dir := range []string{root, filepath.Join(root, DoneDir), filepath.Join(root, FailedDir)} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, errors.Wrapf(err, "创建监听目录失败: %s", dir)
+		}
+	}
+
+	return &Watcher{
+		root:   root,
+		submit: submit,
+		jobs:   jobs,
+		opts:   opts.withDefaults(),
+	}, nil
+}
+
+// Start 启动后台扫描
+func (w *Watcher) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running {
+		return
+	}
+	w.running = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	logrus.Infof("开始监听发布目录: %s", w.root)
+
+	w.wg.Add(1)
+	go w.loop(ctx)
+}
+
+// Stop 停止后台扫描，已入队的任务不受影响
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = false
+	w.cancel()
+	w.mu.Unlock()
+
+	w.wg.Wait()
+	logrus.Info("发布目录监听已停止")
+}
+
+func (w *Watcher) loop(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.scan(ctx, time.Now())
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scan 处理监听目录下的每个子文件夹：已入队的检查任务状态，复制完成的加入发布队列
+func (w *Watcher) scan(ctx context.Context, now time.Time) {
+	entries, err := os.ReadDir(w.root)
+	if err != nil {
+		logrus.Warnf("failed to read watch dir: %v", err)
+		return
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+
+		name := entry.Name()
+		if !entry.IsDir() || name == DoneDir || name == FailedDir || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		dir := filepath.Join(w.root, name)
+		if jobID, ok := readJobID(dir); ok {
+			w.track(dir, jobID)
+			continue
+		}
+
+		if ready, err := isReady(dir, now, w.opts.SettleTime); err != nil {
+			logrus.Warnf("failed to check folder %s: %v", dir, err)
+		} else if ready {
+			w.enqueue(ctx, dir)
+		}
+	}
+}
+
+// enqueue 校验并加入发布队列，校验失败的文件夹直接移动到 failed/
+func (w *Watcher) enqueue(ctx context.Context, dir string) {
+	job, err := w.submit(ctx, dir)
+	if err != nil {
+		logrus.Warnf("发布目录中的笔记校验失败: %s %v", dir, err)
+		w.finish(dir, FailedDir, &Result{Status: publishqueue.StatusFailed, Error: err.Error()})
+		return
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, jobFile), []byte(job.ID), 0644); err != nil {
+		// 记录失败时下次扫描会重新提交，相同内容的重复发布由发布记录去重
+		logrus.Warnf("failed to write job file for %s: %v", dir, err)
+		return
+	}
+
+	logrus.Infof("发布目录中的笔记已加入队列: %s job=%s", dir, job.ID)
+}
+
+// track 任务结束后按结果移动文件夹
+func (w *Watcher) track(dir, jobID string) {
+	job, err := w.jobs.Get(jobID)
+	if err != nil {
+		if errors.Is(err, publishqueue.ErrJobNotFound) {
+			w.finish(dir, FailedDir, &Result{Status: publishqueue.StatusFailed, JobID: jobID, Error: err.Error()})
+		}
+		return
+	}
+
+	result := &Result{
+		Status:   job.Status,
+		JobID:    job.ID,
+		Attempts: job.Attempts,
+		Error:    job.LastError,
+	}
+
+	switch job.Status {
+	case publishqueue.StatusSucceeded:
+		result.Error = ""
+		result.Result = job.Result
+		w.finish(dir, DoneDir, result)
+	case publishqueue.StatusFailed, publishqueue.StatusCanceled:
+		w.finish(dir, FailedDir, result)
+	}
+}
+
+// finish 移动文件夹并写入结果文件
+func (w *Watcher) finish(dir, target string, result *Result) {
+	dest, err := moveFolder(dir, filepath.Join(w.root, target))
+	if err != nil {
+		logrus.Errorf("failed to move folder %s: %v", dir, err)
+		return
+	}
+	_ = os.Remove(filepath.Join(dest, jobFile))
+
+	result.Folder = filepath.Base(dir)
+	result.FinishedAt = time.Now()
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dest, ResultFile), data, 0644)
+	}
+	if err != nil {
+		logrus.Warnf("failed to write result file for %s: %v", dest, err)
+	}
+
+	logrus.Infof("发布目录处理完成: %s -> %s (%s)", dir, dest, result.Status)
+}
+
+// readJobID 读取已入队文件夹记录的任务 ID
+func readJobID(dir string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, jobFile))
+	if err != nil {
+		return "", false
+	}
+	id := strings.TrimSpace(string(data))
+	return id, id != ""
+}
+
+// isReady 文件夹包含 post.md、没有复制中的临时文件，且最近 settle 时长内没有修改时视为复制完成
+func isReady(dir string, now time.Time, settle time.Duration) (bool, error) {
+	if _, err := os.Stat(filepath.Join(dir, PostFile)); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var (
+		latest  time.Time
+		partial bool
+	)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if isPartial(d.Name()) {
+			partial = true
+			return filepath.SkipAll
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return !partial && now.Sub(latest) >= settle, nil
+}
+
+func isPartial(name string) bool {
+	lower := strings.ToLower(name)
+	for _, suffix := range partialSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// moveFolder 将文件夹移动到 targetDir 下，同名文件夹已存在时加时间后缀
+func moveFolder(dir, targetDir string) (string, error) {
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return "", err
+	}
+
+	name := filepath.Base(dir)
+	dest := filepath.Join(targetDir, name)
+	if _, err := os.Stat(dest); err == nil {
+		dest = filepath.Join(targetDir, fmt.Sprintf("%s-%s", name, time.Now().Format("20060102-150405")))
+	}
+
+	if err := os.Rename(dir, dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
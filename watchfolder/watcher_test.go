@@ -0,0 +1,141 @@
+package watchfolder
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/publishqueue"
+)
+
+type fakeJobs map[string]publishqueue.Job
+
+func (f fakeJobs) Get(id string) (publishqueue.Job, error) {
+	job, ok := f[id]
+	if !ok {
+		return publishqueue.Job{}, publishqueue.ErrJobNotFound
+	}
+	return job, nil
+}
+
+func writeFolder(t *testing.T, root, name string, files ...string) string {
+	t.Helper()
+
+	dir := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for _, file := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte("x"), 0644))
+	}
+	return dir
+}
+
+func readResult(t *testing.T, dir string) Result {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, ResultFile))
+	require.NoError(t, err)
+
+	var result Result
+	require.NoError(t, json.Unmarshal(data, &result))
+	return result
+}
+
+func TestIsReady(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	later := now.Add(time.Minute)
+
+	dir := writeFolder(t, root, "no-post", "1.jpg")
+	ready, err := isReady(dir, later, 30*time.Second)
+	require.NoError(t, err)
+	require.False(t, ready)
+
+	dir = writeFolder(t, root, "copying", PostFile, "1.jpg.part")
+	ready, err = isReady(dir, later, 30*time.Second)
+	require.NoError(t, err)
+	require.False(t, ready)
+
+	dir = writeFolder(t, root, "complete", PostFile, "1.jpg")
+	ready, err = isReady(dir, now, 30*time.Second)
+	require.NoError(t, err)
+	require.False(t, ready, "刚修改过的文件夹需要等待")
+
+	ready, err = isReady(dir, later, 30*time.Second)
+	require.NoError(t, err)
+	require.True(t, ready)
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	jobs := fakeJobs{}
+
+	var submitted []string
+	submit := func(ctx context.Context, dir string) (publishqueue.Job, error) {
+		name := filepath.Base(dir)
+		submitted = append(submitted, name)
+		if name == "invalid" {
+			return publishqueue.Job{}, errors.New("缺少标题")
+		}
+		job := publishqueue.Job{ID: "job-" + name, Status: publishqueue.StatusPending}
+		jobs[job.ID] = job
+		return job, nil
+	}
+
+	w, err := NewWatcher(root, submit, jobs, Options{})
+	require.NoError(t, err)
+
+	writeFolder(t, root, "ok", PostFile, "1.jpg")
+	writeFolder(t, root, "retry", PostFile, "1.jpg")
+	writeFolder(t, root, "invalid", PostFile)
+	writeFolder(t, root, "empty")
+
+	ctx := context.Background()
+	later := time.Now().Add(time.Minute)
+
+	w.scan(ctx, later)
+	require.ElementsMatch(t, []string{"ok", "retry", "invalid"}, submitted)
+
+	// 校验失败直接移到 failed/
+	result := readResult(t, filepath.Join(root, FailedDir, "invalid"))
+	require.Equal(t, publishqueue.StatusFailed, result.Status)
+	require.Equal(t, "缺少标题", result.Error)
+
+	// 任务未结束时文件夹保持不动，也不会重复提交
+	submitted = nil
+	w.scan(ctx, later)
+	require.Empty(t, submitted)
+	require.DirExists(t, filepath.Join(root, "ok"))
+
+	jobs["job-ok"] = publishqueue.Job{ID: "job-ok", Status: publishqueue.StatusSucceeded, Attempts: 1, Result: json.RawMessage(`{"note_id":"abc"}`)}
+	jobs["job-retry"] = publishqueue.Job{ID: "job-retry", Status: publishqueue.StatusFailed, Attempts: 3, LastError: "上传失败"}
+	w.scan(ctx, later)
+
+	require.NoDirExists(t, filepath.Join(root, "ok"))
+	result = readResult(t, filepath.Join(root, DoneDir, "ok"))
+	require.Equal(t, publishqueue.StatusSucceeded, result.Status)
+	require.Equal(t, "job-ok", result.JobID)
+	require.JSONEq(t, `{"note_id":"abc"}`, string(result.Result))
+	require.NoFileExists(t, filepath.Join(root, DoneDir, "ok", jobFile))
+
+	result = readResult(t, filepath.Join(root, FailedDir, "retry"))
+	require.Equal(t, "上传失败", result.Error)
+	require.Equal(t, 3, result.Attempts)
+
+	require.DirExists(t, filepath.Join(root, "empty"))
+}
+
+func TestMoveFolderConflict(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, DoneDir)
+	writeFolder(t, target, "post")
+
+	dest, err := moveFolder(writeFolder(t, root, "post", PostFile), target)
+	require.NoError(t, err)
+	require.NotEqual(t, filepath.Join(target, "post"), dest)
+	require.FileExists(t, filepath.Join(dest, PostFile))
+}
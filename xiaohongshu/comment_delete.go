@@ -0,0 +1,253 @@
+package xiaohongshu
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DeletedComment 被删除评论的元数据，用于审计
+type DeletedComment struct {
+	FeedID    string `json:"feed_id"`
+	CommentID string `json:"comment_id"`
+	Content   string `json:"content"`
+	UserID    string `json:"user_id"`
+	Nickname  string `json:"nickname"`
+	Date      string `json:"date"` // 页面展示的评论时间
+	DeletedAt int64  `json:"deleted_at"`
+}
+
+// 删除评论相关选择器
+const (
+	selectorCommentContent  = ".content"
+	selectorCommentAuthor   = ".author a.name"
+	selectorCommentDate     = ".info .date"
+	selectorCommentMoreMenu = ".interactions .more, .interactions .menu-icon, .operation .more"
+	selectorMenuItems       = ".dropdown-items div, .d-dropdown-item, .menu-item, li"
+	selectorConfirmButtons  = ".d-modal button, .reds-modal button, [role=dialog] button, .confirm-modal button"
+)
+
+var userProfileHrefPattern = regexp.MustCompile(`/user/profile/([0-9a-zA-Z]+)`)
+
+// DeleteCommentOption 删除评论的定位方式，CommentID 和 Content 二选一
+type DeleteCommentOption struct {
+	CommentID string
+	Content   string // 按评论内容精确匹配，仅匹配当前账号的评论
+}
+
+// DeleteComment 删除当前账号在笔记下发表的评论，并确认其已消失
+func (f *CommentFeedAction) DeleteComment(ctx context.Context, feedID, xsecToken string, opt DeleteCommentOption) (*DeletedComment, error) {
+	if opt.CommentID == "" && opt.Content == "" {
+		return nil, errors.New("必须提供评论ID或评论内容")
+	}
+
+	page := f.page.Context(ctx).Timeout(120 * time.Second)
+
+	url := makeFeedDetailURL(feedID, xsecToken)
+	logrus.Infof("Opening feed detail page to delete comment: %s", url)
+
+	page.MustNavigate(url)
+	page.MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	// 自己笔记下所有评论都有删除菜单，不能据此判断评论是否为本人发表
+	myUserID := getCurrentUserID(page)
+	if myUserID == "" {
+		return nil, errors.New("未能获取当前登录用户ID，无法确认评论是否为本人发表，已取消删除")
+	}
+
+	var commentElem *rod.Element
+	var err error
+	if opt.CommentID != "" {
+		commentElem, err = findCommentElement(page, opt.CommentID)
+	} else {
+		commentElem, err = findOwnCommentByContent(page, opt.Content, myUserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	meta := readCommentMeta(commentElem)
+	meta.FeedID = feedID
+
+	if err := checkCommentOwner(meta, myUserID); err != nil {
+		return nil, err
+	}
+
+	if err := clickCommentDeleteMenu(page, commentElem); err != nil {
+		return nil, err
+	}
+
+	if err := confirmDeleteDialog(page); err != nil {
+		return nil, err
+	}
+
+	time.Sleep(2 * time.Second)
+
+	if has, _, _ := page.Has("#comment-" + meta.CommentID); has {
+		return nil, errors.Errorf("删除评论 %s 后评论仍然存在", meta.CommentID)
+	}
+
+	meta.DeletedAt = time.Now().Unix()
+	logrus.Infof("feed %s 删除评论 %s 成功", feedID, meta.CommentID)
+
+	return meta, nil
+}
+
+// findOwnCommentByContent 按内容精确查找当前账号发表的评论
+func findOwnCommentByContent(page *rod.Page, content, myUserID string) (*rod.Element, error) {
+	content = strings.TrimSpace(content)
+
+	elem := scrollFindComment(page, func() *rod.Element {
+		items, err := page.Elements(".comment-item")
+		if err != nil {
+			return nil
+		}
+
+		for _, item := range items {
+			meta := readCommentMeta(item)
+			if meta.Content != content {
+				continue
+			}
+			if meta.UserID != myUserID {
+				continue
+			}
+			return item
+		}
+		return nil
+	})
+	if elem == nil {
+		return nil, errors.Errorf("没有找到内容为 %q 的本人评论", content)
+	}
+
+	return elem, nil
+}
+
+// checkCommentOwner 确认评论由当前账号发表，无法读取评论作者时拒绝删除
+func checkCommentOwner(meta *DeletedComment, myUserID string) error {
+	if meta.UserID == "" {
+		return errors.Errorf("未能读取评论 %s 的作者，无法确认是否为本人评论，已取消删除", meta.CommentID)
+	}
+	if myUserID == "" || meta.UserID != myUserID {
+		return errors.Errorf("评论 %s 不是当前账号发表的，无法删除", meta.CommentID)
+	}
+	return nil
+}
+
+// readCommentMeta 从评论元素中读取元数据
+func readCommentMeta(commentElem *rod.Element) *DeletedComment {
+	meta := &DeletedComment{}
+
+	if id, err := commentElem.Attribute("id"); err == nil && id != nil {
+		meta.CommentID = strings.TrimPrefix(*id, "comment-")
+	}
+
+	if elem, err := commentElem.Element(selectorCommentContent); err == nil {
+		if text, err := elem.Text(); err == nil {
+			meta.Content = strings.TrimSpace(text)
+		}
+	}
+
+	if elem, err := commentElem.Element(selectorCommentAuthor); err == nil {
+		if text, err := elem.Text(); err == nil {
+			meta.Nickname = strings.TrimSpace(text)
+		}
+		if href, err := elem.Attribute("href"); err == nil && href != nil {
+			if m := userProfileHrefPattern.FindStringSubmatch(*href); m != nil {
+				meta.UserID = m[1]
+			}
+		}
+	}
+
+	if elem, err := commentElem.Element(selectorCommentDate); err == nil {
+		if text, err := elem.Text(); err == nil {
+			meta.Date = strings.TrimSpace(text)
+		}
+	}
+
+	return meta
+}
+
+// clickCommentDeleteMenu 打开评论的更多菜单并点击“删除”
+func clickCommentDeleteMenu(page *rod.Page, commentElem *rod.Element) error {
+	commentElem.MustHover()
+	time.Sleep(500 * time.Millisecond)
+
+	moreElem, err := commentElem.Element(selectorCommentMoreMenu)
+	if err != nil {
+		return errors.Wrap(err, "没有找到评论的更多菜单")
+	}
+	moreElem.MustHover()
+	moreElem.MustClick()
+	time.Sleep(500 * time.Millisecond)
+
+	deleteItem, err := findVisibleElementByText(page, selectorMenuItems, "删除")
+	if err != nil {
+		return errors.Wrap(err, "评论菜单中没有删除选项，可能不是本人评论")
+	}
+	deleteItem.MustClick()
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
+
+// confirmDeleteDialog 在确认弹窗中点击确认删除
+func confirmDeleteDialog(page *rod.Page) error {
+	for _, text := range []string{"删除", "确定", "确认"} {
+		if btn, err := findVisibleElementByText(page, selectorConfirmButtons, text); err == nil {
+			btn.MustClick()
+			return nil
+		}
+	}
+
+	// 部分版本没有二次确认
+	logrus.Info("未出现删除确认弹窗，继续校验删除结果")
+	return nil
+}
+
+// findVisibleElementByText 查找文本完全匹配的可见元素
+func findVisibleElementByText(page *rod.Page, selector, text string) (*rod.Element, error) {
+	elems, err := page.Elements(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, elem := range elems {
+		t, err := elem.Text()
+		if err != nil || strings.TrimSpace(t) != text {
+			continue
+		}
+		if !isElementVisible(elem) {
+			continue
+		}
+		return elem, nil
+	}
+
+	return nil, errors.Errorf("没有找到文本为 %s 的元素", text)
+}
+
+// getCurrentUserID 从 __INITIAL_STATE__ 中读取当前登录用户的 ID
+func getCurrentUserID(page *rod.Page) string {
+	result, err := page.Eval(`() => {
+		if (window.__INITIAL_STATE__ &&
+		    window.__INITIAL_STATE__.user &&
+		    window.__INITIAL_STATE__.user.userInfo) {
+			const userInfo = window.__INITIAL_STATE__.user.userInfo;
+			const data = userInfo.value !== undefined ? userInfo.value : userInfo._value;
+			if (data && data.userId) {
+				return data.userId;
+			}
+		}
+		return "";
+	}`)
+	if err != nil {
+		logrus.Warnf("获取当前用户ID失败: %v", err)
+		return ""
+	}
+	return result.Value.String()
+}
@@ -0,0 +1,17 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCommentOwner(t *testing.T) {
+	require.NoError(t, checkCommentOwner(&DeletedComment{CommentID: "c1", UserID: "u1"}, "u1"))
+
+	require.ErrorContains(t, checkCommentOwner(&DeletedComment{CommentID: "c1", UserID: "u2"}, "u1"), "不是当前账号")
+
+	// 读取不到评论作者或当前用户时不能确认归属
+	require.ErrorContains(t, checkCommentOwner(&DeletedComment{CommentID: "c1"}, "u1"), "未能读取评论 c1 的作者")
+	require.Error(t, checkCommentOwner(&DeletedComment{CommentID: "c1", UserID: "u1"}, ""))
+}
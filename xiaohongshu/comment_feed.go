@@ -2,9 +2,13 @@ package xiaohongshu
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -33,18 +37,245 @@ func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken,
 
 	time.Sleep(1 * time.Second)
 
-	elem := page.MustElement("div.input-box div.content-edit span")
+	elem := page.MustElement(selectorCommentInputTrigger)
 	elem.MustClick()
 
-	elem2 := page.MustElement("div.input-box div.content-edit p.content-input")
+	elem2 := page.MustElement(selectorCommentInput)
 	elem2.MustInput(content)
 
 	time.Sleep(1 * time.Second)
 
-	submitButton := page.MustElement("div.bottom button.submit")
+	submitButton := page.MustElement(selectorCommentSubmit)
 	submitButton.MustClick()
 
 	time.Sleep(1 * time.Second)
 
 	return nil
 }
+
+// 评论区选择器
+const (
+	selectorCommentInputTrigger = "div.input-box div.content-edit span"
+	selectorCommentInput        = "div.input-box div.content-edit p.content-input"
+	selectorCommentSubmit       = "div.bottom button.submit"
+	selectorCommentScroller     = ".note-scroller"
+	selectorCommentEnd          = ".comments-container .end-container"
+	selectorCommentShowMore     = ".comments-container .show-more"
+	selectorCommentLike         = ".interactions .like .like-wrapper"
+	selectorCommentReply        = ".interactions .reply"
+)
+
+// maxCommentScrolls 查找评论时最多滚动加载的次数
+const maxCommentScrolls = 30
+
+// ReplyToComment 回复指定评论，并确认回复已出现在该评论的回复列表中
+func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToken, commentID, content string) error {
+	page := f.page.Context(ctx).Timeout(120 * time.Second)
+
+	url := makeFeedDetailURL(feedID, xsecToken)
+	logrus.Infof("Opening feed detail page to reply comment %s: %s", commentID, url)
+
+	page.MustNavigate(url)
+	page.MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	commentElem, err := findCommentElement(page, commentID)
+	if err != nil {
+		return err
+	}
+
+	replyButton, err := commentElem.Element(selectorCommentReply)
+	if err != nil {
+		return errors.Wrapf(err, "没有找到评论 %s 的回复按钮", commentID)
+	}
+	replyButton.MustClick()
+	time.Sleep(1 * time.Second)
+
+	inputElem := page.MustElement(selectorCommentInput)
+	inputElem.MustInput(content)
+	time.Sleep(1 * time.Second)
+
+	page.MustElement(selectorCommentSubmit).MustClick()
+	time.Sleep(2 * time.Second)
+
+	if !hasReplyUnderComment(page, commentID, content) {
+		// 回复列表可能被折叠，展开后再确认一次
+		expandCommentReplies(page)
+		time.Sleep(1 * time.Second)
+
+		if !hasReplyUnderComment(page, commentID, content) {
+			return errors.Errorf("回复评论 %s 后未在回复列表中找到该回复", commentID)
+		}
+	}
+
+	logrus.Infof("feed %s 回复评论 %s 成功", feedID, commentID)
+	return nil
+}
+
+// LikeComment 点赞指定评论，如果已点赞则直接返回
+func (f *CommentFeedAction) LikeComment(ctx context.Context, feedID, xsecToken, commentID string) error {
+	return f.performCommentLike(ctx, feedID, xsecToken, commentID, true)
+}
+
+// UnlikeComment 取消点赞指定评论，如果未点赞则直接返回
+func (f *CommentFeedAction) UnlikeComment(ctx context.Context, feedID, xsecToken, commentID string) error {
+	return f.performCommentLike(ctx, feedID, xsecToken, commentID, false)
+}
+
+func (f *CommentFeedAction) performCommentLike(ctx context.Context, feedID, xsecToken, commentID string, targetLiked bool) error {
+	actionType := actionLike
+	if !targetLiked {
+		actionType = actionUnlike
+	}
+
+	page := f.page.Context(ctx).Timeout(120 * time.Second)
+
+	url := makeFeedDetailURL(feedID, xsecToken)
+	logrus.Infof("Opening feed detail page for comment %s %s: %s", commentID, actionType, url)
+
+	page.MustNavigate(url)
+	page.MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	commentElem, err := findCommentElement(page, commentID)
+	if err != nil {
+		return err
+	}
+
+	likeElem, err := commentElem.Element(selectorCommentLike)
+	if err != nil {
+		return errors.Wrapf(err, "没有找到评论 %s 的点赞按钮", commentID)
+	}
+
+	if isCommentLiked(likeElem) == targetLiked {
+		logrus.Infof("comment %s already in target state (%s), skip clicking", commentID, actionType)
+		return nil
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		likeElem.MustClick()
+		time.Sleep(2 * time.Second)
+
+		if isCommentLiked(likeElem) == targetLiked {
+			logrus.Infof("comment %s %s成功", commentID, actionType)
+			return nil
+		}
+
+		logrus.Warnf("comment %s 第%d次%s后状态未变化", commentID, attempt, actionType)
+	}
+
+	return errors.Errorf("评论 %s %s失败，状态未变化", commentID, actionType)
+}
+
+// findCommentElement 在详情页中查找指定评论，必要时滚动加载更多评论并展开回复
+func findCommentElement(page *rod.Page, commentID string) (*rod.Element, error) {
+	selector := fmt.Sprintf("#comment-%s", commentID)
+
+	elem := scrollFindComment(page, func() *rod.Element {
+		if has, elem, _ := page.Has(selector); has {
+			return elem
+		}
+		return nil
+	})
+	if elem == nil {
+		return nil, errors.Errorf("没有找到评论 %s", commentID)
+	}
+
+	return elem, nil
+}
+
+// scrollFindComment 反复执行 find，找不到时展开回复或滚动加载更多评论，直到找到或评论加载完毕
+func scrollFindComment(page *rod.Page, find func() *rod.Element) *rod.Element {
+	for i := 0; i < maxCommentScrolls; i++ {
+		if elem := find(); elem != nil {
+			elem.MustScrollIntoView()
+			time.Sleep(500 * time.Millisecond)
+			return elem
+		}
+
+		// 子评论默认折叠，先展开再查找
+		if expandCommentReplies(page) > 0 {
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		if reachedCommentEnd(page) {
+			break
+		}
+
+		page.MustEval(`(selector) => {
+			const scroller = document.querySelector(selector);
+			if (scroller) {
+				scroller.scrollTop = scroller.scrollHeight;
+			}
+		}`, selectorCommentScroller)
+		time.Sleep(1 * time.Second)
+	}
+
+	// 评论加载完毕后最后再找一次
+	elem := find()
+	if elem != nil {
+		elem.MustScrollIntoView()
+	}
+	return elem
+}
+
+// expandCommentReplies 点击所有“展开回复”按钮，返回点击的数量
+func expandCommentReplies(page *rod.Page) int {
+	elems, err := page.Elements(selectorCommentShowMore)
+	if err != nil {
+		return 0
+	}
+
+	clicked := 0
+	for _, elem := range elems {
+		if !isElementVisible(elem) {
+			continue
+		}
+		if err := elem.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			logrus.Debugf("展开评论回复失败: %v", err)
+			continue
+		}
+		clicked++
+	}
+
+	return clicked
+}
+
+// reachedCommentEnd 评论是否已全部加载
+func reachedCommentEnd(page *rod.Page) bool {
+	has, _, err := page.Has(selectorCommentEnd)
+	return err == nil && has
+}
+
+// isCommentLiked 根据点赞按钮样式判断评论是否已点赞
+func isCommentLiked(likeElem *rod.Element) bool {
+	cls, err := likeElem.Attribute("class")
+	if err != nil || cls == nil {
+		return false
+	}
+	return strings.Contains(*cls, "like-active")
+}
+
+// hasReplyUnderComment 检查评论所在的楼层中是否出现了指定内容的回复
+func hasReplyUnderComment(page *rod.Page, commentID, content string) bool {
+	result, err := page.Eval(`(commentID, content) => {
+		const comment = document.getElementById("comment-" + commentID);
+		if (!comment) {
+			return false;
+		}
+		const thread = comment.closest(".parent-comment") || comment.parentElement;
+		const replies = thread.querySelectorAll(".comment-item-sub .content");
+		for (const reply of replies) {
+			if (reply.innerText.includes(content)) {
+				return true;
+			}
+		}
+		return false;
+	}`, commentID, strings.TrimSpace(content))
+	if err != nil {
+		logrus.Warnf("检查回复结果失败: %v", err)
+		return false
+	}
+	return result.Value.Bool()
+}
@@ -0,0 +1,438 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	urlOfNoteManager = `https://creator.xiaohongshu.com/new/note-manager`
+)
+
+// 创作中心笔记审核状态
+const (
+	NoteStatusAll       = "全部"
+	NoteStatusReviewing = "审核中"
+	NoteStatusPublished = "已发布"
+	NoteStatusRejected  = "未通过"
+)
+
+// noteStatusTabs 笔记状态与笔记管理页 TAB 文本的对应关系
+var noteStatusTabs = map[string]string{
+	NoteStatusAll:       "全部笔记",
+	NoteStatusPublished: "已发布",
+	NoteStatusReviewing: "审核中",
+	NoteStatusRejected:  "未通过",
+}
+
+// CreatorNote 创作中心中自己发布的笔记
+type CreatorNote struct {
+	NoteID       string `json:"note_id"`
+	Title        string `json:"title"`
+	Status       string `json:"status"` // 审核中/已发布/未通过
+	PublishTime  string `json:"publish_time"`
+	Cover        string `json:"cover,omitempty"`
+	ViewCount    string `json:"view_count,omitempty"`
+	LikeCount    string `json:"like_count,omitempty"`
+	CommentCount string `json:"comment_count,omitempty"`
+	CollectCount string `json:"collect_count,omitempty"`
+}
+
+// EditNoteContent 编辑笔记的内容，空字段表示保持不变
+type EditNoteContent struct {
+	Title   string
+	Content string
+	Tags    []string
+}
+
+// CreatorNoteAction 创作中心笔记管理动作
+type CreatorNoteAction struct {
+	page *rod.Page
+}
+
+// NewCreatorNoteAction 进入创作中心笔记管理页
+func NewCreatorNoteAction(page *rod.Page) *CreatorNoteAction {
+	pp := page.Timeout(120 * time.Second)
+
+	pp.MustNavigate(urlOfNoteManager).MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	return &CreatorNoteAction{page: pp}
+}
+
+// ListNotes 获取自己发布的笔记列表，status 为空时返回全部笔记
+func (c *CreatorNoteAction) ListNotes(ctx context.Context, status string) ([]CreatorNote, error) {
+	page := c.page.Context(ctx)
+
+	if status == "" {
+		status = NoteStatusAll
+	}
+	tabName, ok := noteStatusTabs[status]
+	if !ok {
+		return nil, errors.Errorf("不支持的笔记状态: %s，可选值: 全部|已发布|审核中|未通过", status)
+	}
+
+	if status != NoteStatusAll {
+		if err := clickNoteManagerTab(page, tabName); err != nil {
+			return nil, err
+		}
+	}
+
+	notes, err := extractCreatorNotes(page)
+	if err != nil {
+		return nil, err
+	}
+
+	// 单个状态 TAB 下的笔记可能不显示状态标签，以 TAB 为准
+	if status != NoteStatusAll {
+		for i := range notes {
+			notes[i].Status = status
+		}
+	}
+
+	return notes, nil
+}
+
+// EditNote 编辑自己发布的笔记标题、正文和标签，提交后重新打开编辑页确认修改已保存。
+// 正文末尾的话题标签与正文分开处理：只修改正文时保留原有标签，提供标签时替换原有标签。
+func (c *CreatorNoteAction) EditNote(ctx context.Context, noteID string, content EditNoteContent) error {
+	if content.Title == "" && content.Content == "" && len(content.Tags) == 0 {
+		return errors.New("没有需要修改的内容")
+	}
+
+	page := c.page.Context(ctx)
+
+	if err := openNoteEditor(page, noteID); err != nil {
+		return err
+	}
+
+	if content.Title != "" {
+		titleElem := page.MustElement("div.d-input input")
+		titleElem.MustSelectAllText().MustInput("")
+		titleElem.MustInput(content.Title)
+		time.Sleep(1 * time.Second)
+	}
+
+	contentElem, ok := getContentElement(page)
+	if !ok {
+		return errors.New("没有找到内容输入框")
+	}
+
+	body, tags := splitTrailingTags(contentElem.MustText())
+	if content.Content != "" {
+		body = content.Content
+	}
+	if len(content.Tags) > 0 {
+		tags = content.Tags
+	}
+	if len(tags) >= 10 {
+		logrus.Warnf("标签数量超过10，截取前10个标签")
+		tags = tags[:10]
+	}
+	expected := EditNoteContent{Title: content.Title, Content: body, Tags: tags}
+
+	// 修改正文或标签时重新输入整个正文，避免原有标签被重复追加
+	if content.Content != "" || len(content.Tags) > 0 {
+		clearContentElement(contentElem)
+		contentElem.MustInput(body)
+		inputTags(contentElem, tags)
+	}
+
+	time.Sleep(1 * time.Second)
+
+	submitButton := page.MustElement("div.submit div.d-button-content")
+	submitButton.MustClick()
+
+	time.Sleep(3 * time.Second)
+
+	if err := verifyNoteEdit(page, noteID, expected); err != nil {
+		return err
+	}
+
+	logrus.Infof("笔记 %s 编辑成功", noteID)
+	return nil
+}
+
+// openNoteEditor 在笔记管理页中打开指定笔记的编辑页
+func openNoteEditor(page *rod.Page, noteID string) error {
+	card, err := findCreatorNoteCard(page, noteID)
+	if err != nil {
+		return err
+	}
+
+	if err := clickNoteCardAction(page, card, "编辑"); err != nil {
+		return err
+	}
+
+	page.MustWaitLoad().MustWaitDOMStable()
+	time.Sleep(2 * time.Second)
+	return nil
+}
+
+// verifyNoteEdit 回到笔记管理页重新打开编辑页，确认标题、正文和标签已保存
+func verifyNoteEdit(page *rod.Page, noteID string, expected EditNoteContent) error {
+	page.MustNavigate(urlOfNoteManager).MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	if err := openNoteEditor(page, noteID); err != nil {
+		return errors.Wrap(err, "提交后重新打开笔记失败，无法确认修改是否保存")
+	}
+
+	title := page.MustElement("div.d-input input").MustProperty("value").String()
+	contentElem, ok := getContentElement(page)
+	if !ok {
+		return errors.New("提交后没有找到内容输入框，无法确认修改是否保存")
+	}
+	body, tags := splitTrailingTags(contentElem.MustText())
+
+	if expected.Title != "" && strings.TrimSpace(title) != strings.TrimSpace(expected.Title) {
+		return errors.Errorf("笔记标题未保存，当前标题: %s", title)
+	}
+	if !sameText(body, expected.Content) {
+		return errors.New("笔记正文未保存，请在创作中心确认")
+	}
+	if !sameTags(tags, expected.Tags) {
+		return errors.Errorf("笔记标签未保存，当前标签: %s", strings.Join(tags, " "))
+	}
+	return nil
+}
+
+// topicTagPattern 正文中的话题标签：编辑器中显示为 #名称，笔记描述中为 #名称[话题]#
+var topicTagPattern = regexp.MustCompile(`#([^\s\x{00a0}#\[\]]+)(?:\[话题\]#)?`)
+
+// splitTrailingTags 拆出正文末尾只包含话题标签的行，返回去掉这些行的正文和标签名称
+func splitTrailingTags(text string) (string, []string) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var tags []string
+	for len(lines) > 0 {
+		line := strings.TrimSpace(lines[len(lines)-1])
+		if line != "" {
+			if strings.TrimSpace(topicTagPattern.ReplaceAllString(line, "")) != "" {
+				break
+			}
+			var lineTags []string
+			for _, m := range topicTagPattern.FindAllStringSubmatch(line, -1) {
+				lineTags = append(lineTags, m[1])
+			}
+			tags = append(lineTags, tags...)
+		}
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.TrimRightFunc(strings.Join(lines, "\n"), unicode.IsSpace), tags
+}
+
+// sameText 忽略空白差异比较正文
+func sameText(a, b string) bool {
+	return strings.Join(strings.Fields(a), " ") == strings.Join(strings.Fields(b), " ")
+}
+
+// sameTags 比较标签，忽略 # 前缀和顺序。标签联想可能选中包含该名称的话题，如 美食 → 美食分享，
+// 因此每个期望的标签只需被一个实际标签包含；数量不同说明有标签缺失或重复
+func sameTags(actual, expected []string) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+	used := make([]bool, len(actual))
+	for _, tag := range expected {
+		tag = strings.TrimSpace(strings.TrimLeft(tag, "#"))
+		found := false
+		for i, a := range actual {
+			if !used[i] && strings.Contains(strings.TrimLeft(a, "#"), tag) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// DeleteNote 删除自己发布的笔记，并确认其已从列表中消失
+func (c *CreatorNoteAction) DeleteNote(ctx context.Context, noteID string) (*CreatorNote, error) {
+	page := c.page.Context(ctx)
+
+	notes, err := extractCreatorNotes(page)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted *CreatorNote
+	for i := range notes {
+		if notes[i].NoteID == noteID {
+			deleted = &notes[i]
+			break
+		}
+	}
+
+	card, err := findCreatorNoteCard(page, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clickNoteCardAction(page, card, "删除"); err != nil {
+		return nil, err
+	}
+
+	if err := confirmDeleteDialog(page); err != nil {
+		return nil, err
+	}
+
+	time.Sleep(2 * time.Second)
+
+	if _, err := findCreatorNoteCard(page, noteID); err == nil {
+		return nil, errors.Errorf("删除笔记 %s 后笔记仍然存在", noteID)
+	}
+
+	if deleted == nil {
+		deleted = &CreatorNote{NoteID: noteID}
+	}
+
+	logrus.Infof("笔记 %s 删除成功", noteID)
+	return deleted, nil
+}
+
+// clickNoteManagerTab 切换笔记管理页的状态 TAB
+func clickNoteManagerTab(page *rod.Page, tabName string) error {
+	tab, err := findVisibleElementByText(page, ".tabs .tab, .d-tabs-header .d-tab, .note-manager-tabs span", tabName)
+	if err != nil {
+		return errors.Wrapf(err, "没有找到笔记状态 TAB - %s", tabName)
+	}
+
+	tab.MustClick()
+	page.MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	return nil
+}
+
+// extractCreatorNotes 从笔记管理页中提取笔记列表
+func extractCreatorNotes(page *rod.Page) ([]CreatorNote, error) {
+	result := page.MustEval(`() => {
+		const notes = [];
+		const cards = document.querySelectorAll("div.note[data-impression]");
+		for (const card of cards) {
+			let noteId = "";
+			try {
+				const impression = JSON.parse(card.getAttribute("data-impression"));
+				noteId = impression.noteTarget.value.noteId || impression.noteTarget.noteId || "";
+			} catch (e) {}
+			if (!noteId) {
+				continue;
+			}
+
+			const text = (selector) => {
+				const el = card.querySelector(selector);
+				return el ? el.innerText.trim() : "";
+			};
+			const icons = Array.from(card.querySelectorAll(".icon_list .icon")).map(el => el.innerText.trim());
+
+			let status = "已发布";
+			const cardText = card.innerText;
+			if (cardText.includes("审核中")) {
+				status = "审核中";
+			} else if (cardText.includes("未通过")) {
+				status = "未通过";
+			}
+
+			const cover = card.querySelector("img");
+			notes.push({
+				note_id: noteId,
+				title: text(".info .title"),
+				status: status,
+				publish_time: text(".info .time").replace(/^发布于\s*/, ""),
+				cover: cover ? cover.src : "",
+				view_count: icons[0] || "",
+				comment_count: icons[1] || "",
+				like_count: icons[2] || "",
+				collect_count: icons[3] || "",
+			});
+		}
+		return JSON.stringify(notes);
+	}`).String()
+
+	var notes []CreatorNote
+	if err := json.Unmarshal([]byte(result), &notes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal creator notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// noteIDPattern 笔记 ID 只包含字母和数字
+var noteIDPattern = regexp.MustCompile(`^[0-9a-zA-Z]+$`)
+
+// creatorNoteCardSelector 生成笔记卡片的选择器，笔记 ID 会拼入选择器，需先校验格式
+func creatorNoteCardSelector(noteID string) (string, error) {
+	if !noteIDPattern.MatchString(noteID) {
+		return "", errors.Errorf("无效的笔记 ID: %q", noteID)
+	}
+	return fmt.Sprintf(`div.note[data-impression*="%s"]`, noteID), nil
+}
+
+// findCreatorNoteCard 在笔记管理页中查找指定笔记的卡片
+func findCreatorNoteCard(page *rod.Page, noteID string) (*rod.Element, error) {
+	selector, err := creatorNoteCardSelector(noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	has, card, err := page.Has(selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "查找笔记卡片失败")
+	}
+	if !has {
+		return nil, errors.Errorf("笔记管理页中没有找到笔记 %s", noteID)
+	}
+
+	return card, nil
+}
+
+// clickNoteCardAction 点击笔记卡片上的操作按钮（编辑/删除等）
+func clickNoteCardAction(page *rod.Page, card *rod.Element, actionText string) error {
+	card.MustScrollIntoView()
+	card.MustHover()
+	time.Sleep(500 * time.Millisecond)
+
+	elems, err := card.Elements(".control span, .control div, .operations span, button")
+	if err != nil {
+		return errors.Wrapf(err, "没有找到笔记的%s按钮", actionText)
+	}
+
+	for _, elem := range elems {
+		text, err := elem.Text()
+		if err != nil || strings.TrimSpace(text) != actionText {
+			continue
+		}
+		elem.MustClick()
+		time.Sleep(1 * time.Second)
+		return nil
+	}
+
+	return errors.Errorf("没有找到笔记的%s按钮", actionText)
+}
+
+// clearContentElement 清空正文编辑器
+func clearContentElement(contentElem *rod.Element) {
+	contentElem.MustClick()
+	contentElem.MustKeyActions().
+		Press(input.ControlLeft).
+		Type(input.KeyA).
+		Release(input.ControlLeft).
+		Type(input.Backspace).
+		MustDo()
+	time.Sleep(500 * time.Millisecond)
+}
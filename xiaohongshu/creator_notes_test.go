@@ -0,0 +1,41 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTrailingTags(t *testing.T) {
+	body, tags := splitTrailingTags("今天去了#海边 玩\n\n#旅行 #周末[话题]#\n#海边 \n")
+	require.Equal(t, "今天去了#海边 玩", body)
+	require.Equal(t, []string{"旅行", "周末", "海边"}, tags)
+
+	// 没有标签
+	body, tags = splitTrailingTags("正文第一行\n正文第二行")
+	require.Equal(t, "正文第一行\n正文第二行", body)
+	require.Empty(t, tags)
+
+	// 只有标签
+	body, tags = splitTrailingTags("#美食 #探店")
+	require.Empty(t, body)
+	require.Equal(t, []string{"美食", "探店"}, tags)
+}
+
+func TestSameTags(t *testing.T) {
+	require.True(t, sameTags([]string{"旅行", "美食分享"}, []string{"#美食", "旅行"}))
+	require.False(t, sameTags([]string{"旅行", "旅行", "美食"}, []string{"旅行", "美食"}), "重复的标签")
+	require.False(t, sameTags([]string{"旅行"}, []string{"旅行", "美食"}), "缺少标签")
+	require.False(t, sameTags([]string{"旅行", "穿搭"}, []string{"旅行", "美食"}))
+}
+
+func TestCreatorNoteCardSelector(t *testing.T) {
+	selector, err := creatorNoteCardSelector("64f1a2b3c4d5e6f7a8b9c0d1")
+	require.NoError(t, err)
+	require.Equal(t, `div.note[data-impression*="64f1a2b3c4d5e6f7a8b9c0d1"]`, selector)
+
+	for _, id := range []string{"", `abc"]`, "abc def", `a"],div[x="`} {
+		_, err := creatorNoteCardSelector(id)
+		require.Error(t, err, id)
+	}
+}
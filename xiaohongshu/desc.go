@@ -0,0 +1,189 @@
+package xiaohongshu
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 笔记描述中的标记类型
+const (
+	SpanTopic   = "topic"
+	SpanMention = "mention"
+	SpanEmoji   = "emoji"
+)
+
+// descMarkupPattern 匹配描述中的标记：话题 #名称[话题]#、表情 [名称R]、提及 @昵称。
+// 表情只是候选，[HR]、[ASMR] 等不在 emojiCodes 中的按普通文本处理
+var descMarkupPattern = regexp.MustCompile(`#([^#\[\]\n]+?)\[话题\]#|\[([^\[\]\s]{1,8}[RH])\]|@([^\s@#\[\]，。！？、；：,!?;:]+)`)
+
+// emojiCodes 小红书表情代码与近似的 Unicode emoji，Unicode emoji 不重复以便反向转换。
+// 只收录已在平台上确认可显示的代码，未确认的代码发布后会以原文显示
+var emojiCodes = map[string]string{
+	"微笑R":   "🙂",
+	"害羞R":   "☺️",
+	"失望R":   "😞",
+	"汗颜R":   "😓",
+	"哇R":    "😮",
+	"喝奶茶R":  "🧋",
+	"自拍R":   "🤳",
+	"偷笑R":   "🤭",
+	"飞吻R":   "😘",
+	"石化R":   "😨",
+	"笑哭R":   "😂",
+	"赞R":    "👍",
+	"暗中观察R": "👀",
+	"买爆R":   "🛍️",
+	"大笑R":   "😆",
+	"色色R":   "😍",
+	"生气R":   "😠",
+	"哭惹R":   "😭",
+	"萌萌哒R":  "🥰",
+	"斜眼R":   "😒",
+	"可怜R":   "🥺",
+	"鄙视R":   "😤",
+	"皱眉R":   "😣",
+	"抓狂R":   "😫",
+	"派对R":   "🥳",
+	"吧唧R":   "😋",
+	"惊恐R":   "😱",
+	"再见R":   "👋",
+	"叹气R":   "😮‍💨",
+	"睡觉R":   "😴",
+	"得意R":   "😏",
+	"吃瓜R":   "🍉",
+	"黑薯问号R": "❓",
+	"黄金薯R":  "🍠",
+	"吐舌头H":  "😛",
+}
+
+var (
+	emojiDecoder = newEmojiReplacer(false)
+	emojiEncoder = newEmojiReplacer(true)
+)
+
+// DescSpan 描述中的一处标记。Start/End 为在解析后纯文本中的字符（rune）偏移，左闭右开
+type DescSpan struct {
+	Type  string `json:"type"` // topic/mention/emoji
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Name  string `json:"name"`            // 话题名、用户昵称或表情代码名（如 笑哭R）
+	Raw   string `json:"raw"`             // 原始标记，如 #旅行[话题]#、[笑哭R]
+	Emoji string `json:"emoji,omitempty"` // 表情对应的 Unicode emoji
+}
+
+// ParsedDesc 解析后的笔记描述
+type ParsedDesc struct {
+	// Text 纯文本：话题转为 #名称，已知表情代码转为 Unicode emoji（其余方括号内容原样保留），提及保持 @昵称
+	Text     string     `json:"text"`
+	Topics   []string   `json:"topics,omitempty"`   // 话题，去重后按出现顺序
+	Mentions []string   `json:"mentions,omitempty"` // 提及的用户昵称，去重后按出现顺序
+	Spans    []DescSpan `json:"spans,omitempty"`
+}
+
+// ParseDesc 解析笔记描述中的话题、提及和表情代码
+func ParseDesc(desc string) *ParsedDesc {
+	var (
+		parsed = &ParsedDesc{}
+		text   strings.Builder
+		offset int // 已写入 text 的字符数
+		last   int // desc 中已处理到的位置
+		seen   = make(map[string]bool)
+	)
+	write := func(s string) {
+		text.WriteString(s)
+		offset += utf8.RuneCountInString(s)
+	}
+
+	for _, m := range descMarkupPattern.FindAllStringSubmatchIndex(desc, -1) {
+		raw := desc[m[0]:m[1]]
+		span := DescSpan{Raw: raw}
+		replacement := raw
+
+		switch {
+		case m[2] >= 0:
+			span.Type = SpanTopic
+			span.Name = strings.TrimSpace(desc[m[2]:m[3]])
+			replacement = "#" + span.Name
+		case m[4] >= 0:
+			emoji, ok := emojiCodes[desc[m[4]:m[5]]]
+			if !ok {
+				continue
+			}
+			span.Type = SpanEmoji
+			span.Name = desc[m[4]:m[5]]
+			span.Emoji = emoji
+			replacement = emoji
+		default:
+			// 邮箱等 @ 前紧跟字母数字的不是提及
+			if r, _ := utf8.DecodeLastRuneInString(desc[:m[0]]); r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				continue
+			}
+			span.Type = SpanMention
+			span.Name = desc[m[6]:m[7]]
+		}
+
+		write(desc[last:m[0]])
+		span.Start = offset
+		write(replacement)
+		span.End = offset
+		last = m[1]
+
+		parsed.Spans = append(parsed.Spans, span)
+		if key := span.Type + ":" + span.Name; !seen[key] {
+			seen[key] = true
+			switch span.Type {
+			case SpanTopic:
+				parsed.Topics = append(parsed.Topics, span.Name)
+			case SpanMention:
+				parsed.Mentions = append(parsed.Mentions, span.Name)
+			}
+		}
+	}
+	write(desc[last:])
+
+	parsed.Text = text.String()
+	return parsed
+}
+
+// DecodeEmoji 将小红书表情代码转为 Unicode emoji，如 [笑哭R] → 😂，没有对应的代码保持不变
+func DecodeEmoji(text string) string {
+	return emojiDecoder.Replace(text)
+}
+
+// EncodeEmoji 将 Unicode emoji 转为小红书表情代码，如 😂 → [笑哭R]，发布后显示为小红书表情
+func EncodeEmoji(text string) string {
+	return emojiEncoder.Replace(text)
+}
+
+// newEmojiReplacer 创建表情代码和 Unicode emoji 的替换器。
+// 编码时较长的 emoji 优先匹配（如 😮‍💨 先于 😮），并兼容省略变体选择符的写法（如 ❤）
+func newEmojiReplacer(encode bool) *strings.Replacer {
+	codes := make([]string, 0, len(emojiCodes))
+	for code := range emojiCodes {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		ei, ej := emojiCodes[codes[i]], emojiCodes[codes[j]]
+		if len(ei) != len(ej) {
+			return len(ei) > len(ej)
+		}
+		return codes[i] < codes[j]
+	})
+
+	var pairs []string
+	for _, code := range codes {
+		emoji := emojiCodes[code]
+		if !encode {
+			pairs = append(pairs, "["+code+"]", emoji)
+			continue
+		}
+		pairs = append(pairs, emoji, "["+code+"]")
+		if bare := strings.TrimSuffix(emoji, "\ufe0f"); bare != emoji {
+			pairs = append(pairs, bare, "["+code+"]")
+		}
+	}
+	return strings.NewReplacer(pairs...)
+}
@@ -0,0 +1,54 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDesc(t *testing.T) {
+	desc := "周末去杭州[笑哭R] 和@小红薯 一起\n#旅行[话题]# #杭州[话题]##旅行[话题]# [新表情R][HR][ASMR] 联系 me@example.com"
+
+	parsed := ParseDesc(desc)
+
+	require.Equal(t, "周末去杭州😂 和@小红薯 一起\n#旅行 #杭州#旅行 [新表情R][HR][ASMR] 联系 me@example.com", parsed.Text)
+	require.Equal(t, []string{"旅行", "杭州"}, parsed.Topics)
+	require.Equal(t, []string{"小红薯"}, parsed.Mentions)
+
+	require.Equal(t, []DescSpan{
+		{Type: SpanEmoji, Start: 5, End: 6, Name: "笑哭R", Raw: "[笑哭R]", Emoji: "😂"},
+		{Type: SpanMention, Start: 8, End: 12, Name: "小红薯", Raw: "@小红薯"},
+		{Type: SpanTopic, Start: 16, End: 19, Name: "旅行", Raw: "#旅行[话题]#"},
+		{Type: SpanTopic, Start: 20, End: 23, Name: "杭州", Raw: "#杭州[话题]#"},
+		{Type: SpanTopic, Start: 23, End: 26, Name: "旅行", Raw: "#旅行[话题]#"},
+	}, parsed.Spans, "未收录的方括号内容不是表情")
+
+	// 偏移按字符计算，可以直接截取纯文本
+	runes := []rune(parsed.Text)
+	for _, span := range parsed.Spans {
+		text := string(runes[span.Start:span.End])
+		switch span.Type {
+		case SpanTopic:
+			require.Equal(t, "#"+span.Name, text)
+		case SpanMention:
+			require.Equal(t, "@"+span.Name, text)
+		}
+	}
+
+	empty := ParseDesc("没有标记")
+	require.Equal(t, "没有标记", empty.Text)
+	require.Empty(t, empty.Spans)
+}
+
+func TestEmojiCodes(t *testing.T) {
+	require.Equal(t, "好耶😂👍 [未知R]", DecodeEmoji("好耶[笑哭R][赞R] [未知R]"))
+	require.Equal(t, "好耶[笑哭R][赞R]", EncodeEmoji("好耶😂👍"))
+
+	// 较长的 emoji 优先，省略变体选择符也能识别
+	require.Equal(t, "[叹气R][哇R]", EncodeEmoji("😮‍💨😮"))
+	require.Equal(t, "[害羞R][害羞R]", EncodeEmoji("☺️☺"))
+
+	for code, emoji := range emojiCodes {
+		require.Equal(t, emoji, DecodeEmoji(EncodeEmoji(emoji)), code)
+	}
+}
@@ -63,6 +63,8 @@ func (f *FeedDetailAction) GetFeedDetail(ctx context.Context, feedID, xsecToken
 		return nil, fmt.Errorf("feed %s not found in noteDetailMap", feedID)
 	}
 
+	noteDetail.Note.DescParsed = ParseDesc(noteDetail.Note.Desc)
+
 	return &FeedDetailResponse{
 		Note:     noteDetail.Note,
 		Comments: noteDetail.Comments,
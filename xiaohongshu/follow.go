@@ -0,0 +1,139 @@
+package xiaohongshu
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// SelectorFollowButton 用户主页的关注按钮
+const SelectorFollowButton = ".user-info .follow-button, .info-part .follow-button, button.follow-button"
+
+const (
+	actionFollow   interactActionType = "关注"
+	actionUnfollow interactActionType = "取消关注"
+)
+
+// FollowAction 负责处理关注相关交互
+type FollowAction struct {
+	page *rod.Page
+}
+
+func NewFollowAction(page *rod.Page) *FollowAction {
+	return &FollowAction{page: page}
+}
+
+// Follow 关注指定用户，如果已关注则直接返回
+func (a *FollowAction) Follow(ctx context.Context, userID, xsecToken string) error {
+	return a.perform(ctx, userID, xsecToken, true)
+}
+
+// Unfollow 取消关注指定用户，如果未关注则直接返回
+func (a *FollowAction) Unfollow(ctx context.Context, userID, xsecToken string) error {
+	return a.perform(ctx, userID, xsecToken, false)
+}
+
+func (a *FollowAction) perform(ctx context.Context, userID, xsecToken string, targetFollowed bool) error {
+	actionType := actionFollow
+	if !targetFollowed {
+		actionType = actionUnfollow
+	}
+
+	page := a.page.Context(ctx).Timeout(60 * time.Second)
+	url := makeUserProfileURL(userID, xsecToken)
+	logrus.Infof("Opening user profile page for %s: %s", actionType, url)
+
+	page.MustNavigate(url)
+	page.MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	followed, err := a.getFollowState(page)
+	if err != nil {
+		return err
+	}
+
+	if targetFollowed && followed {
+		logrus.Infof("user %s already followed, skip clicking", userID)
+		return nil
+	}
+	if !targetFollowed && !followed {
+		logrus.Infof("user %s not followed yet, skip clicking", userID)
+		return nil
+	}
+
+	return a.toggleFollow(page, userID, targetFollowed, actionType)
+}
+
+func (a *FollowAction) toggleFollow(page *rod.Page, userID string, targetFollowed bool, actionType interactActionType) error {
+	a.clickFollowButton(page, targetFollowed)
+	time.Sleep(3 * time.Second)
+
+	followed, err := a.getFollowState(page)
+	if err != nil {
+		return errors.Wrapf(err, "验证%s状态失败", actionType)
+	}
+	if followed == targetFollowed {
+		logrus.Infof("user %s %s成功", userID, actionType)
+		return nil
+	}
+
+	logrus.Warnf("user %s %s可能未成功，状态未变化，尝试再次点击", userID, actionType)
+	a.clickFollowButton(page, targetFollowed)
+	time.Sleep(2 * time.Second)
+
+	followed, err = a.getFollowState(page)
+	if err != nil {
+		return errors.Wrapf(err, "第二次验证%s状态失败", actionType)
+	}
+	if followed == targetFollowed {
+		logrus.Infof("user %s 第二次点击%s成功", userID, actionType)
+		return nil
+	}
+
+	return errors.Errorf("user %s %s失败，关注状态未变化", userID, actionType)
+}
+
+// clickFollowButton 点击关注按钮，取消关注时处理二次确认弹窗
+func (a *FollowAction) clickFollowButton(page *rod.Page, targetFollowed bool) {
+	page.MustElement(SelectorFollowButton).MustClick()
+
+	if targetFollowed {
+		return
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	for _, text := range []string{"不再关注", "确定", "确认"} {
+		if btn, err := findVisibleElementByText(page, selectorConfirmButtons, text); err == nil {
+			btn.MustClick()
+			return
+		}
+	}
+}
+
+// getFollowState 根据关注按钮文本判断是否已关注
+func (a *FollowAction) getFollowState(page *rod.Page) (bool, error) {
+	has, btn, err := page.Has(SelectorFollowButton)
+	if err != nil {
+		return false, errors.Wrap(err, "查找关注按钮失败")
+	}
+	if !has {
+		return false, errors.New("没有找到关注按钮，可能是当前账号自己的主页")
+	}
+
+	text, err := btn.Text()
+	if err != nil {
+		return false, errors.Wrap(err, "读取关注按钮文本失败")
+	}
+
+	return isFollowedText(text), nil
+}
+
+// isFollowedText 关注按钮文本为“已关注”或“互相关注”时表示已关注；“关注”“回关”表示未关注
+func isFollowedText(text string) bool {
+	text = strings.TrimSpace(text)
+	return strings.Contains(text, "已关注") || strings.Contains(text, "互相关注")
+}
@@ -15,12 +15,50 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// MaxImages 单篇图文笔记最多上传的图片数量
+const MaxImages = 18
+
 // PublishImageContent 发布图文内容
 type PublishImageContent struct {
-	Title      string
-	Content    string
-	Tags       []string
-	ImagePaths []string
+	Title        string
+	Content      string
+	Tags         []string
+	ImagePaths   []string
+	ScheduleTime *time.Time // 定时发布时间，为空则立即发布
+	Draft        bool       // 仅保存到草稿箱，不发布
+	Visibility   string     // 可见范围，为空则保持默认（公开）
+	Original     bool       // 是否声明原创
+	Declaration  string     // 内容类型声明，如“笔记含AI合成内容”
+	Mentions     []string   // 需要 @ 的用户昵称或小红书号
+	Location     string     // 地点名称
+}
+
+// PublishResult 发布结果
+type PublishResult struct {
+	NoteID             string   // 新笔记 ID，发布接口未返回时为空
+	URL                string   // 新笔记的分享链接
+	XsecToken          string   // 新笔记的 xsec_token
+	ScheduledAt        string   // 平台确认的定时发布时间，立即发布时为空
+	Draft              bool     // 是否保存到了草稿箱
+	Location           string   // 页面显示的已添加地点
+	UnresolvedMentions []string // 没有在 @ 下拉框中找到的用户
+	UnresolvedLocation string   // 没有找到的地点
+}
+
+// publishForm 图文和视频共用的发布表单内容
+type publishForm struct {
+	Title        string
+	Content      string
+	Tags         []string
+	ScheduleTime *time.Time
+	Draft        bool
+	Visibility   string
+	Original     bool
+	Declaration  string
+	Mentions     []string
+	Location     string
+	Cover        *VideoCover // 仅视频
+	DraftsBefore []Draft     // 草稿模式下填写前草稿箱中已有的草稿，用于确认本次保存的草稿
 }
 
 type PublishAction struct {
@@ -50,15 +88,24 @@ func NewPublishImageAction(page *rod.Page) (*PublishAction, error) {
 	}, nil
 }
 
-func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent) error {
+func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent) (*PublishResult, error) {
 	if len(content.ImagePaths) == 0 {
-		return errors.New("图片不能为空")
+		return nil, errors.New("图片不能为空")
 	}
 
 	page := p.page.Context(ctx)
 
+	// 草稿模式先记录草稿箱中已有的草稿，保存后对比确认新增了本次的草稿
+	var draftsBefore []Draft
+	if content.Draft {
+		var err error
+		if draftsBefore, err = snapshotDrafts(page, DraftTypeImage); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := uploadImages(page, content.ImagePaths); err != nil {
-		return errors.Wrap(err, "小红书上传图片失败")
+		return nil, errors.Wrap(err, "小红书上传图片失败")
 	}
 
 	tags := content.Tags
@@ -69,11 +116,24 @@ func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent
 
 	logrus.Infof("发布内容: title=%s, images=%v, tags=%v", content.Title, len(content.ImagePaths), tags)
 
-	if err := submitPublish(page, content.Title, content.Content, tags); err != nil {
-		return errors.Wrap(err, "小红书发布失败")
+	result, err := submitPublish(page, publishForm{
+		Title:        content.Title,
+		Content:      content.Content,
+		Tags:         tags,
+		ScheduleTime: content.ScheduleTime,
+		Draft:        content.Draft,
+		Visibility:   content.Visibility,
+		Original:     content.Original,
+		Declaration:  content.Declaration,
+		Mentions:     content.Mentions,
+		Location:     content.Location,
+		DraftsBefore: draftsBefore,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书发布失败")
 	}
 
-	return nil
+	return result, nil
 }
 
 func removePopCover(page *rod.Page) {
@@ -239,30 +299,60 @@ func waitForUploadComplete(page *rod.Page, expectedCount int) error {
 	return errors.New("上传超时，请检查网络连接和图片大小")
 }
 
-func submitPublish(page *rod.Page, title, content string, tags []string) error {
+func submitPublish(page *rod.Page, form publishForm) (*PublishResult, error) {
 
 	titleElem := page.MustElement("div.d-input input")
-	titleElem.MustInput(title)
+	titleElem.MustInput(form.Title)
 
 	time.Sleep(1 * time.Second)
 
+	result := &PublishResult{}
+
 	if contentElem, ok := getContentElement(page); ok {
-		contentElem.MustInput(content)
+		contentElem.MustInput(form.Content)
 
-		inputTags(contentElem, tags)
+		result.UnresolvedMentions = inputMentions(contentElem, form.Mentions)
+		inputTags(contentElem, form.Tags)
 
 	} else {
-		return errors.New("没有找到内容输入框")
+		return nil, errors.New("没有找到内容输入框")
 	}
 
 	time.Sleep(1 * time.Second)
 
+	if err := applyPublishSettings(page, form, result); err != nil {
+		return nil, err
+	}
+
+	if form.ScheduleTime != nil {
+		scheduledAt, err := setScheduleTime(page, *form.ScheduleTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "设置定时发布失败")
+		}
+		result.ScheduledAt = scheduledAt
+	}
+
+	if form.Draft {
+		if _, err := saveDraft(page, DraftTypeImage, form.Title, form.DraftsBefore); err != nil {
+			return nil, err
+		}
+		result.Draft = true
+		return result, nil
+	}
+
+	watcher := watchPublish(page)
+	defer watcher.stop()
+
 	submitButton := page.MustElement("div.submit div.d-button-content")
 	submitButton.MustClick()
 
-	time.Sleep(3 * time.Second)
+	conf, err := watcher.wait(publishConfirmTimeout)
+	if err != nil {
+		return nil, err
+	}
+	conf.apply(result)
 
-	return nil
+	return result, nil
 }
 
 // 查找内容输入框 - 使用Race方法处理两种样式
@@ -0,0 +1,234 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// publishConfirmTimeout 点击发布后等待发布成功确认的时长
+const publishConfirmTimeout = 60 * time.Second
+
+// ErrPublishUnconfirmed 已点击发布但未能确认发布结果，笔记可能已经发布，不能直接重试
+var ErrPublishUnconfirmed = errors.New("点击发布后未检测到发布成功，笔记可能已发布，请在创作中心确认笔记状态")
+
+// publishAPIPaths 发布笔记接口的路径
+var publishAPIPaths = []string{
+	"/web_api/sns/v2/note",
+	"/api/galaxy/creator/note/publish",
+	"/api/galaxy/v2/creator/note/publish",
+}
+
+// publishConfirmation 发布成功的确认信息
+type publishConfirmation struct {
+	NoteID    string
+	XsecToken string
+}
+
+// publishWatcher 在点击发布前启动，监听发布接口的响应和发布成功页
+type publishWatcher struct {
+	page   *rod.Page
+	cancel context.CancelFunc
+	result chan publishConfirmation
+	errs   chan error
+}
+
+// watchPublish 开始监听发布接口响应，需在点击发布按钮之前调用
+func watchPublish(page *rod.Page) *publishWatcher {
+	if err := (proto.NetworkEnable{}).Call(page); err != nil {
+		logrus.Warnf("开启网络监听失败，将仅通过页面确认发布结果: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(page.GetContext())
+	p := page.Context(ctx)
+
+	w := &publishWatcher{
+		page:   page,
+		cancel: cancel,
+		result: make(chan publishConfirmation, 1),
+		errs:   make(chan error, 1),
+	}
+
+	requests := make(map[proto.NetworkRequestID]bool)
+	wait := p.EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) {
+			if e.Request.Method == "POST" && isPublishAPI(e.Request.URL) {
+				requests[e.RequestID] = true
+			}
+		},
+		func(e *proto.NetworkLoadingFinished) bool {
+			if !requests[e.RequestID] {
+				return false
+			}
+
+			body, err := proto.NetworkGetResponseBody{RequestID: e.RequestID}.Call(p)
+			if err != nil {
+				logrus.Warnf("读取发布接口响应失败: %v", err)
+				return false
+			}
+
+			data := []byte(body.Body)
+			if body.Base64Encoded {
+				if data, err = base64.StdEncoding.DecodeString(body.Body); err != nil {
+					logrus.Warnf("解码发布接口响应失败: %v", err)
+					return false
+				}
+			}
+
+			conf, err := parsePublishResponse(data)
+			if errors.Is(err, ErrPublishUnconfirmed) {
+				// 响应无法解析时不能判断是否发布成功，继续等待发布成功页
+				logrus.Warnf("%v", err)
+				return false
+			}
+			if err != nil {
+				w.errs <- err
+				return true
+			}
+
+			w.result <- *conf
+			return true
+		},
+	)
+	go wait()
+
+	return w
+}
+
+// wait 等待发布接口返回成功或页面跳转到发布成功页。
+// 接口明确返回失败时返回该错误；超时或被取消时无法确认结果，返回 ErrPublishUnconfirmed
+func (w *publishWatcher) wait(timeout time.Duration) (*publishConfirmation, error) {
+	deadline := time.Now().Add(timeout)
+	done := w.page.GetContext().Done()
+
+	for time.Now().Before(deadline) {
+		select {
+		case conf := <-w.result:
+			logrus.Infof("发布接口返回成功: note_id=%s", conf.NoteID)
+			return &conf, nil
+		case err := <-w.errs:
+			return nil, err
+		case <-done:
+			return nil, ErrPublishUnconfirmed
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		if isPublishSuccessPage(w.page) {
+			// 成功页可能先于接口响应被检测到，再给接口一点时间以拿到笔记 ID
+			select {
+			case conf := <-w.result:
+				return &conf, nil
+			case <-time.After(2 * time.Second):
+			}
+
+			logrus.Warn("检测到发布成功页，但未获取到发布接口返回的笔记ID")
+			return &publishConfirmation{}, nil
+		}
+	}
+
+	return nil, ErrPublishUnconfirmed
+}
+
+// stop 停止监听
+func (w *publishWatcher) stop() {
+	w.cancel()
+}
+
+// apply 将确认信息写入发布结果
+func (c *publishConfirmation) apply(result *PublishResult) {
+	result.NoteID = c.NoteID
+	result.XsecToken = c.XsecToken
+	if c.NoteID != "" {
+		result.URL = makeNoteShareURL(c.NoteID, c.XsecToken)
+	}
+}
+
+// isPublishAPI 判断请求是否为发布笔记接口
+func isPublishAPI(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, path := range publishAPIPaths {
+		if u.Path == path || strings.HasPrefix(u.Path, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isPublishSuccessPage 页面是否已跳转到发布成功页或出现发布成功提示
+func isPublishSuccessPage(page *rod.Page) bool {
+	info, err := page.Info()
+	if err == nil && strings.Contains(info.URL, "/publish/success") {
+		return true
+	}
+
+	result, err := page.Eval(`() => {
+		const body = document.body ? document.body.innerText : "";
+		return body.includes("发布成功");
+	}`)
+	if err != nil {
+		return false
+	}
+	return result.Value.Bool()
+}
+
+// parsePublishResponse 解析发布接口的响应。
+// 只有接口明确返回失败时才返回接口给出的错误信息；响应无法解析时返回 ErrPublishUnconfirmed
+func parsePublishResponse(data []byte) (*publishConfirmation, error) {
+	var resp struct {
+		Success *bool           `json:"success"`
+		Code    int             `json:"code"`
+		Msg     string          `json:"msg"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, errors.Wrapf(ErrPublishUnconfirmed, "解析发布接口响应失败: %v", err)
+	}
+
+	if (resp.Success != nil && !*resp.Success) || resp.Code != 0 {
+		msg := resp.Msg
+		if msg == "" {
+			msg = fmt.Sprintf("code=%d", resp.Code)
+		}
+		return nil, errors.Errorf("发布接口返回失败: %s", msg)
+	}
+
+	var fields map[string]any
+	if len(resp.Data) > 0 {
+		_ = json.Unmarshal(resp.Data, &fields)
+	}
+
+	return &publishConfirmation{
+		NoteID:    firstStringField(fields, "id", "note_id", "noteId"),
+		XsecToken: firstStringField(fields, "xsec_token", "xsecToken"),
+	}, nil
+}
+
+// firstStringField 返回第一个存在的非空字符串字段
+func firstStringField(fields map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := fields[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// makeNoteShareURL 生成笔记的分享链接
+func makeNoteShareURL(noteID, xsecToken string) string {
+	if xsecToken == "" {
+		return fmt.Sprintf("https://www.xiaohongshu.com/explore/%s", noteID)
+	}
+	return fmt.Sprintf("https://www.xiaohongshu.com/explore/%s?xsec_token=%s&xsec_source=pc_share", noteID, url.QueryEscape(xsecToken))
+}
@@ -0,0 +1,51 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePublishResponse(t *testing.T) {
+	conf, err := parsePublishResponse([]byte(`{"success":true,"code":0,"data":{"id":"64f1a2b3c4d5e6f7a8b9c0d1","xsec_token":"ABxxx"}}`))
+	require.NoError(t, err)
+	require.Equal(t, "64f1a2b3c4d5e6f7a8b9c0d1", conf.NoteID)
+	require.Equal(t, "ABxxx", conf.XsecToken)
+
+	// 字段名兼容驼峰写法
+	conf, err = parsePublishResponse([]byte(`{"success":true,"data":{"noteId":"abc","xsecToken":"tok"}}`))
+	require.NoError(t, err)
+	require.Equal(t, "abc", conf.NoteID)
+	require.Equal(t, "tok", conf.XsecToken)
+
+	// 成功但未返回笔记信息
+	conf, err = parsePublishResponse([]byte(`{"success":true}`))
+	require.NoError(t, err)
+	require.Empty(t, conf.NoteID)
+
+	_, err = parsePublishResponse([]byte(`{"success":false,"msg":"标题包含敏感词"}`))
+	require.ErrorContains(t, err, "标题包含敏感词")
+
+	_, err = parsePublishResponse([]byte(`{"code":-9102}`))
+	require.ErrorContains(t, err, "code=-9102")
+
+	// 响应无法解析时不能认定发布失败
+	_, err = parsePublishResponse([]byte(`<html></html>`))
+	require.ErrorIs(t, err, ErrPublishUnconfirmed)
+
+	_, err = parsePublishResponse([]byte(`{"success":false,"msg":"标题包含敏感词"}`))
+	require.NotErrorIs(t, err, ErrPublishUnconfirmed)
+}
+
+func TestIsPublishAPI(t *testing.T) {
+	require.True(t, isPublishAPI("https://edith.xiaohongshu.com/web_api/sns/v2/note"))
+	require.True(t, isPublishAPI("https://creator.xiaohongshu.com/api/galaxy/creator/note/publish?t=1"))
+	require.False(t, isPublishAPI("https://edith.xiaohongshu.com/web_api/sns/v2/note_draft_list"))
+	require.False(t, isPublishAPI("https://edith.xiaohongshu.com/api/sns/web/v1/feed"))
+	require.False(t, isPublishAPI("://bad"))
+}
+
+func TestMakeNoteShareURL(t *testing.T) {
+	require.Equal(t, "https://www.xiaohongshu.com/explore/abc", makeNoteShareURL("abc", ""))
+	require.Equal(t, "https://www.xiaohongshu.com/explore/abc?xsec_token=AB%2Bx%3D&xsec_source=pc_share", makeNoteShareURL("abc", "AB+x="))
+}
@@ -0,0 +1,369 @@
+package xiaohongshu
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 草稿类型
+const (
+	DraftTypeImage = "image"
+	DraftTypeVideo = "video"
+)
+
+// draftTabs 草稿类型与草稿箱 TAB 文本的对应关系
+var draftTabs = map[string]string{
+	DraftTypeImage: "图文笔记",
+	DraftTypeVideo: "视频笔记",
+}
+
+// uploadTabs 草稿类型与发布页上传 TAB 文本的对应关系
+var uploadTabs = map[string]string{
+	DraftTypeImage: "上传图文",
+	DraftTypeVideo: "上传视频",
+}
+
+// absoluteDatePattern 带年份的完整日期。“3分钟前”“昨天 12:00”等相对时间会随时间变化，不能用于生成草稿 ID
+var absoluteDatePattern = regexp.MustCompile(`\d{4}[-/.年]\d{1,2}[-/.月]\d{1,2}`)
+
+// 草稿箱相关选择器
+const (
+	selectorDraftItems  = ".draft-list .draft-item, .draft-container .draft-item, .drafts .item"
+	selectorDraftButton = "button, .d-button, .d-button-content, span"
+)
+
+// Draft 创作中心草稿箱中的草稿
+type Draft struct {
+	DraftID string `json:"draft_id"`
+	Type    string `json:"type"` // image/video
+	Title   string `json:"title"`
+	SavedAt string `json:"saved_at"` // 页面展示的保存时间
+	Cover   string `json:"cover,omitempty"`
+}
+
+// DraftAction 草稿箱动作
+type DraftAction struct {
+	page *rod.Page
+}
+
+// NewDraftAction 进入发布页
+func NewDraftAction(page *rod.Page) *DraftAction {
+	pp := page.Timeout(300 * time.Second)
+
+	pp.MustNavigate(urlOfPublic).MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	return &DraftAction{page: pp}
+}
+
+// ListDrafts 获取草稿箱中的图文和视频草稿
+func (d *DraftAction) ListDrafts(ctx context.Context) ([]Draft, error) {
+	page := d.page.Context(ctx)
+
+	if err := openDraftBox(page); err != nil {
+		return nil, err
+	}
+
+	var drafts []Draft
+	for _, draftType := range []string{DraftTypeImage, DraftTypeVideo} {
+		items, err := listDraftsOfType(page, draftType)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, items...)
+	}
+
+	return drafts, nil
+}
+
+// PublishDraft 打开指定草稿并发布，确认发布成功后返回草稿和新笔记信息
+func (d *DraftAction) PublishDraft(ctx context.Context, draftID string) (*Draft, *PublishResult, error) {
+	page := d.page.Context(ctx)
+
+	if err := openDraftBox(page); err != nil {
+		return nil, nil, err
+	}
+
+	for _, draftType := range []string{DraftTypeImage, DraftTypeVideo} {
+		drafts, err := listDraftsOfType(page, draftType)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i, draft := range drafts {
+			if draft.DraftID != draftID {
+				continue
+			}
+
+			if err := clickDraftItemButton(page, i, "编辑"); err != nil {
+				return nil, nil, err
+			}
+
+			page.MustWaitLoad().MustWaitDOMStable()
+			time.Sleep(2 * time.Second)
+
+			result, err := clickPublishButton(page)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			logrus.Infof("草稿 %s 已发布: %s note_id=%s", draftID, draft.Title, result.NoteID)
+			return &draft, result, nil
+		}
+	}
+
+	return nil, nil, errors.Errorf("草稿箱中没有找到草稿 %s", draftID)
+}
+
+// snapshotDrafts 在填写发布内容前读取草稿箱中已有的草稿，读取后回到上传页
+func snapshotDrafts(page *rod.Page, draftType string) ([]Draft, error) {
+	if err := openDraftBox(page); err != nil {
+		return nil, errors.Wrap(err, "打开草稿箱失败，无法确认草稿是否保存")
+	}
+	drafts, err := listDraftsOfType(page, draftType)
+	if err != nil {
+		return nil, err
+	}
+
+	page.MustNavigate(urlOfPublic).MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	if err := mustClickPublishTab(page, uploadTabs[draftType]); err != nil {
+		return nil, errors.Wrapf(err, "切换到%s失败", uploadTabs[draftType])
+	}
+	time.Sleep(1 * time.Second)
+
+	return drafts, nil
+}
+
+// saveDraft 点击“暂存离开”，将已填写的内容保存到草稿箱，
+// 并对比保存前的草稿箱 before，确认新增了该标题的草稿
+func saveDraft(page *rod.Page, draftType, title string, before []Draft) (*Draft, error) {
+	btn, err := findVisibleElementByText(page, selectorDraftButton, "暂存离开")
+	if err != nil {
+		return nil, errors.Wrap(err, "没有找到暂存离开按钮")
+	}
+
+	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return nil, errors.Wrap(err, "点击暂存离开按钮失败")
+	}
+
+	time.Sleep(3 * time.Second)
+
+	// 回到发布页，在草稿箱中确认草稿已保存
+	page.MustNavigate(urlOfPublic).MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	if err := openDraftBox(page); err != nil {
+		return nil, errors.Wrap(err, "打开草稿箱失败，无法确认草稿是否保存")
+	}
+	drafts, err := listDraftsOfType(page, draftType)
+	if err != nil {
+		return nil, err
+	}
+
+	draft, ok := findSavedDraft(before, drafts, title)
+	if !ok {
+		return nil, errors.Errorf("草稿箱中没有找到新保存的草稿: %s", title)
+	}
+
+	logrus.Infof("内容已暂存到草稿箱: %s", draft.Title)
+	return draft, nil
+}
+
+// findSavedDraft 对比保存前后的草稿箱，返回新增的标题匹配的草稿。
+// 草稿箱中已有同名草稿时，只有同名草稿变多才能确认本次保存成功
+func findSavedDraft(before, after []Draft, title string) (*Draft, bool) {
+	matched := filterDraftsByTitle(after, title)
+	existing := filterDraftsByTitle(before, title)
+	if len(matched) <= len(existing) {
+		return nil, false
+	}
+
+	seen := make(map[string]int)
+	for _, d := range existing {
+		seen[d.DraftID]++
+	}
+	for _, d := range matched {
+		if seen[d.DraftID] > 0 {
+			seen[d.DraftID]--
+			continue
+		}
+		return &d, true
+	}
+	return &matched[0], true
+}
+
+// filterDraftsByTitle 按标题筛选草稿，草稿箱中过长的标题会被截断并以省略号结尾
+func filterDraftsByTitle(drafts []Draft, title string) []Draft {
+	title = strings.TrimSpace(title)
+
+	var matched []Draft
+	for _, d := range drafts {
+		shown := strings.TrimSpace(d.Title)
+		if shown == title {
+			matched = append(matched, d)
+			continue
+		}
+		if prefix := strings.TrimRight(shown, ".…"); prefix != shown && prefix != "" && strings.HasPrefix(title, prefix) {
+			matched = append(matched, d)
+		}
+	}
+	return matched
+}
+
+// clickPublishButton 点击编辑页的发布按钮并确认发布成功，兼容图文和视频两种样式
+func clickPublishButton(page *rod.Page) (*PublishResult, error) {
+	watcher := watchPublish(page)
+	defer watcher.stop()
+
+	if has, btn, _ := page.Has("div.submit div.d-button-content"); has {
+		btn.MustClick()
+	} else {
+		btn, err := waitForPublishButtonClickable(page)
+		if err != nil {
+			return nil, err
+		}
+		if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return nil, errors.Wrap(err, "点击发布按钮失败")
+		}
+	}
+
+	conf, err := watcher.wait(publishConfirmTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PublishResult{}
+	conf.apply(result)
+	return result, nil
+}
+
+// openDraftBox 打开发布页上的草稿箱
+func openDraftBox(page *rod.Page) error {
+	entry, err := page.ElementByJS(rod.Eval(`() => {
+		const elems = Array.from(document.querySelectorAll("span, div, a"))
+			.filter(el => el.children.length === 0 && el.innerText.trim().startsWith("草稿箱"));
+		return elems.length > 0 ? elems[0] : null;
+	}`))
+	if err != nil {
+		return errors.Wrap(err, "没有找到草稿箱入口")
+	}
+
+	entry.MustClick()
+	page.MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	return nil
+}
+
+// listDraftsOfType 切换到指定类型的草稿 TAB 并提取草稿列表
+func listDraftsOfType(page *rod.Page, draftType string) ([]Draft, error) {
+	tabName := draftTabs[draftType]
+	if tab, err := findVisibleElementByText(page, ".d-tabs-header .d-tab, .tabs .tab, span, div", tabName); err == nil {
+		tab.MustClick()
+		page.MustWaitDOMStable()
+		time.Sleep(500 * time.Millisecond)
+	} else {
+		logrus.Warnf("没有找到草稿箱 TAB %s，使用当前列表", tabName)
+	}
+
+	result := page.MustEval(`(selector) => {
+		const drafts = [];
+		for (const item of document.querySelectorAll(selector)) {
+			const text = (s) => {
+				const el = item.querySelector(s);
+				return el ? el.innerText.trim() : "";
+			};
+			const cover = item.querySelector("img");
+			drafts.push({
+				draft_id: item.getAttribute("data-id") || item.getAttribute("data-draft-id") || "",
+				title: text(".title, .draft-title"),
+				saved_at: text(".time, .draft-time, .date"),
+				cover: cover ? cover.src : "",
+			});
+		}
+		return JSON.stringify(drafts);
+	}`, selectorDraftItems).String()
+
+	var drafts []Draft
+	if err := json.Unmarshal([]byte(result), &drafts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal drafts: %w", err)
+	}
+
+	// 相同内容生成的 ID 重复时按列表顺序加序号区分
+	counts := make(map[string]int)
+	for i := range drafts {
+		drafts[i].Type = draftType
+		if drafts[i].DraftID != "" {
+			continue
+		}
+		id := makeDraftID(draftType, drafts[i].Title, drafts[i].SavedAt, drafts[i].Cover)
+		if counts[id]++; counts[id] > 1 {
+			id = fmt.Sprintf("%s-%d", id, counts[id])
+		}
+		drafts[i].DraftID = id
+	}
+
+	return drafts, nil
+}
+
+// clickDraftItemButton 点击第 index 个草稿上的操作按钮
+func clickDraftItemButton(page *rod.Page, index int, text string) error {
+	items, err := page.Elements(selectorDraftItems)
+	if err != nil || index >= len(items) {
+		return errors.New("草稿列表已变化，请重新获取草稿列表")
+	}
+
+	item := items[index]
+	item.MustScrollIntoView()
+	item.MustHover()
+	time.Sleep(500 * time.Millisecond)
+
+	buttons, err := item.Elements(selectorDraftButton)
+	if err != nil {
+		return errors.Wrapf(err, "没有找到草稿的%s按钮", text)
+	}
+
+	for _, btn := range buttons {
+		t, err := btn.Text()
+		if err != nil || t != text {
+			continue
+		}
+		btn.MustClick()
+		return nil
+	}
+
+	// 部分版本点击草稿卡片即进入编辑
+	if text == "编辑" {
+		item.MustClick()
+		return nil
+	}
+
+	return errors.Errorf("没有找到草稿的%s按钮", text)
+}
+
+// makeDraftID 草稿箱不展示草稿 ID 时，根据类型、标题、封面和保存日期生成稳定的 ID。
+// 相对时间（如“3分钟前”）和本地临时的封面地址（blob:、data:）会变化，不参与生成
+func makeDraftID(draftType, title, savedAt, cover string) string {
+	if !absoluteDatePattern.MatchString(savedAt) {
+		savedAt = ""
+	}
+	if !strings.HasPrefix(cover, "http://") && !strings.HasPrefix(cover, "https://") {
+		cover = ""
+	}
+
+	sum := sha1.Sum([]byte(draftType + "|" + title + "|" + savedAt + "|" + cover))
+	return hex.EncodeToString(sum[:])[:12]
+}
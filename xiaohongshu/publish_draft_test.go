@@ -0,0 +1,49 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeDraftID(t *testing.T) {
+	id := makeDraftID(DraftTypeImage, "周末去哪玩", "2025-01-02 15:04", "")
+	require.Len(t, id, 12)
+
+	// 相同草稿生成相同 ID
+	require.Equal(t, id, makeDraftID(DraftTypeImage, "周末去哪玩", "2025-01-02 15:04", ""))
+
+	// 类型、标题、保存日期或封面不同则 ID 不同
+	require.NotEqual(t, id, makeDraftID(DraftTypeVideo, "周末去哪玩", "2025-01-02 15:04", ""))
+	require.NotEqual(t, id, makeDraftID(DraftTypeImage, "周末去哪玩", "2025-01-02 15:05", ""))
+	require.NotEqual(t, id, makeDraftID(DraftTypeImage, "周末去哪玩", "2025-01-02 15:04", "https://example.com/a.jpg"))
+
+	// 相对时间和本地临时封面地址会变化，不影响 ID
+	relative := makeDraftID(DraftTypeImage, "周末去哪玩", "3分钟前", "blob:https://creator.xiaohongshu.com/1")
+	require.Equal(t, relative, makeDraftID(DraftTypeImage, "周末去哪玩", "昨天 12:00", "blob:https://creator.xiaohongshu.com/2"))
+}
+
+func TestFindSavedDraft(t *testing.T) {
+	before := []Draft{
+		{DraftID: "a", Title: "周末去哪玩"},
+		{DraftID: "b", Title: "一篇标题特别长的笔记会被..."},
+	}
+
+	// 已有同名草稿，草稿箱没有变化时不能确认保存成功
+	_, ok := findSavedDraft(before, before, "周末去哪玩")
+	require.False(t, ok)
+
+	after := append([]Draft{{DraftID: "c", Title: "周末去哪玩"}}, before...)
+	draft, ok := findSavedDraft(before, after, "周末去哪玩")
+	require.True(t, ok)
+	require.Equal(t, "c", draft.DraftID)
+
+	// 截断的标题按前缀匹配
+	after = append([]Draft{{DraftID: "d", Title: "一篇标题特别长的笔记会被..."}}, before...)
+	draft, ok = findSavedDraft(before, after, "一篇标题特别长的笔记会被截断显示")
+	require.True(t, ok)
+	require.Equal(t, "d", draft.DraftID)
+
+	_, ok = findSavedDraft(nil, before, "周末去哪")
+	require.False(t, ok)
+}
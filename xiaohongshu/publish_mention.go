@@ -0,0 +1,179 @@
+package xiaohongshu
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// @用户和地点相关选择器
+const (
+	selectorMentionContainer = "#creator-editor-mention-container, .mention-container"
+	selectorMentionItems     = ".item, .mention-item"
+	selectorLocationOptions  = ".d-select-dropdown .d-option, .d-options-wrapper .d-option, .location-item"
+)
+
+// mentionCandidate @下拉框中的候选用户
+type mentionCandidate struct {
+	Nickname string
+	RedID    string // 小红书号
+}
+
+// normalizeMention 去掉首尾空白和开头的 @
+func normalizeMention(mention string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(mention), "@＠"))
+}
+
+// matchMention 候选用户的昵称或小红书号与 mention 完全一致时匹配
+func matchMention(mention string, candidate mentionCandidate) bool {
+	if mention == "" {
+		return false
+	}
+	return candidate.Nickname == mention || (candidate.RedID != "" && candidate.RedID == mention)
+}
+
+// inputMentions 在正文末尾通过编辑器的 @ 下拉框插入提及用户，返回未能匹配的用户
+func inputMentions(contentElem *rod.Element, mentions []string) []string {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	var unresolved []string
+	for _, mention := range mentions {
+		name := normalizeMention(mention)
+		if name == "" {
+			continue
+		}
+		if !inputMention(contentElem, name) {
+			unresolved = append(unresolved, mention)
+		}
+	}
+
+	return unresolved
+}
+
+// inputMention 输入 @ 和用户名，在下拉框中选择匹配的用户。未匹配时删除已输入的文本。
+func inputMention(contentElem *rod.Element, name string) bool {
+	contentElem.MustInput(" @")
+	time.Sleep(200 * time.Millisecond)
+
+	for _, char := range name {
+		contentElem.MustInput(string(char))
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+
+	if item := findMentionItem(contentElem.Page(), name); item != nil {
+		item.MustClick()
+		slog.Info("成功选择@用户", "mention", name)
+		time.Sleep(300 * time.Millisecond)
+		return true
+	}
+
+	logrus.Warnf("没有找到匹配的@用户: %s", name)
+
+	// 删除已输入的 “ @用户名”
+	keys := contentElem.MustKeyActions()
+	for range []rune(" @" + name) {
+		keys = keys.Type(input.Backspace)
+	}
+	keys.MustDo()
+	time.Sleep(300 * time.Millisecond)
+
+	return false
+}
+
+// findMentionItem 在 @ 下拉框中查找昵称或小红书号匹配的用户
+func findMentionItem(page *rod.Page, name string) *rod.Element {
+	container, err := page.Element(selectorMentionContainer)
+	if err != nil || container == nil {
+		return nil
+	}
+
+	items, err := container.Elements(selectorMentionItems)
+	if err != nil {
+		return nil
+	}
+
+	for _, item := range items {
+		if matchMention(name, readMentionCandidate(item)) {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// readMentionCandidate 读取候选用户的昵称和小红书号
+func readMentionCandidate(item *rod.Element) mentionCandidate {
+	var c mentionCandidate
+
+	if elem, err := item.Element(".name, .nickname"); err == nil {
+		if text, err := elem.Text(); err == nil {
+			c.Nickname = strings.TrimSpace(text)
+		}
+	}
+
+	if elem, err := item.Element(".red-id, .user-id, .desc"); err == nil {
+		if text, err := elem.Text(); err == nil {
+			c.RedID = parseRedID(text)
+		}
+	}
+
+	return c
+}
+
+// parseRedID 从“小红书号：123456”这样的文本中提取小红书号
+func parseRedID(text string) string {
+	text = strings.TrimSpace(text)
+	for _, sep := range []string{"：", ":"} {
+		if i := strings.Index(text, sep); i >= 0 {
+			return strings.TrimSpace(text[i+len(sep):])
+		}
+	}
+	return text
+}
+
+// setLocation 通过“添加地点”选择器搜索并选择地点，返回页面显示的地点名称
+func setLocation(page *rod.Page, location string) (string, error) {
+	selectElem, err := findSelectByLabel(page, "添加地点", "添加地点")
+	if err != nil {
+		return "", err
+	}
+
+	selectElem.MustScrollIntoView()
+	selectElem.MustClick()
+	time.Sleep(500 * time.Millisecond)
+
+	searchInput, err := selectElem.Element("input")
+	if err != nil {
+		return "", errors.Wrap(err, "没有找到地点搜索框")
+	}
+	searchInput.MustInput(location)
+	time.Sleep(2 * time.Second)
+
+	option, err := findVisibleElementContainingText(page, selectorLocationOptions, location)
+	if err != nil {
+		return "", errors.Wrapf(err, "没有找到地点 %s", location)
+	}
+	option.MustClick()
+	time.Sleep(500 * time.Millisecond)
+
+	text, err := selectElem.Text()
+	if err != nil {
+		return "", errors.Wrap(err, "读取已选地点失败")
+	}
+	text = strings.TrimSpace(text)
+	if !strings.Contains(text, location) {
+		return "", errors.Errorf("选择地点 %s 后显示为 %s", location, text)
+	}
+
+	logrus.Infof("已添加地点: %s", text)
+	return text, nil
+}
@@ -0,0 +1,28 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeMention(t *testing.T) {
+	require.Equal(t, "小红薯", normalizeMention(" @小红薯 "))
+	require.Equal(t, "小红薯", normalizeMention("＠小红薯"))
+	require.Equal(t, "", normalizeMention("@"))
+}
+
+func TestMatchMention(t *testing.T) {
+	c := mentionCandidate{Nickname: "小红薯", RedID: "123456"}
+
+	require.True(t, matchMention("小红薯", c))
+	require.True(t, matchMention("123456", c))
+	require.False(t, matchMention("小红", c))
+	require.False(t, matchMention("", mentionCandidate{}))
+}
+
+func TestParseRedID(t *testing.T) {
+	require.Equal(t, "123456", parseRedID("小红书号：123456"))
+	require.Equal(t, "abc", parseRedID("小红书号: abc"))
+	require.Equal(t, "123456", parseRedID(" 123456 "))
+}
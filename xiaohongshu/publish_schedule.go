@@ -0,0 +1,130 @@
+package xiaohongshu
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 平台定时发布允许的时间窗口：1小时后至14天内
+const (
+	ScheduleMinAdvance = 1 * time.Hour
+	ScheduleMaxAdvance = 14 * 24 * time.Hour
+)
+
+// scheduleTimeLayout 定时发布时间输入框的格式
+const scheduleTimeLayout = "2006-01-02 15:04"
+
+// beijingLocation 平台展示和解析时间使用的时区
+var beijingLocation = time.FixedZone("CST", 8*60*60)
+
+// ParseScheduleTime 解析定时发布时间
+// 支持 RFC3339、"2006-01-02 15:04:05"、"2006-01-02 15:04" 和 Unix 秒级时间戳，未带时区的按北京时间处理
+func ParseScheduleTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, errors.New("定时发布时间不能为空")
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04:05", scheduleTimeLayout, "2006-01-02T15:04:05", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, beijingLocation); err == nil {
+			return t, nil
+		}
+	}
+
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+
+	return time.Time{}, errors.Errorf("无法解析定时发布时间: %s，支持格式如 2025-01-02 15:04 或 RFC3339", s)
+}
+
+// ValidateScheduleTime 校验定时发布时间是否在平台允许的窗口内
+func ValidateScheduleTime(t, now time.Time) error {
+	if t.Before(now.Add(ScheduleMinAdvance)) {
+		return errors.Errorf("定时发布时间 %s 过早，需至少在 %s 之后",
+			t.In(beijingLocation).Format(scheduleTimeLayout), ScheduleMinAdvance)
+	}
+	if t.After(now.Add(ScheduleMaxAdvance)) {
+		return errors.Errorf("定时发布时间 %s 过晚，最多只能提前 %d 天设置",
+			t.In(beijingLocation).Format(scheduleTimeLayout), int(ScheduleMaxAdvance.Hours()/24))
+	}
+	return nil
+}
+
+// setScheduleTime 打开“定时发布”开关并填写发布时间，返回页面确认的时间文本
+func setScheduleTime(page *rod.Page, t time.Time) (string, error) {
+	if err := ValidateScheduleTime(t, time.Now()); err != nil {
+		return "", err
+	}
+
+	switchElem, err := findScheduleSwitch(page)
+	if err != nil {
+		return "", err
+	}
+
+	if !isSwitchChecked(switchElem) {
+		switchElem.MustClick()
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	pp := page.Timeout(10 * time.Second)
+	timeInput, err := pp.Element(".post-time-wrapper input, .date-picker-container input, .d-datepicker input")
+	if err != nil {
+		return "", errors.Wrap(err, "没有找到定时发布时间输入框")
+	}
+
+	expected := t.In(beijingLocation).Format(scheduleTimeLayout)
+
+	timeInput.MustSelectAllText().MustInput("")
+	timeInput.MustInput(expected)
+	timeInput.MustKeyActions().Press(input.Enter).MustDo()
+	time.Sleep(500 * time.Millisecond)
+
+	// 关闭日期选择弹层
+	clickEmptyPosition(page)
+	time.Sleep(500 * time.Millisecond)
+
+	confirmed, err := timeInput.Property("value")
+	if err != nil {
+		return "", errors.Wrap(err, "读取定时发布时间失败")
+	}
+
+	confirmedText := strings.TrimSpace(confirmed.String())
+	if !strings.HasPrefix(confirmedText, expected) {
+		return "", errors.Errorf("定时发布时间设置失败，期望 %s，页面显示 %s", expected, confirmedText)
+	}
+
+	logrus.Infof("已设置定时发布: %s", confirmedText)
+	return confirmedText, nil
+}
+
+// findScheduleSwitch 查找“定时发布”开关
+func findScheduleSwitch(page *rod.Page) (*rod.Element, error) {
+	if has, elem, _ := page.Has(".post-time-wrapper .d-switch"); has {
+		return elem, nil
+	}
+
+	// 兜底：根据“定时发布”文本找到所在区域内的开关
+	return findSwitchByLabel(page, "定时发布")
+}
+
+// isSwitchChecked 开关是否已打开
+func isSwitchChecked(elem *rod.Element) bool {
+	if cls, err := elem.Attribute("class"); err == nil && cls != nil && strings.Contains(*cls, "checked") {
+		return true
+	}
+	if checked, err := elem.Property("checked"); err == nil && checked.Bool() {
+		return true
+	}
+	return false
+}
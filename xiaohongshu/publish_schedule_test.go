@@ -0,0 +1,37 @@
+package xiaohongshu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScheduleTime(t *testing.T) {
+	expected := time.Date(2025, 1, 2, 15, 4, 0, 0, beijingLocation)
+
+	for _, input := range []string{
+		"2025-01-02 15:04",
+		"2025-01-02 15:04:00",
+		"2025-01-02T15:04:00+08:00",
+		"2025-01-02T07:04:00Z",
+		"1735801440",
+	} {
+		got, err := ParseScheduleTime(input)
+		require.NoError(t, err, input)
+		require.True(t, expected.Equal(got), "%s => %s", input, got)
+	}
+
+	_, err := ParseScheduleTime("明天下午")
+	require.Error(t, err)
+}
+
+func TestValidateScheduleTime(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, beijingLocation)
+
+	require.NoError(t, ValidateScheduleTime(now.Add(2*time.Hour), now))
+	require.NoError(t, ValidateScheduleTime(now.Add(13*24*time.Hour), now))
+
+	require.Error(t, ValidateScheduleTime(now.Add(30*time.Minute), now))
+	require.Error(t, ValidateScheduleTime(now.Add(15*24*time.Hour), now))
+}
@@ -0,0 +1,272 @@
+package xiaohongshu
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 笔记可见范围
+const (
+	VisibilityPublic  = "公开"
+	VisibilityPrivate = "仅自己可见"
+	VisibilityFriends = "仅互关好友可见"
+)
+
+// 内容类型声明
+const (
+	DeclarationAI      = "笔记含AI合成内容"
+	DeclarationFiction = "虚构演绎，仅供娱乐"
+)
+
+// visibilityAliases 可见范围的可选写法
+var visibilityAliases = map[string]string{
+	"公开":      VisibilityPublic,
+	"公开可见":    VisibilityPublic,
+	"public":  VisibilityPublic,
+	"仅自己可见":   VisibilityPrivate,
+	"private": VisibilityPrivate,
+	"仅互关好友可见": VisibilityFriends,
+	"friends": VisibilityFriends,
+}
+
+// declarationAliases 内容类型声明的可选写法
+var declarationAliases = map[string]string{
+	DeclarationAI:      DeclarationAI,
+	"ai":               DeclarationAI,
+	DeclarationFiction: DeclarationFiction,
+	"fiction":          DeclarationFiction,
+}
+
+// 发布设置相关选择器
+const (
+	selectorSelectOptions = ".d-select-dropdown .d-option, .d-dropdown .d-option, .d-options-wrapper .d-option, .d-grid-item"
+)
+
+// NormalizeVisibility 规范化可见范围，空字符串表示不修改
+func NormalizeVisibility(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "", nil
+	}
+	if normalized, ok := visibilityAliases[strings.ToLower(v)]; ok {
+		return normalized, nil
+	}
+	return "", errors.Errorf("不支持的可见范围: %s，可选值: 公开|仅自己可见|仅互关好友可见", v)
+}
+
+// NormalizeDeclaration 规范化内容类型声明，空字符串表示不声明
+func NormalizeDeclaration(d string) (string, error) {
+	d = strings.TrimSpace(d)
+	if d == "" {
+		return "", nil
+	}
+	if normalized, ok := declarationAliases[strings.ToLower(d)]; ok {
+		return normalized, nil
+	}
+	return "", errors.Errorf("不支持的内容类型声明: %s，可选值: %s|%s", d, DeclarationAI, DeclarationFiction)
+}
+
+// applyPublishSettings 设置地点、可见范围、原创声明和内容类型声明，并在提交前逐项校验。
+// 地点找不到时不中断发布，记录到 result.UnresolvedLocation。
+func applyPublishSettings(page *rod.Page, form publishForm, result *PublishResult) error {
+	if form.Location != "" {
+		location, err := setLocation(page, form.Location)
+		if err != nil {
+			logrus.Warnf("添加地点失败: %v", err)
+			result.UnresolvedLocation = form.Location
+			clickEmptyPosition(page)
+		} else {
+			result.Location = location
+		}
+	}
+
+	if form.Visibility != "" && form.Visibility != VisibilityPublic {
+		if err := setVisibility(page, form.Visibility); err != nil {
+			return errors.Wrap(err, "设置可见范围失败")
+		}
+	}
+
+	if form.Original {
+		if err := setOriginal(page); err != nil {
+			return errors.Wrap(err, "设置原创声明失败")
+		}
+	}
+
+	if form.Declaration != "" {
+		if err := setContentDeclaration(page, form.Declaration); err != nil {
+			return errors.Wrap(err, "设置内容类型声明失败")
+		}
+	}
+
+	return nil
+}
+
+// setVisibility 在“权限设置”下拉框中选择可见范围
+func setVisibility(page *rod.Page, visibility string) error {
+	selectElem, err := findSelectByLabel(page, "权限设置", "公开可见")
+	if err != nil {
+		return err
+	}
+
+	if err := chooseSelectOption(page, selectElem, visibility); err != nil {
+		return err
+	}
+
+	logrus.Infof("已设置可见范围: %s", visibility)
+	return nil
+}
+
+// setOriginal 打开“原创声明”开关，处理声明确认弹窗
+func setOriginal(page *rod.Page) error {
+	switchElem, err := findSwitchByLabel(page, "原创声明")
+	if err != nil {
+		return err
+	}
+
+	if isSwitchChecked(switchElem) {
+		return nil
+	}
+
+	switchElem.MustClick()
+	time.Sleep(1 * time.Second)
+
+	// 首次声明原创会弹出须知，需要勾选同意后确认
+	if checkbox, err := page.Element(".d-modal .d-checkbox, [role=dialog] .d-checkbox, [role=dialog] input[type=checkbox]"); err == nil {
+		if visible, _ := checkbox.Visible(); visible {
+			checkbox.MustClick()
+			time.Sleep(300 * time.Millisecond)
+		}
+	}
+	for _, text := range []string{"声明原创", "确认", "确定"} {
+		if btn, err := findVisibleElementByText(page, selectorConfirmButtons, text); err == nil {
+			btn.MustClick()
+			time.Sleep(1 * time.Second)
+			break
+		}
+	}
+
+	if !isSwitchChecked(switchElem) {
+		return errors.New("原创声明开关未打开")
+	}
+
+	logrus.Info("已开启原创声明")
+	return nil
+}
+
+// setContentDeclaration 在“内容类型声明”下拉框中选择声明类型
+func setContentDeclaration(page *rod.Page, declaration string) error {
+	selectElem, err := findSelectByLabel(page, "内容类型声明", "添加内容类型声明")
+	if err != nil {
+		return err
+	}
+
+	if err := chooseSelectOption(page, selectElem, declaration); err != nil {
+		return err
+	}
+
+	logrus.Infof("已设置内容类型声明: %s", declaration)
+	return nil
+}
+
+// chooseSelectOption 展开下拉框并选择文本匹配的选项，然后校验下拉框显示的值
+func chooseSelectOption(page *rod.Page, selectElem *rod.Element, optionText string) error {
+	selectElem.MustScrollIntoView()
+	selectElem.MustClick()
+	time.Sleep(500 * time.Millisecond)
+
+	option, err := findVisibleElementContainingText(page, selectorSelectOptions, optionText)
+	if err != nil {
+		return errors.Wrapf(err, "没有找到选项 %s", optionText)
+	}
+	option.MustClick()
+	time.Sleep(500 * time.Millisecond)
+
+	text, err := selectElem.Text()
+	if err != nil {
+		return errors.Wrap(err, "读取下拉框当前值失败")
+	}
+	if !strings.Contains(text, optionText) {
+		return errors.Errorf("选择 %s 后下拉框显示为 %s", optionText, strings.TrimSpace(text))
+	}
+
+	return nil
+}
+
+// findSelectByLabel 根据标签文本或占位文本查找下拉框
+func findSelectByLabel(page *rod.Page, label, placeholder string) (*rod.Element, error) {
+	elem, err := page.ElementByJS(rod.Eval(`(label, placeholder) => {
+		const leaves = Array.from(document.querySelectorAll("span, div, label"))
+			.filter(el => el.children.length === 0);
+		for (const text of [label, placeholder]) {
+			for (const el of leaves.filter(el => el.innerText.trim().startsWith(text))) {
+				const sel = el.closest(".d-select, .d-select-wrapper");
+				if (sel) {
+					return sel;
+				}
+				let container = el.parentElement;
+				for (let i = 0; i < 4 && container; i++) {
+					const found = container.querySelector(".d-select, .d-select-wrapper");
+					if (found) {
+						return found;
+					}
+					container = container.parentElement;
+				}
+			}
+		}
+		return null;
+	}`, label, placeholder))
+	if err != nil {
+		return nil, errors.Wrapf(err, "没有找到%s下拉框", label)
+	}
+
+	return elem, nil
+}
+
+// findSwitchByLabel 根据标签文本找到所在区域内的开关
+func findSwitchByLabel(page *rod.Page, label string) (*rod.Element, error) {
+	elem, err := page.ElementByJS(rod.Eval(`(label) => {
+		const labels = Array.from(document.querySelectorAll("span, div, label"))
+			.filter(el => el.children.length === 0 && el.innerText.trim() === label);
+		for (const el of labels) {
+			let container = el.parentElement;
+			for (let i = 0; i < 4 && container; i++) {
+				const sw = container.querySelector(".d-switch, input[type=checkbox]");
+				if (sw) {
+					return sw;
+				}
+				container = container.parentElement;
+			}
+		}
+		return null;
+	}`, label))
+	if err != nil {
+		return nil, errors.Wrapf(err, "没有找到%s开关", label)
+	}
+
+	return elem, nil
+}
+
+// findVisibleElementContainingText 查找文本包含 text 的可见元素
+func findVisibleElementContainingText(page *rod.Page, selector, text string) (*rod.Element, error) {
+	elems, err := page.Elements(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, elem := range elems {
+		t, err := elem.Text()
+		if err != nil || !strings.Contains(t, text) {
+			continue
+		}
+		if !isElementVisible(elem) {
+			continue
+		}
+		return elem, nil
+	}
+
+	return nil, errors.Errorf("没有找到包含 %s 的元素", text)
+}
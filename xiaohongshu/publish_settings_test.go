@@ -0,0 +1,42 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeVisibility(t *testing.T) {
+	for input, expected := range map[string]string{
+		"":        "",
+		"公开":      VisibilityPublic,
+		"公开可见":    VisibilityPublic,
+		"Private": VisibilityPrivate,
+		"仅自己可见":   VisibilityPrivate,
+		"friends": VisibilityFriends,
+	} {
+		got, err := NormalizeVisibility(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, got, input)
+	}
+
+	_, err := NormalizeVisibility("粉丝可见")
+	require.Error(t, err)
+}
+
+func TestNormalizeDeclaration(t *testing.T) {
+	got, err := NormalizeDeclaration("AI")
+	require.NoError(t, err)
+	require.Equal(t, DeclarationAI, got)
+
+	got, err = NormalizeDeclaration(DeclarationFiction)
+	require.NoError(t, err)
+	require.Equal(t, DeclarationFiction, got)
+
+	got, err = NormalizeDeclaration("")
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	_, err = NormalizeDeclaration("广告")
+	require.Error(t, err)
+}
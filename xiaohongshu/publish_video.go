@@ -14,10 +14,18 @@ import (
 
 // PublishVideoContent 发布视频内容
 type PublishVideoContent struct {
-	Title     string
-	Content   string
-	Tags      []string
-	VideoPath string
+	Title        string
+	Content      string
+	Tags         []string
+	VideoPath    string
+	ScheduleTime *time.Time  // 定时发布时间，为空则立即发布
+	Draft        bool        // 仅保存到草稿箱，不发布
+	Visibility   string      // 可见范围，为空则保持默认（公开）
+	Original     bool        // 是否声明原创
+	Declaration  string      // 内容类型声明，如“笔记含AI合成内容”
+	Mentions     []string    // 需要 @ 的用户昵称或小红书号
+	Location     string      // 地点名称
+	Cover        *VideoCover // 自定义封面，为空则使用平台默认封面
 }
 
 // NewPublishVideoAction 进入发布页并切换到“上传视频”
@@ -37,21 +45,44 @@ func NewPublishVideoAction(page *rod.Page) (*PublishAction, error) {
 }
 
 // PublishVideo 上传视频并提交
-func (p *PublishAction) PublishVideo(ctx context.Context, content PublishVideoContent) error {
+func (p *PublishAction) PublishVideo(ctx context.Context, content PublishVideoContent) (*PublishResult, error) {
 	if content.VideoPath == "" {
-		return errors.New("视频不能为空")
+		return nil, errors.New("视频不能为空")
 	}
 
 	page := p.page.Context(ctx)
 
+	// 草稿模式先记录草稿箱中已有的草稿，保存后对比确认新增了本次的草稿
+	var draftsBefore []Draft
+	if content.Draft {
+		var err error
+		if draftsBefore, err = snapshotDrafts(page, DraftTypeVideo); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := uploadVideo(page, content.VideoPath); err != nil {
-		return errors.Wrap(err, "小红书上传视频失败")
+		return nil, errors.Wrap(err, "小红书上传视频失败")
 	}
 
-	if err := submitPublishVideo(page, content.Title, content.Content, content.Tags); err != nil {
-		return errors.Wrap(err, "小红书发布失败")
+	result, err := submitPublishVideo(page, publishForm{
+		Title:        content.Title,
+		Content:      content.Content,
+		Tags:         content.Tags,
+		ScheduleTime: content.ScheduleTime,
+		Draft:        content.Draft,
+		Visibility:   content.Visibility,
+		Original:     content.Original,
+		Declaration:  content.Declaration,
+		Mentions:     content.Mentions,
+		Location:     content.Location,
+		Cover:        content.Cover,
+		DraftsBefore: draftsBefore,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书发布失败")
 	}
-	return nil
+	return result, nil
 }
 
 // uploadVideo 上传单个本地视频
@@ -116,33 +147,75 @@ func waitForPublishButtonClickable(page *rod.Page) (*rod.Element, error) {
 }
 
 // submitPublishVideo 填写标题、正文、标签并点击发布（等待按钮可点击后再提交）
-func submitPublishVideo(page *rod.Page, title, content string, tags []string) error {
+func submitPublishVideo(page *rod.Page, form publishForm) (*PublishResult, error) {
 	// 标题
 	titleElem := page.MustElement("div.d-input input")
-	titleElem.MustInput(title)
+	titleElem.MustInput(form.Title)
 	time.Sleep(1 * time.Second)
 
-	// 正文 + 标签
+	result := &PublishResult{}
+
+	// 正文 + @用户 + 标签
 	if contentElem, ok := getContentElement(page); ok {
-		contentElem.MustInput(content)
-		inputTags(contentElem, tags)
+		contentElem.MustInput(form.Content)
+		result.UnresolvedMentions = inputMentions(contentElem, form.Mentions)
+		inputTags(contentElem, form.Tags)
 	} else {
-		return errors.New("没有找到内容输入框")
+		return nil, errors.New("没有找到内容输入框")
 	}
 
 	time.Sleep(1 * time.Second)
 
-	// 等待发布按钮可点击
+	// 地点、可见范围、原创和内容类型声明
+	if err := applyPublishSettings(page, form, result); err != nil {
+		return nil, err
+	}
+
+	// 封面
+	if form.Cover != nil {
+		if err := setVideoCover(page, *form.Cover); err != nil {
+			return nil, errors.Wrap(err, "设置视频封面失败")
+		}
+	}
+
+	// 定时发布
+	if form.ScheduleTime != nil {
+		scheduledAt, err := setScheduleTime(page, *form.ScheduleTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "设置定时发布失败")
+		}
+		result.ScheduledAt = scheduledAt
+	}
+
+	// 等待视频上传和处理完成，未处理完的视频无法完整保存到草稿箱
 	btn, err := waitForPublishButtonClickable(page)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// 草稿模式：暂存离开，不发布
+	if form.Draft {
+		if _, err := saveDraft(page, DraftTypeVideo, form.Title, form.DraftsBefore); err != nil {
+			return nil, err
+		}
+		result.Draft = true
+		return result, nil
 	}
 
-	// 点击发布
+	watcher := watchPublish(page)
+	defer watcher.stop()
+
+	// 点击发布。点击报错时事件可能已经发出，无法确认是否已发布
 	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
-		return errors.Wrap(err, "点击发布按钮失败")
+		return nil, errors.Wrapf(ErrPublishUnconfirmed, "点击发布按钮失败: %v", err)
 	}
 
-	time.Sleep(3 * time.Second)
-	return nil
+	// 确认发布成功
+	conf, err := watcher.wait(publishConfirmTimeout)
+	if err != nil {
+		return nil, err
+	}
+	conf.apply(result)
+
+	return result, nil
 }
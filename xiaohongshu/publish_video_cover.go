@@ -0,0 +1,201 @@
+package xiaohongshu
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// VideoCover 视频封面设置，Frame 和 ImagePath 二选一
+type VideoCover struct {
+	Frame     *time.Duration // 从视频中截取该时间点的画面作为封面
+	ImagePath string         // 上传本地图片作为封面
+}
+
+// 封面编辑相关选择器
+const (
+	selectorCoverModal     = ".d-modal, [role=dialog], .cover-modal"
+	selectorCoverTimeline  = ".frame-list, .cover-timeline, .d-slider, .video-frames"
+	selectorCoverFileInput = "input[type=file][accept*=image], input[type=file]"
+)
+
+// ParseCoverTime 解析封面截取时间，支持秒数（3、3.5）、Go 时长（1m20s）和 mm:ss / hh:mm:ss
+func ParseCoverTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("封面时间不能为空")
+	}
+
+	if sec, err := strconv.ParseFloat(s, 64); err == nil {
+		if sec < 0 {
+			return 0, errors.Errorf("封面时间不能为负数: %s", s)
+		}
+		return time.Duration(sec * float64(time.Second)), nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			return 0, errors.Errorf("封面时间不能为负数: %s", s)
+		}
+		return d, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) == 2 || len(parts) == 3 {
+		var total float64
+		for _, part := range parts {
+			v, err := strconv.ParseFloat(part, 64)
+			if err != nil || v < 0 {
+				return 0, errors.Errorf("无法解析封面时间: %s", s)
+			}
+			total = total*60 + v
+		}
+		return time.Duration(total * float64(time.Second)), nil
+	}
+
+	return 0, errors.Errorf("无法解析封面时间: %s，支持格式如 3.5、1m20s、01:20", s)
+}
+
+// setVideoCover 打开封面编辑弹窗，截取指定画面或上传图片作为封面，并确认弹窗已关闭
+func setVideoCover(page *rod.Page, cover VideoCover) error {
+	if cover.Frame == nil && cover.ImagePath == "" {
+		return nil
+	}
+
+	if err := openCoverEditor(page); err != nil {
+		return err
+	}
+
+	modal, err := page.Timeout(10 * time.Second).Element(selectorCoverModal)
+	if err != nil {
+		return errors.Wrap(err, "没有找到封面编辑弹窗")
+	}
+
+	if cover.ImagePath != "" {
+		err = uploadCoverImage(page, modal, cover.ImagePath)
+	} else {
+		err = selectCoverFrame(modal, *cover.Frame)
+	}
+	if err != nil {
+		return err
+	}
+
+	confirmed := false
+	for _, text := range []string{"确定", "确认", "完成"} {
+		if btn, err := findVisibleElementByText(page, selectorConfirmButtons, text); err == nil {
+			btn.MustClick()
+			confirmed = true
+			break
+		}
+	}
+	if !confirmed {
+		return errors.New("没有找到封面编辑弹窗的确定按钮")
+	}
+
+	time.Sleep(2 * time.Second)
+
+	if visible, _ := modal.Visible(); visible {
+		return errors.New("封面编辑弹窗未关闭，封面可能未设置成功")
+	}
+
+	logrus.Info("视频封面设置完成")
+	return nil
+}
+
+// openCoverEditor 点击封面区域的“设置封面/修改封面”按钮
+func openCoverEditor(page *rod.Page) error {
+	for _, text := range []string{"设置封面", "修改封面", "编辑封面", "选择封面"} {
+		if btn, err := findVisibleElementContainingText(page, ".cover-container span, .cover-container div, .coverContainer div, button, span", text); err == nil {
+			btn.MustScrollIntoView()
+			btn.MustClick()
+			time.Sleep(1 * time.Second)
+			return nil
+		}
+	}
+
+	return errors.New("没有找到设置封面按钮")
+}
+
+// uploadCoverImage 在封面编辑弹窗中上传本地图片
+func uploadCoverImage(page *rod.Page, modal *rod.Element, imagePath string) error {
+	if _, err := os.Stat(imagePath); err != nil {
+		return errors.Wrapf(err, "封面图片不存在: %s", imagePath)
+	}
+
+	// 部分版本需要先切换到“上传封面”
+	if tab, err := findVisibleElementContainingText(page, selectorCoverModal+" span, "+selectorCoverModal+" div", "上传封面"); err == nil {
+		tab.MustClick()
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	fileInput, err := modal.Element(selectorCoverFileInput)
+	if err != nil {
+		return errors.Wrap(err, "没有找到封面上传输入框")
+	}
+	fileInput.MustSetFiles(imagePath)
+
+	// 等待图片加载到预览区
+	time.Sleep(3 * time.Second)
+
+	logrus.Infof("已上传封面图片: %s", imagePath)
+	return nil
+}
+
+// selectCoverFrame 在封面编辑弹窗中定位到视频的指定时间点
+func selectCoverFrame(modal *rod.Element, frame time.Duration) error {
+	video, err := modal.Element("video")
+	if err != nil {
+		return errors.Wrap(err, "没有找到封面编辑视频")
+	}
+
+	durationVal, err := video.Eval(`() => this.duration`)
+	if err != nil {
+		return errors.Wrap(err, "读取视频时长失败")
+	}
+	duration := durationVal.Value.Num()
+	target := frame.Seconds()
+	if math.IsNaN(duration) || duration <= 0 {
+		return errors.New("视频时长未知，无法选择封面画面")
+	}
+	if target > duration {
+		return errors.Errorf("封面时间 %.1fs 超过视频时长 %.1fs", target, duration)
+	}
+
+	// 优先点击时间轴对应位置，让编辑器记录选中的画面
+	if timeline, err := modal.Element(selectorCoverTimeline); err == nil {
+		if shape, err := timeline.Shape(); err == nil && len(shape.Quads) > 0 {
+			box := shape.Box()
+			x := box.X + box.Width*target/duration
+			y := box.Y + box.Height/2
+			page := modal.Page()
+			page.Mouse.MustMoveTo(x, y).MustClick(proto.InputMouseButtonLeft)
+			time.Sleep(1 * time.Second)
+		}
+	}
+
+	// 兜底：直接定位视频，并校验当前时间
+	current, err := video.Eval(`(t) => {
+		if (Math.abs(this.currentTime - t) > 0.5) {
+			this.currentTime = t;
+		}
+		return this.currentTime;
+	}`, target)
+	if err != nil {
+		return errors.Wrap(err, "定位封面画面失败")
+	}
+	time.Sleep(1 * time.Second)
+
+	if math.Abs(current.Value.Num()-target) > 1 {
+		return errors.Errorf("封面画面定位到 %.1fs，期望 %.1fs", current.Value.Num(), target)
+	}
+
+	logrus.Infof("已选择 %.1fs 处的画面作为封面", target)
+	return nil
+}
@@ -0,0 +1,28 @@
+package xiaohongshu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoverTime(t *testing.T) {
+	for input, expected := range map[string]time.Duration{
+		"3":        3 * time.Second,
+		"3.5":      3500 * time.Millisecond,
+		"1m20s":    80 * time.Second,
+		"01:20":    80 * time.Second,
+		"00:01:20": 80 * time.Second,
+		"0":        0,
+	} {
+		got, err := ParseCoverTime(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, got, input)
+	}
+
+	for _, input := range []string{"", "-1", "abc", "1:xx"} {
+		_, err := ParseCoverTime(input)
+		require.Error(t, err, input)
+	}
+}
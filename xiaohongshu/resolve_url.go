@@ -0,0 +1,168 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 链接解析结果类型
+const (
+	ResolvedTypeNote = "note"
+	ResolvedTypeUser = "user"
+)
+
+// ResolvedURL 分享链接/笔记链接解析结果
+type ResolvedURL struct {
+	URL       string `json:"url"`  // 跳转后的最终链接
+	Type      string `json:"type"` // note | user
+	FeedID    string `json:"feed_id,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+	XsecToken string `json:"xsec_token,omitempty"`
+}
+
+var (
+	// shareURLPattern 从分享文案中提取链接，如 "xxx http://xhslink.com/a/abc，复制本条信息..."
+	shareURLPattern = regexp.MustCompile(`https?://[A-Za-z0-9\-._~:/?#\[\]@!$&'()*+,;=%]+`)
+
+	notePathPattern = regexp.MustCompile(`^/(?:explore|discovery/item)/([0-9a-zA-Z]+)/?$`)
+	userPathPattern = regexp.MustCompile(`^/user/profile/([0-9a-zA-Z]+)/?$`)
+)
+
+// ExtractShareURL 从用户粘贴的文本中提取第一个链接，文本本身就是链接时原样返回
+func ExtractShareURL(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("链接不能为空")
+	}
+
+	link := shareURLPattern.FindString(text)
+	if link == "" {
+		// 兼容省略协议头的写法，如 xhslink.com/a/abc
+		if strings.HasPrefix(text, "xhslink.com/") || strings.HasPrefix(text, "www.xiaohongshu.com/") {
+			return "https://" + text, nil
+		}
+		return "", errors.Errorf("未找到有效链接: %s", text)
+	}
+
+	// 去掉中文文案里紧跟在链接后面的标点
+	return strings.TrimRight(link, ",.;!?)]"), nil
+}
+
+// IsShortShareURL 是否为需要跳转的 xhslink.com 短链接
+func IsShortShareURL(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "xhslink.com" || strings.HasSuffix(host, ".xhslink.com")
+}
+
+// ParseNoteURL 解析 xiaohongshu.com 的笔记或用户主页链接
+// 支持 /explore/{id}、/discovery/item/{id} 以及 /user/profile/{id}
+func ParseNoteURL(link string) (*ResolvedURL, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, errors.Wrap(err, "解析链接失败")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host != "xiaohongshu.com" && !strings.HasSuffix(host, ".xiaohongshu.com") {
+		return nil, errors.Errorf("不是小红书链接: %s", link)
+	}
+
+	result := &ResolvedURL{
+		URL:       link,
+		XsecToken: u.Query().Get("xsec_token"),
+	}
+
+	if m := notePathPattern.FindStringSubmatch(u.Path); m != nil {
+		result.Type = ResolvedTypeNote
+		result.FeedID = m[1]
+		return result, nil
+	}
+
+	if m := userPathPattern.FindStringSubmatch(u.Path); m != nil {
+		result.Type = ResolvedTypeUser
+		result.UserID = m[1]
+		return result, nil
+	}
+
+	return nil, errors.Errorf("无法识别的小红书链接: %s", link)
+}
+
+// ResolveURLAction 通过浏览器跟随跳转解析分享链接
+type ResolveURLAction struct {
+	page *rod.Page
+}
+
+// NewResolveURLAction 创建链接解析动作
+func NewResolveURLAction(page *rod.Page) *ResolveURLAction {
+	return &ResolveURLAction{page: page}
+}
+
+// Resolve 打开链接并跟随跳转，从最终地址中提取笔记/用户 ID 和 xsec_token
+func (r *ResolveURLAction) Resolve(ctx context.Context, rawURL string) (*ResolvedURL, error) {
+	link, err := ExtractShareURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	page := r.page.Context(ctx).Timeout(60 * time.Second)
+
+	logrus.Infof("打开链接以解析跳转: %s", link)
+
+	page.MustNavigate(link)
+	page.MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	finalURL := page.MustInfo().URL
+	logrus.Infof("链接跳转后的地址: %s", finalURL)
+
+	result, err := ParseNoteURL(finalURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// 跳转后的链接可能不带 xsec_token，尝试从页面状态中读取
+	if result.XsecToken == "" && result.Type == ResolvedTypeNote {
+		result.XsecToken = getNoteXsecTokenFromState(page, result.FeedID)
+	}
+
+	return result, nil
+}
+
+// getNoteXsecTokenFromState 从 __INITIAL_STATE__ 中读取笔记的 xsecToken
+func getNoteXsecTokenFromState(page *rod.Page, feedID string) string {
+	result := page.MustEval(`() => {
+		if (window.__INITIAL_STATE__ &&
+		    window.__INITIAL_STATE__.note &&
+		    window.__INITIAL_STATE__.note.noteDetailMap) {
+			return JSON.stringify(window.__INITIAL_STATE__.note.noteDetailMap);
+		}
+		return "";
+	}`).String()
+	if result == "" {
+		return ""
+	}
+
+	var noteDetailMap map[string]struct {
+		Note struct {
+			XsecToken string `json:"xsecToken"`
+		} `json:"note"`
+	}
+	if err := json.Unmarshal([]byte(result), &noteDetailMap); err != nil {
+		logrus.Warnf("解析 noteDetailMap 失败: %v", err)
+		return ""
+	}
+
+	return noteDetailMap[feedID].Note.XsecToken
+}
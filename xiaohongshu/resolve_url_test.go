@@ -0,0 +1,61 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractShareURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://www.xiaohongshu.com/explore/abc123?xsec_token=tk", "https://www.xiaohongshu.com/explore/abc123?xsec_token=tk"},
+		{"58 小红书 好看的封面 http://xhslink.com/a/AbCdEf，复制本条信息，打开【小红书】App查看精彩内容！", "http://xhslink.com/a/AbCdEf"},
+		{"xhslink.com/a/AbCdEf", "https://xhslink.com/a/AbCdEf"},
+	}
+
+	for _, test := range tests {
+		link, err := ExtractShareURL(test.input)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, link)
+	}
+
+	_, err := ExtractShareURL("没有链接的文本")
+	require.Error(t, err)
+}
+
+func TestParseNoteURL(t *testing.T) {
+	// 笔记链接
+	result, err := ParseNoteURL("https://www.xiaohongshu.com/explore/64f1a2b3000000001e02c3d4?xsec_token=ABtoken%3D&xsec_source=pc_feed")
+	require.NoError(t, err)
+	require.Equal(t, ResolvedTypeNote, result.Type)
+	require.Equal(t, "64f1a2b3000000001e02c3d4", result.FeedID)
+	require.Equal(t, "ABtoken=", result.XsecToken)
+
+	// discovery 链接
+	result, err = ParseNoteURL("https://www.xiaohongshu.com/discovery/item/64f1a2b3000000001e02c3d4?app_platform=ios&xsec_token=tk2")
+	require.NoError(t, err)
+	require.Equal(t, ResolvedTypeNote, result.Type)
+	require.Equal(t, "64f1a2b3000000001e02c3d4", result.FeedID)
+	require.Equal(t, "tk2", result.XsecToken)
+
+	// 用户主页链接
+	result, err = ParseNoteURL("https://www.xiaohongshu.com/user/profile/5ff0e6410000000001008400?xsec_token=utk&xsec_source=pc_note")
+	require.NoError(t, err)
+	require.Equal(t, ResolvedTypeUser, result.Type)
+	require.Equal(t, "5ff0e6410000000001008400", result.UserID)
+	require.Equal(t, "utk", result.XsecToken)
+
+	// 非小红书链接
+	_, err = ParseNoteURL("https://example.com/explore/abc")
+	require.Error(t, err)
+
+	// 无法识别的路径
+	_, err = ParseNoteURL("https://www.xiaohongshu.com/search_result?keyword=abc")
+	require.Error(t, err)
+
+	require.True(t, IsShortShareURL("http://xhslink.com/a/AbCdEf"))
+	require.False(t, IsShortShareURL("https://www.xiaohongshu.com/explore/abc"))
+}
@@ -38,10 +38,11 @@ type NoteCard struct {
 
 // User 表示用户信息
 type User struct {
-	UserID   string `json:"userId"`
-	Nickname string `json:"nickname"`
-	NickName string `json:"nickName"`
-	Avatar   string `json:"avatar"`
+	UserID    string `json:"userId"`
+	Nickname  string `json:"nickname"`
+	NickName  string `json:"nickName"`
+	Avatar    string `json:"avatar"`
+	XsecToken string `json:"xsecToken,omitempty"`
 }
 
 // InteractInfo 表示互动信息
@@ -103,6 +104,7 @@ type FeedDetail struct {
 	User         User              `json:"user"`
 	InteractInfo InteractInfo      `json:"interactInfo"`
 	ImageList    []DetailImageInfo `json:"imageList"`
+	DescParsed   *ParsedDesc       `json:"descParsed,omitempty"` // 解析 Desc 得到的纯文本、话题、提及和表情
 }
 
 // DetailImageInfo 表示详情页的图片信息
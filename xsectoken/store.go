@@ -0,0 +1,174 @@
+package xsectoken
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 令牌所属对象类型
+const (
+	KindNote = "note"
+	KindUser = "user"
+)
+
+// DefaultMaxAge 令牌默认有效期，超过后视为过期不再使用
+const DefaultMaxAge = 24 * time.Hour
+
+var (
+	ErrTokenNotFound = errors.New("未找到 xsec_token 缓存")
+	ErrTokenExpired  = errors.New("xsec_token 缓存已过期")
+)
+
+// Entry 一条 xsec_token 记录
+type Entry struct {
+	Kind      string    `json:"kind"`
+	ID        string    `json:"id"`
+	XsecToken string    `json:"xsec_token"`
+	Source    string    `json:"source"` // 令牌来源，如 list_feeds、search_feeds
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Age 令牌距最近一次记录的时长
+func (e Entry) Age() time.Duration {
+	return time.Since(e.UpdatedAt)
+}
+
+// Store 持久化的 xsec_token 缓存，按笔记/用户 ID 索引
+type Store struct {
+	path    string
+	maxAge  time.Duration
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+// NewStore 创建令牌缓存，并从 path 加载已有记录
+func NewStore(path string, maxAge time.Duration) *Store {
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	s := &Store{
+		path:    path,
+		maxAge:  maxAge,
+		entries: make(map[string]Entry),
+	}
+
+	if err := s.load(); err != nil {
+		logrus.Warnf("failed to load xsec tokens: %v", err)
+	}
+
+	return s
+}
+
+// Record 记录若干令牌，空 ID 或空令牌会被忽略
+func (s *Store) Record(entries ...Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	now := time.Now()
+	for _, e := range entries {
+		if e.ID == "" || e.XsecToken == "" {
+			continue
+		}
+		e.UpdatedAt = now
+		s.entries[makeKey(e.Kind, e.ID)] = e
+		changed = true
+	}
+
+	if !changed {
+		return
+	}
+
+	if err := s.save(); err != nil {
+		logrus.Warnf("failed to save xsec tokens: %v", err)
+	}
+}
+
+// Get 获取未过期的令牌
+func (s *Store) Get(kind, id string) (Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries[makeKey(kind, id)]
+	if !ok {
+		return Entry{}, ErrTokenNotFound
+	}
+
+	if e.Age() > s.maxAge {
+		return e, ErrTokenExpired
+	}
+
+	return e, nil
+}
+
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to read xsec tokens file")
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return errors.Wrap(err, "failed to unmarshal xsec tokens")
+	}
+
+	for _, e := range entries {
+		s.entries[makeKey(e.Kind, e.ID)] = e
+	}
+
+	return nil
+}
+
+// save 写入文件，调用方需持有锁。过期记录在写入时清理。
+func (s *Store) save() error {
+	entries := make([]Entry, 0, len(s.entries))
+	for key, e := range s.entries {
+		if e.Age() > s.maxAge {
+			delete(s.entries, key)
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	// 先写临时文件再重命名，避免写入中断时留下不完整的缓存文件
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func makeKey(kind, id string) string {
+	return kind + ":" + id
+}
+
+// GetStoreFilePath 获取令牌缓存文件路径。
+// 优先使用环境变量 XSEC_TOKENS_PATH，否则使用当前目录下的 xsec_tokens.json
+func GetStoreFilePath() string {
+	path := os.Getenv("XSEC_TOKENS_PATH")
+	if path == "" {
+		path = "xsec_tokens.json"
+	}
+	return path
+}
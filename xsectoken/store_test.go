@@ -0,0 +1,54 @@
+package xsectoken
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreRecordAndGet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "xsec_tokens.json")
+
+	store := NewStore(path, time.Hour)
+	store.Record(
+		Entry{Kind: KindNote, ID: "note1", XsecToken: "tk1", Source: "search_feeds"},
+		Entry{Kind: KindUser, ID: "user1", XsecToken: "utk1", Source: "search_feeds"},
+		Entry{Kind: KindNote, ID: "note2", XsecToken: ""}, // 空令牌忽略
+	)
+
+	e, err := store.Get(KindNote, "note1")
+	require.NoError(t, err)
+	require.Equal(t, "tk1", e.XsecToken)
+	require.Equal(t, "search_feeds", e.Source)
+
+	_, err = store.Get(KindNote, "note2")
+	require.ErrorIs(t, err, ErrTokenNotFound)
+
+	// 同一 ID 的笔记和用户互不影响
+	_, err = store.Get(KindUser, "note1")
+	require.ErrorIs(t, err, ErrTokenNotFound)
+
+	// 重新加载后数据仍在
+	reloaded := NewStore(path, time.Hour)
+	e, err = reloaded.Get(KindUser, "user1")
+	require.NoError(t, err)
+	require.Equal(t, "utk1", e.XsecToken)
+
+	// 保存通过临时文件重命名完成，不留下临时文件
+	require.NoFileExists(t, path+".tmp")
+}
+
+func TestStoreExpired(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "xsec_tokens.json"), time.Hour)
+	store.entries[makeKey(KindNote, "old")] = Entry{
+		Kind:      KindNote,
+		ID:        "old",
+		XsecToken: "tk",
+		UpdatedAt: time.Now().Add(-2 * time.Hour),
+	}
+
+	_, err := store.Get(KindNote, "old")
+	require.ErrorIs(t, err, ErrTokenExpired)
+}